@@ -30,6 +30,9 @@ func main() {
 	application := app.New(ctx, log, cfg)
 
 	go application.HTTPSrv.Run()
+	go application.Scheduler.Run()
+	go application.KeyRotation.Run()
+	go application.TrashPurge.Run()
 
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
@@ -39,6 +42,9 @@ func main() {
 	log.Info("stopping application", slog.String("signal", sign.String()))
 
 	application.HTTPSrv.Stop()
+	application.Scheduler.Stop()
+	application.KeyRotation.Stop()
+	application.TrashPurge.Stop()
 
 	log.Info("application stopped")
 }
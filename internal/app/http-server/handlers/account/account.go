@@ -0,0 +1,395 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/password"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Account
+type Account interface {
+	Profile(ctx context.Context, userId int64) (models.Profile, error)
+	UpdateProfile(ctx context.Context, userId int64, input models.UpdateProfileInput) (models.Profile, error)
+	ChangePassword(ctx context.Context, userId int64, currentPassword string, newPassword string) error
+	ChangeEmail(ctx context.Context, userId int64, password string, newEmail string) error
+	DeleteAccount(ctx context.Context, userId int64, password string) error
+}
+
+type AccountHandler struct {
+	ctx     context.Context
+	log     *slog.Logger
+	account Account
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	account Account,
+) *AccountHandler {
+	return &AccountHandler{
+		ctx:     ctx,
+		log:     log,
+		account: account,
+	}
+}
+
+type UpdateRequest struct {
+	DisplayName *string `json:"display_name,omitempty" validate:"omitnil,max=100"`
+	Timezone    *string `json:"timezone,omitempty" validate:"omitnil,min=1"`
+	Locale      *string `json:"locale,omitempty" validate:"omitnil,min=1"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type ChangeEmailRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type DeleteRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type ProfileResponse struct {
+	resp.Response
+	Profile models.Profile `json:"profile"`
+}
+
+func (h *AccountHandler) Profile(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.account.Profile"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, ok := h.userId(w, r, log)
+	if !ok {
+		return
+	}
+
+	profile, err := h.account.Profile(h.ctx, userId)
+	if err != nil {
+		if errors.Is(err, authService.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("user not found"))
+
+			return
+		}
+
+		log.Error("failed to get profile", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get profile"))
+
+		return
+	}
+
+	render.JSON(w, r, ProfileResponse{
+		Response: resp.OK("Profile found"),
+		Profile:  profile,
+	})
+}
+
+func (h *AccountHandler) Update(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.account.Update"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req UpdateRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, ok := h.userId(w, r, log)
+	if !ok {
+		return
+	}
+
+	input := models.UpdateProfileInput{
+		DisplayName: req.DisplayName,
+		Timezone:    req.Timezone,
+		Locale:      req.Locale,
+	}
+
+	profile, err := h.account.UpdateProfile(h.ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, authService.ErrInvalidTimezone) {
+			log.Warn("invalid timezone", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid timezone"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidLocale) {
+			log.Warn("invalid locale", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid locale"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("user not found"))
+
+			return
+		}
+
+		log.Error("failed to update profile", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to update profile"))
+
+		return
+	}
+
+	log.Info("profile updated", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, ProfileResponse{
+		Response: resp.OK("Profile successfully updated"),
+		Profile:  profile,
+	})
+}
+
+func (h *AccountHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.account.ChangePassword"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req ChangePasswordRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, ok := h.userId(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.account.ChangePassword(h.ctx, userId, req.CurrentPassword, req.NewPassword); err != nil {
+		if reauthFailed(w, r, log, err) {
+			return
+		}
+
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			log.Warn("weak password", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(policyErr.Error()))
+
+			return
+		}
+
+		log.Error("failed to change password", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to change password"))
+
+		return
+	}
+
+	log.Info("password changed", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Password successfully changed, sign in again on your other devices"))
+}
+
+func (h *AccountHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.account.ChangeEmail"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req ChangeEmailRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, ok := h.userId(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.account.ChangeEmail(h.ctx, userId, req.Password, req.Email); err != nil {
+		if reauthFailed(w, r, log, err) {
+			return
+		}
+		if errors.Is(err, authService.ErrUserExists) {
+			log.Warn("email is already taken", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("email is already taken"))
+
+			return
+		}
+
+		log.Error("failed to change email", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to change email"))
+
+		return
+	}
+
+	log.Info("email change requested", slog.Int64("user_id", userId))
+
+	w.WriteHeader(http.StatusAccepted)
+	render.JSON(w, r, resp.OK("Follow the link sent to the new email to confirm it"))
+}
+
+func (h *AccountHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.account.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req DeleteRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, ok := h.userId(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.account.DeleteAccount(h.ctx, userId, req.Password); err != nil {
+		if reauthFailed(w, r, log, err) {
+			return
+		}
+		if errors.Is(err, authService.ErrWorkspaceOwned) {
+			log.Warn("shared workspace has no admin", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("make someone an admin of your shared workspaces first"))
+
+			return
+		}
+
+		log.Error("failed to delete account", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to delete account"))
+
+		return
+	}
+
+	log.Info("account deleted", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Account successfully deleted"))
+}
+
+func (h *AccountHandler) userId(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return 0, false
+	}
+
+	return userId, true
+}
+
+// reauthFailed writes the response for a wrong or locked out password.
+func reauthFailed(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) bool {
+	var lockedErr *authService.LockedError
+	if errors.As(err, &lockedErr) {
+		log.Warn("too many attempts", sl.Err(err))
+
+		retryAfter := int(math.Ceil(time.Until(lockedErr.Until).Seconds()))
+
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		render.JSON(w, r, resp.Error("too many attempts, try again later"))
+
+		return true
+	}
+	if errors.Is(err, authService.ErrInvalidCredentials) {
+		log.Warn("invalid password", sl.Err(err))
+
+		w.WriteHeader(http.StatusForbidden)
+		render.JSON(w, r, resp.Error("invalid password"))
+
+		return true
+	}
+	if errors.Is(err, authService.ErrUserNotFound) {
+		log.Warn("user not found", sl.Err(err))
+
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error("user not found"))
+
+		return true
+	}
+
+	return false
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return false
+	}
+
+	return true
+}
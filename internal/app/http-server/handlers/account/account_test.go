@@ -0,0 +1,421 @@
+package account_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/account"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/account/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/Muaz717/todo-app/internal/lib/password"
+	"github.com/stretchr/testify/require"
+)
+
+var testProfile = models.Profile{
+	Id:            1,
+	Email:         "test@mail.ru",
+	DisplayName:   "Test",
+	Timezone:      "Europe/Moscow",
+	Locale:        "ru",
+	EmailVerified: true,
+}
+
+func TestProfileHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "User not found",
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  authService.ErrUserNotFound,
+		},
+		{
+			name:       "Profile error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get profile",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			accountMock := mocks.NewAccount(t)
+
+			accountMock.
+				On("Profile", ctx, int64(1)).
+				Return(testProfile, tt.mockError)
+
+			handler := account.New(ctx, log, accountMock).Profile
+
+			req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp account.ProfileResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, testProfile, resp.Profile)
+			}
+		})
+	}
+}
+
+func TestUpdateHandler(t *testing.T) {
+	timezone := "Europe/Moscow"
+	locale := "ru"
+
+	tests := []struct {
+		name       string
+		req        account.UpdateRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        account.UpdateRequest{Timezone: &timezone, Locale: &locale},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid timezone",
+			req:        account.UpdateRequest{Timezone: &timezone},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid timezone",
+			mockError:  authService.ErrInvalidTimezone,
+		},
+		{
+			name:       "Invalid locale",
+			req:        account.UpdateRequest{Locale: &locale},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid locale",
+			mockError:  authService.ErrInvalidLocale,
+		},
+		{
+			name:       "Update error",
+			req:        account.UpdateRequest{Locale: &locale},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to update profile",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			accountMock := mocks.NewAccount(t)
+
+			input := models.UpdateProfileInput{
+				DisplayName: tt.req.DisplayName,
+				Timezone:    tt.req.Timezone,
+				Locale:      tt.req.Locale,
+			}
+
+			accountMock.
+				On("UpdateProfile", ctx, int64(1), input).
+				Return(testProfile, tt.mockError)
+
+			handler := account.New(ctx, log, accountMock).Update
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/me", &body)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp account.ProfileResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, testProfile, resp.Profile)
+			}
+		})
+	}
+}
+
+func TestChangePasswordHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        account.ChangePasswordRequest
+		statusCode int
+		respError  string
+		mockError  error
+		retryAfter bool
+	}{
+		{
+			name:       "Success",
+			req:        account.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "new password"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty new password",
+			req:        account.ChangePasswordRequest{CurrentPassword: "old password"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field NewPassword is a required field",
+		},
+		{
+			name:       "Wrong password",
+			req:        account.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new password"},
+			statusCode: http.StatusForbidden,
+			respError:  "invalid password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name:       "Locked",
+			req:        account.ChangePasswordRequest{CurrentPassword: "wrong", NewPassword: "new password"},
+			statusCode: http.StatusTooManyRequests,
+			respError:  "too many attempts, try again later",
+			mockError:  &authService.LockedError{Until: time.Now().Add(time.Minute)},
+			retryAfter: true,
+		},
+		{
+			name:       "Weak password",
+			req:        account.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "short"},
+			statusCode: http.StatusBadRequest,
+			respError:  "weak password: must be at least 8 characters long",
+			mockError:  &password.PolicyError{Reason: "must be at least 8 characters long"},
+		},
+		{
+			name:       "ChangePassword error",
+			req:        account.ChangePasswordRequest{CurrentPassword: "old password", NewPassword: "new password"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to change password",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			accountMock := mocks.NewAccount(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				accountMock.
+					On("ChangePassword", ctx, int64(1), tt.req.CurrentPassword, tt.req.NewPassword).
+					Return(tt.mockError)
+			}
+
+			handler := account.New(ctx, log, accountMock).ChangePassword
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/me/password", &body)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+			require.Equal(t, tt.retryAfter, rr.Header().Get("Retry-After") != "")
+		})
+	}
+}
+
+func TestChangeEmailHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        account.ChangeEmailRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        account.ChangeEmailRequest{Email: "new@mail.ru", Password: "password"},
+			statusCode: http.StatusAccepted,
+		},
+		{
+			name:       "Invalid email",
+			req:        account.ChangeEmailRequest{Email: "new", Password: "password"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is not a valid Email",
+		},
+		{
+			name:       "Wrong password",
+			req:        account.ChangeEmailRequest{Email: "new@mail.ru", Password: "wrong"},
+			statusCode: http.StatusForbidden,
+			respError:  "invalid password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name:       "Email taken",
+			req:        account.ChangeEmailRequest{Email: "taken@mail.ru", Password: "password"},
+			statusCode: http.StatusConflict,
+			respError:  "email is already taken",
+			mockError:  authService.ErrUserExists,
+		},
+		{
+			name:       "ChangeEmail error",
+			req:        account.ChangeEmailRequest{Email: "new@mail.ru", Password: "password"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to change email",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			accountMock := mocks.NewAccount(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				accountMock.
+					On("ChangeEmail", ctx, int64(1), tt.req.Password, tt.req.Email).
+					Return(tt.mockError)
+			}
+
+			handler := account.New(ctx, log, accountMock).ChangeEmail
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/me/email", &body)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        account.DeleteRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        account.DeleteRequest{Password: "password"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty password",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Password is a required field",
+		},
+		{
+			name:       "Wrong password",
+			req:        account.DeleteRequest{Password: "wrong"},
+			statusCode: http.StatusForbidden,
+			respError:  "invalid password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name:       "Owns shared workspace",
+			req:        account.DeleteRequest{Password: "password"},
+			statusCode: http.StatusConflict,
+			respError:  "make someone an admin of your shared workspaces first",
+			mockError:  authService.ErrWorkspaceOwned,
+		},
+		{
+			name:       "DeleteAccount error",
+			req:        account.DeleteRequest{Password: "password"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to delete account",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			accountMock := mocks.NewAccount(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				accountMock.
+					On("DeleteAccount", ctx, int64(1), tt.req.Password).
+					Return(tt.mockError)
+			}
+
+			handler := account.New(ctx, log, accountMock).Delete
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/me", &body)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
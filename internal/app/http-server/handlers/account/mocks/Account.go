@@ -0,0 +1,139 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Account is an autogenerated mock type for the Account type
+type Account struct {
+	mock.Mock
+}
+
+// ChangeEmail provides a mock function with given fields: ctx, userId, password, newEmail
+func (_m *Account) ChangeEmail(ctx context.Context, userId int64, password string, newEmail string) error {
+	ret := _m.Called(ctx, userId, password, newEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, userId, password, newEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ChangePassword provides a mock function with given fields: ctx, userId, currentPassword, newPassword
+func (_m *Account) ChangePassword(ctx context.Context, userId int64, currentPassword string, newPassword string) error {
+	ret := _m.Called(ctx, userId, currentPassword, newPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, userId, currentPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteAccount provides a mock function with given fields: ctx, userId, password
+func (_m *Account) DeleteAccount(ctx context.Context, userId int64, password string) error {
+	ret := _m.Called(ctx, userId, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteAccount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) error); ok {
+		r0 = rf(ctx, userId, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Profile provides a mock function with given fields: ctx, userId
+func (_m *Account) Profile(ctx context.Context, userId int64) (models.Profile, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Profile")
+	}
+
+	var r0 models.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.Profile, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.Profile); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(models.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, userId, input
+func (_m *Account) UpdateProfile(ctx context.Context, userId int64, input models.UpdateProfileInput) (models.Profile, error) {
+	ret := _m.Called(ctx, userId, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 models.Profile
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.UpdateProfileInput) (models.Profile, error)); ok {
+		return rf(ctx, userId, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.UpdateProfileInput) models.Profile); ok {
+		r0 = rf(ctx, userId, input)
+	} else {
+		r0 = ret.Get(0).(models.Profile)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.UpdateProfileInput) error); ok {
+		r1 = rf(ctx, userId, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAccount creates a new instance of Account. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccount(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Account {
+	mock := &Account{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
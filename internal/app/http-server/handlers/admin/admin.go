@@ -0,0 +1,335 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	adminsrv "github.com/Muaz717/todo-app/internal/app/services/admin"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Admin
+type Admin interface {
+	Users(ctx context.Context, filter models.UserFilter) (models.UserPage, error)
+	User(ctx context.Context, userId int64) (models.UserDetails, error)
+	SetDisabled(ctx context.Context, adminId int64, userId int64, disabled bool) error
+	SetRole(ctx context.Context, adminId int64, userId int64, role string) error
+	ForcePasswordReset(ctx context.Context, adminId int64, userId int64) error
+}
+
+type AdminHandler struct {
+	ctx   context.Context
+	log   *slog.Logger
+	admin Admin
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	admin Admin,
+) *AdminHandler {
+	return &AdminHandler{
+		ctx:   ctx,
+		log:   log,
+		admin: admin,
+	}
+}
+
+type RoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type UserResponse struct {
+	resp.Response
+	User models.UserDetails `json:"user"`
+}
+
+const (
+	defaultUsersLimit = 50
+	maxUsersLimit     = 200
+)
+
+func (h *AdminHandler) Users(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.admin.Users"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	filter, err := parseUserFilter(r)
+	if err != nil {
+		log.Error("invalid query", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(err.Error()))
+
+		return
+	}
+
+	page, err := h.admin.Users(h.ctx, filter)
+	if err != nil {
+		log.Error("failed to get users", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get users"))
+
+		return
+	}
+
+	render.JSON(w, r, page)
+}
+
+func (h *AdminHandler) User(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.admin.User"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	_, userId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	user, err := h.admin.User(h.ctx, userId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get user")
+
+		return
+	}
+
+	render.JSON(w, r, UserResponse{
+		Response: resp.OK("User found"),
+		User:     user,
+	})
+}
+
+func (h *AdminHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, "handlers.admin.Disable", true)
+}
+
+func (h *AdminHandler) Enable(w http.ResponseWriter, r *http.Request) {
+	h.setDisabled(w, r, "handlers.admin.Enable", false)
+}
+
+func (h *AdminHandler) setDisabled(w http.ResponseWriter, r *http.Request, op string, disabled bool) {
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	adminId, userId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.admin.SetDisabled(h.ctx, adminId, userId, disabled); err != nil {
+		writeError(w, r, log, err, "failed to change user state")
+
+		return
+	}
+
+	if disabled {
+		log.Info("user disabled", slog.Int64("user_id", userId))
+
+		render.JSON(w, r, resp.OK("User disabled"))
+
+		return
+	}
+
+	log.Info("user enabled", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("User enabled"))
+}
+
+func (h *AdminHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.admin.SetRole"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req RoleRequest
+
+	err := render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	adminId, userId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.admin.SetRole(h.ctx, adminId, userId, req.Role); err != nil {
+		writeError(w, r, log, err, "failed to set user role")
+
+		return
+	}
+
+	log.Info("user role changed", slog.Int64("user_id", userId), slog.String("role", req.Role))
+
+	render.JSON(w, r, resp.OK("User role changed"))
+}
+
+func (h *AdminHandler) ForcePasswordReset(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.admin.ForcePasswordReset"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	adminId, userId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.admin.ForcePasswordReset(h.ctx, adminId, userId); err != nil {
+		writeError(w, r, log, err, "failed to reset password")
+
+		return
+	}
+
+	log.Info("password reset forced", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("User signed out, password reset link sent"))
+}
+
+// target returns the id of the admin making the request and of the user in the path.
+func (h *AdminHandler) target(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, int64, bool) {
+	userId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid user id"))
+
+		return 0, 0, false
+	}
+
+	adminId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return 0, 0, false
+	}
+
+	return adminId, userId, true
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, msg string) {
+	switch {
+	case errors.Is(err, adminsrv.ErrUserNotFound):
+		log.Warn("user not found", sl.Err(err))
+
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error("user not found"))
+	case errors.Is(err, adminsrv.ErrInvalidRole):
+		log.Warn("invalid role", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid role"))
+	case errors.Is(err, adminsrv.ErrSelfAction):
+		log.Warn("admin acting on own account", sl.Err(err))
+
+		w.WriteHeader(http.StatusConflict)
+		render.JSON(w, r, resp.Error("admins can not disable or demote themselves"))
+	default:
+		log.Error(msg, sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error(msg))
+	}
+}
+
+// parseUserFilter reads listing options from the query string,
+// returned errors are meant to be shown to the client.
+func parseUserFilter(r *http.Request) (models.UserFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.UserFilter{
+		Query: query.Get("q"),
+		Limit: defaultUsersLimit,
+	}
+
+	switch v := query.Get("role"); v {
+	case "":
+	case models.RoleUser, models.RoleAdmin:
+		filter.Role = v
+	default:
+		return models.UserFilter{}, errors.New("invalid role")
+	}
+
+	switch query.Get("status") {
+	case "", "all":
+	case "active":
+		disabled := false
+		filter.Disabled = &disabled
+	case "disabled":
+		disabled := true
+		filter.Disabled = &disabled
+	default:
+		return models.UserFilter{}, errors.New("invalid status")
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxUsersLimit {
+			return models.UserFilter{}, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		after, err := cursor.Decode(v)
+		if err != nil {
+			return models.UserFilter{}, errors.New("invalid cursor")
+		}
+		filter.AfterId = after.Id
+	}
+
+	return filter, nil
+}
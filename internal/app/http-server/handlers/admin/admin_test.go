@@ -0,0 +1,407 @@
+package admin_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/admin"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/admin/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	adminsrv "github.com/Muaz717/todo-app/internal/app/services/admin"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsersHandler(t *testing.T) {
+	disabled := true
+
+	tests := []struct {
+		name       string
+		query      string
+		filter     models.UserFilter
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Defaults",
+			filter:     models.UserFilter{Limit: 50},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:  "Search",
+			query: "?q=mail.ru&role=admin&status=disabled&limit=10&cursor=" + cursor.Encode(models.Cursor{Id: 7}),
+			filter: models.UserFilter{
+				Query:    "mail.ru",
+				Role:     models.RoleAdmin,
+				Disabled: &disabled,
+				Limit:    10,
+				AfterId:  7,
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid role",
+			query:      "?role=root",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid role",
+		},
+		{
+			name:       "Invalid limit",
+			query:      "?limit=1000",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid limit",
+		},
+		{
+			name:       "Users error",
+			filter:     models.UserFilter{Limit: 50},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get users",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			adminMock := mocks.NewAdmin(t)
+
+			page := models.UserPage{
+				Users: []models.UserSummary{{Id: 8, Email: "test@mail.ru", Role: models.RoleUser}},
+			}
+
+			if tt.respError == "" || tt.mockError != nil {
+				adminMock.
+					On("Users", ctx, tt.filter).
+					Return(page, tt.mockError)
+			}
+
+			handler := admin.New(ctx, log, adminMock).Users
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got models.UserPage
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, page, got)
+		})
+	}
+}
+
+func TestUserHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			id:         "8",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid user id",
+		},
+		{
+			name:       "Not found",
+			id:         "8",
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  adminsrv.ErrUserNotFound,
+		},
+		{
+			name:       "User error",
+			id:         "8",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get user",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			adminMock := mocks.NewAdmin(t)
+
+			user := models.UserDetails{
+				UserSummary: models.UserSummary{Id: 8, Email: "test@mail.ru", Role: models.RoleUser},
+				Items:       models.ItemCounts{Total: 3, Open: 2, Done: 1},
+				Lists:       1,
+			}
+
+			if tt.respError == "" || tt.mockError != nil {
+				adminMock.
+					On("User", ctx, int64(8)).
+					Return(user, tt.mockError)
+			}
+
+			handler := admin.New(ctx, log, adminMock).User
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users/"+tt.id, nil)
+			req = withUser(withId(req, tt.id), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp admin.UserResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, user, resp.User)
+			}
+		})
+	}
+}
+
+func TestDisableHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		disable    bool
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Disable",
+			disable:    true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Enable",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Self",
+			disable:    true,
+			statusCode: http.StatusConflict,
+			respError:  "admins can not disable or demote themselves",
+			mockError:  adminsrv.ErrSelfAction,
+		},
+		{
+			name:       "Not found",
+			disable:    true,
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  adminsrv.ErrUserNotFound,
+		},
+		{
+			name:       "SetDisabled error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to change user state",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			adminMock := mocks.NewAdmin(t)
+
+			adminMock.
+				On("SetDisabled", ctx, int64(1), int64(8), tt.disable).
+				Return(tt.mockError)
+
+			h := admin.New(ctx, log, adminMock)
+
+			handler := h.Enable
+			if tt.disable {
+				handler = h.Disable
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/8/disable", nil)
+			req = withUser(withId(req, "8"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestSetRoleHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        admin.RoleRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        admin.RoleRequest{Role: models.RoleAdmin},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty role",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Role is a required field",
+		},
+		{
+			name:       "Invalid role",
+			req:        admin.RoleRequest{Role: "root"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid role",
+			mockError:  adminsrv.ErrInvalidRole,
+		},
+		{
+			name:       "SetRole error",
+			req:        admin.RoleRequest{Role: models.RoleUser},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to set user role",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			adminMock := mocks.NewAdmin(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				adminMock.
+					On("SetRole", ctx, int64(1), int64(8), tt.req.Role).
+					Return(tt.mockError)
+			}
+
+			handler := admin.New(ctx, log, adminMock).SetRole
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/8/role", &body)
+			req = withUser(withId(req, "8"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestForcePasswordResetHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not found",
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  adminsrv.ErrUserNotFound,
+		},
+		{
+			name:       "Reset error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to reset password",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			adminMock := mocks.NewAdmin(t)
+
+			adminMock.
+				On("ForcePasswordReset", ctx, int64(1), int64(8)).
+				Return(tt.mockError)
+
+			handler := admin.New(ctx, log, adminMock).ForcePasswordReset
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/8/password-reset", nil)
+			req = withUser(withId(req, "8"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
+
+func withId(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,139 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Admin is an autogenerated mock type for the Admin type
+type Admin struct {
+	mock.Mock
+}
+
+// ForcePasswordReset provides a mock function with given fields: ctx, adminId, userId
+func (_m *Admin) ForcePasswordReset(ctx context.Context, adminId int64, userId int64) error {
+	ret := _m.Called(ctx, adminId, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForcePasswordReset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, adminId, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetDisabled provides a mock function with given fields: ctx, adminId, userId, disabled
+func (_m *Admin) SetDisabled(ctx context.Context, adminId int64, userId int64, disabled bool) error {
+	ret := _m.Called(ctx, adminId, userId, disabled)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetDisabled")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, bool) error); ok {
+		r0 = rf(ctx, adminId, userId, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRole provides a mock function with given fields: ctx, adminId, userId, role
+func (_m *Admin) SetRole(ctx context.Context, adminId int64, userId int64, role string) error {
+	ret := _m.Called(ctx, adminId, userId, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) error); ok {
+		r0 = rf(ctx, adminId, userId, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// User provides a mock function with given fields: ctx, userId
+func (_m *Admin) User(ctx context.Context, userId int64) (models.UserDetails, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for User")
+	}
+
+	var r0 models.UserDetails
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (models.UserDetails, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) models.UserDetails); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Get(0).(models.UserDetails)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Users provides a mock function with given fields: ctx, filter
+func (_m *Admin) Users(ctx context.Context, filter models.UserFilter) (models.UserPage, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Users")
+	}
+
+	var r0 models.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter) (models.UserPage, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, models.UserFilter) models.UserPage); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(models.UserPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, models.UserFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAdmin creates a new instance of Admin. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdmin(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Admin {
+	mock := &Admin{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,240 @@
+package apitoken
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	apitokensrv "github.com/Muaz717/todo-app/internal/app/services/apitoken"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=APIToken
+type APIToken interface {
+	Create(
+		ctx context.Context,
+		userId int64,
+		input models.CreateAPITokenInput,
+	) (models.APIToken, string, error)
+	AllTokens(ctx context.Context, userId int64) ([]models.APIToken, error)
+	Revoke(ctx context.Context, userId int64, tokenId int64) error
+}
+
+type APITokenHandler struct {
+	ctx      context.Context
+	log      *slog.Logger
+	apiToken APIToken
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	apiToken APIToken,
+) *APITokenHandler {
+	return &APITokenHandler{
+		ctx:      ctx,
+		log:      log,
+		apiToken: apiToken,
+	}
+}
+
+type Request struct {
+	Name      string     `json:"name" validate:"required,max=100"`
+	Scopes    []string   `json:"scopes" validate:"required,min=1"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CreateResponse carries the token secret, it is shown only once.
+type CreateResponse struct {
+	resp.Response
+	Id       int64           `json:"id"`
+	Token    string          `json:"token"`
+	APIToken models.APIToken `json:"api_token"`
+}
+
+func (h *APITokenHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.apitoken.Create"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req Request
+
+	err := render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	input := models.CreateAPITokenInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	apiToken, secret, err := h.apiToken.Create(h.ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, apitokensrv.ErrInvalidScope) {
+			log.Warn("invalid scope", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid scope"))
+
+			return
+		}
+		if errors.Is(err, apitokensrv.ErrInvalidExpires) {
+			log.Warn("expiry is in the past", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("expiry is in the past"))
+
+			return
+		}
+
+		log.Error("failed to create api token", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to create api token"))
+
+		return
+	}
+
+	log.Info("api token created", slog.Int64("token_id", apiToken.Id), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Api token successfully created"),
+		Id:       apiToken.Id,
+		Token:    secret,
+		APIToken: apiToken,
+	})
+}
+
+func (h *APITokenHandler) AllTokens(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.apitoken.AllTokens"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	tokens, err := h.apiToken.AllTokens(h.ctx, userId)
+	if err != nil {
+		log.Error("failed to get api tokens", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get api tokens"))
+
+		return
+	}
+
+	log.Info("all api tokens showed")
+
+	render.JSON(w, r, tokens)
+}
+
+func (h *APITokenHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.apitoken.Revoke"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	tokenId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid token id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid token id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := h.apiToken.Revoke(h.ctx, userId, tokenId); err != nil {
+		if errors.Is(err, apitokensrv.ErrTokenNotFound) {
+			log.Warn("api token not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("api token not found"))
+
+			return
+		}
+
+		log.Error("failed to revoke api token", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to revoke api token"))
+
+		return
+	}
+
+	log.Info("api token revoked", slog.Int64("token_id", tokenId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Api token successfully revoked"))
+}
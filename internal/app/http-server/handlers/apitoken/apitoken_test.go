@@ -0,0 +1,273 @@
+package apitoken_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/apitoken"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/apitoken/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	apitokensrv "github.com/Muaz717/todo-app/internal/app/services/apitoken"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        apitoken.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name: "Success",
+			req: apitoken.Request{
+				Name:   "ci",
+				Scopes: []string{models.ScopeItemsRead},
+			},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty name",
+			req:        apitoken.Request{Scopes: []string{models.ScopeItemsRead}},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Name is a required field",
+		},
+		{
+			name:       "No scopes",
+			req:        apitoken.Request{Name: "ci"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Scopes is a required field",
+		},
+		{
+			name: "Invalid scope",
+			req: apitoken.Request{
+				Name:   "ci",
+				Scopes: []string{"items:delete"},
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid scope",
+			mockError:  apitokensrv.ErrInvalidScope,
+		},
+		{
+			name: "Expired",
+			req: apitoken.Request{
+				Name:      "ci",
+				Scopes:    []string{models.ScopeItemsRead},
+				ExpiresAt: ptr(time.Now().Add(-time.Hour)),
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "expiry is in the past",
+			mockError:  apitokensrv.ErrInvalidExpires,
+		},
+		{
+			name: "Create error",
+			req: apitoken.Request{
+				Name:   "ci",
+				Scopes: []string{models.ScopeItemsRead},
+			},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create api token",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			apiTokenMock := mocks.NewAPIToken(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				apiTokenMock.
+					On("Create", ctx, int64(1), mock.AnythingOfType("models.CreateAPITokenInput")).
+					Return(models.APIToken{Id: 1, Name: tt.req.Name}, "tdp_secret", tt.mockError)
+			}
+
+			handler := apitoken.New(ctx, log, apiTokenMock).Create
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tokens/", &input)
+			req = withUserAndToken(req, 1, "")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp apitoken.CreateResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, "tdp_secret", resp.Token)
+				require.Equal(t, int64(1), resp.Id)
+			}
+		})
+	}
+}
+
+func TestAllTokensHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokens     []models.APIToken
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			tokens:     []models.APIToken{{Id: 1, Name: "ci", Prefix: "tdp_abcdef", Scopes: []string{models.ScopeItemsRead}}},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get api tokens",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			apiTokenMock := mocks.NewAPIToken(t)
+			apiTokenMock.
+				On("AllTokens", ctx, int64(1)).
+				Return(tt.tokens, tt.mockError)
+
+			handler := apitoken.New(ctx, log, apiTokenMock).AllTokens
+
+			req := httptest.NewRequest(http.MethodGet, "/api/tokens/", nil)
+			req = withUserAndToken(req, 1, "")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var tokens []models.APIToken
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &tokens))
+			require.Equal(t, tt.tokens, tokens)
+		})
+	}
+}
+
+func TestRevokeHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		tokenId    string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			tokenId:    "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			tokenId:    "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid token id",
+		},
+		{
+			name:       "Not found",
+			tokenId:    "1",
+			statusCode: http.StatusNotFound,
+			respError:  "api token not found",
+			mockError:  apitokensrv.ErrTokenNotFound,
+		},
+		{
+			name:       "Revoke error",
+			tokenId:    "1",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to revoke api token",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			apiTokenMock := mocks.NewAPIToken(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				apiTokenMock.
+					On("Revoke", ctx, int64(1), int64(1)).
+					Return(tt.mockError)
+			}
+
+			handler := apitoken.New(ctx, log, apiTokenMock).Revoke
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/tokens/"+tt.tokenId, nil)
+			req = withUserAndToken(req, 1, tt.tokenId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUserAndToken(req *http.Request, userId int64, tokenId string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", tokenId)
+
+	ctx := context.WithValue(req.Context(), identification.Uid("user_id"), userId)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return req.WithContext(ctx)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
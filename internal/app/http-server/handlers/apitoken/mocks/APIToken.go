@@ -0,0 +1,112 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// APIToken is an autogenerated mock type for the APIToken type
+type APIToken struct {
+	mock.Mock
+}
+
+// AllTokens provides a mock function with given fields: ctx, userId
+func (_m *APIToken) AllTokens(ctx context.Context, userId int64) ([]models.APIToken, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllTokens")
+	}
+
+	var r0 []models.APIToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.APIToken, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.APIToken); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.APIToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userId, input
+func (_m *APIToken) Create(ctx context.Context, userId int64, input models.CreateAPITokenInput) (models.APIToken, string, error) {
+	ret := _m.Called(ctx, userId, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 models.APIToken
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateAPITokenInput) (models.APIToken, string, error)); ok {
+		return rf(ctx, userId, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateAPITokenInput) models.APIToken); ok {
+		r0 = rf(ctx, userId, input)
+	} else {
+		r0 = ret.Get(0).(models.APIToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.CreateAPITokenInput) string); ok {
+		r1 = rf(ctx, userId, input)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, int64, models.CreateAPITokenInput) error); ok {
+		r2 = rf(ctx, userId, input)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Revoke provides a mock function with given fields: ctx, userId, tokenId
+func (_m *APIToken) Revoke(ctx context.Context, userId int64, tokenId int64) error {
+	ret := _m.Called(ctx, userId, tokenId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, tokenId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAPIToken creates a new instance of APIToken. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAPIToken(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *APIToken {
+	mock := &APIToken{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,186 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	archivesrv "github.com/Muaz717/todo-app/internal/app/services/archive"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Archive
+type Archive interface {
+	Export(ctx context.Context, userId int64, w io.Writer) error
+	Import(ctx context.Context, userId int64, r io.ReaderAt, size int64) error
+}
+
+type ArchiveHandler struct {
+	ctx     context.Context
+	log     *slog.Logger
+	archive Archive
+	// maxImportSize limits uploaded archives, in bytes.
+	maxImportSize int64
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	archive Archive,
+	maxImportSize int64,
+) *ArchiveHandler {
+	return &ArchiveHandler{
+		ctx:           ctx,
+		log:           log,
+		archive:       archive,
+		maxImportSize: maxImportSize,
+	}
+}
+
+// Export streams the archive, the write timeout of the server is lifted
+// since large accounts take longer to download.
+func (h *ArchiveHandler) Export(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.archive.Export"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to lift write deadline", sl.Err(err))
+	}
+
+	filename := fmt.Sprintf("todo-export-%s.zip", time.Now().UTC().Format("2006-01-02"))
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	// the status is sent with the first bytes, a failure can only cut the archive short
+	if err := h.archive.Export(h.ctx, userId, w); err != nil {
+		log.Error("failed to export user data", sl.Err(err))
+
+		return
+	}
+
+	log.Info("user data exported", slog.Int64("user_id", userId))
+}
+
+// Import takes the archive as the request body. It is spooled to a temporary
+// file, zip archives can not be read front to back.
+func (h *ArchiveHandler) Import(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.archive.Import"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	if err := rc.SetReadDeadline(time.Time{}); err != nil {
+		log.Warn("failed to lift read deadline", sl.Err(err))
+	}
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		log.Warn("failed to lift write deadline", sl.Err(err))
+	}
+
+	f, err := os.CreateTemp("", "todo-import-*.zip")
+	if err != nil {
+		log.Error("failed to create temporary file", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to import data"))
+
+		return
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	size, err := io.Copy(f, http.MaxBytesReader(w, r.Body, h.maxImportSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			log.Warn("archive is too large", sl.Err(err))
+
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			render.JSON(w, r, resp.Error("archive is too large"))
+
+			return
+		}
+
+		log.Error("failed to read request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to read request"))
+
+		return
+	}
+
+	if size == 0 {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+
+	if err := h.archive.Import(h.ctx, userId, f, size); err != nil {
+		if errors.Is(err, archivesrv.ErrInvalidArchive) {
+			log.Warn("invalid archive", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid archive"))
+
+			return
+		}
+		if errors.Is(err, archivesrv.ErrAccountNotEmpty) {
+			log.Warn("account already has data", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("account already has data, import into a fresh account"))
+
+			return
+		}
+
+		log.Error("failed to import data", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to import data"))
+
+		return
+	}
+
+	log.Info("user data imported", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Data successfully imported"))
+}
@@ -0,0 +1,143 @@
+package archive_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/archive"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/archive/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	archivesrv "github.com/Muaz717/todo-app/internal/app/services/archive"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportHandler(t *testing.T) {
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+
+	archiveMock := mocks.NewArchive(t)
+
+	archiveMock.
+		On("Export", ctx, int64(1), mock.Anything).
+		Run(func(args mock.Arguments) {
+			_, _ = io.WriteString(args.Get(2).(io.Writer), "PK archive")
+		}).
+		Return(nil)
+
+	handler := archive.New(ctx, log, archiveMock, 1024).Export
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me/export", nil)
+	req = withUser(req, 1)
+
+	rr := httptest.NewRecorder()
+	handler(rr, req)
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	require.Equal(t, "application/zip", rr.Header().Get("Content-Type"))
+	require.Contains(t, rr.Header().Get("Content-Disposition"), "attachment; filename=\"todo-export-")
+	require.Equal(t, "PK archive", rr.Body.String())
+}
+
+func TestImportHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		statusCode int
+		respError  string
+		mockError  error
+		callImport bool
+	}{
+		{
+			name:       "Success",
+			body:       "PK archive",
+			statusCode: http.StatusOK,
+			callImport: true,
+		},
+		{
+			name:       "Empty body",
+			statusCode: http.StatusBadRequest,
+			respError:  "empty request",
+		},
+		{
+			name:       "Too large",
+			body:       strings.Repeat("x", 2048),
+			statusCode: http.StatusRequestEntityTooLarge,
+			respError:  "archive is too large",
+		},
+		{
+			name:       "Invalid archive",
+			body:       "not a zip",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid archive",
+			mockError:  archivesrv.ErrInvalidArchive,
+			callImport: true,
+		},
+		{
+			name:       "Account not empty",
+			body:       "PK archive",
+			statusCode: http.StatusConflict,
+			respError:  "account already has data, import into a fresh account",
+			mockError:  archivesrv.ErrAccountNotEmpty,
+			callImport: true,
+		},
+		{
+			name:       "Import error",
+			body:       "PK archive",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to import data",
+			mockError:  errors.New("unexpected error"),
+			callImport: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			archiveMock := mocks.NewArchive(t)
+
+			if tt.callImport {
+				archiveMock.
+					On("Import", ctx, int64(1), mock.Anything, int64(len(tt.body))).
+					Run(func(args mock.Arguments) {
+						// the handler hands over the whole body
+						data, err := io.ReadAll(io.NewSectionReader(args.Get(2).(io.ReaderAt), 0, args.Get(3).(int64)))
+						require.NoError(t, err)
+						require.Equal(t, tt.body, string(data))
+					}).
+					Return(tt.mockError)
+			}
+
+			handler := archive.New(ctx, log, archiveMock, 1024).Import
+
+			req := httptest.NewRequest(http.MethodPost, "/api/me/import", strings.NewReader(tt.body))
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
@@ -0,0 +1,65 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Archive is an autogenerated mock type for the Archive type
+type Archive struct {
+	mock.Mock
+}
+
+// Export provides a mock function with given fields: ctx, userId, w
+func (_m *Archive) Export(ctx context.Context, userId int64, w io.Writer) error {
+	ret := _m.Called(ctx, userId, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.Writer) error); ok {
+		r0 = rf(ctx, userId, w)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Import provides a mock function with given fields: ctx, userId, r, size
+func (_m *Archive) Import(ctx context.Context, userId int64, r io.ReaderAt, size int64) error {
+	ret := _m.Called(ctx, userId, r, size)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, io.ReaderAt, int64) error); ok {
+		r0 = rf(ctx, userId, r, size)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewArchive creates a new instance of Archive. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewArchive(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Archive {
+	mock := &Archive{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
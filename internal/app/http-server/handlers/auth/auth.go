@@ -5,10 +5,18 @@ import (
 	"errors"
 	"io"
 	"log/slog"
+	"math"
+	"net"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
 	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/password"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -21,7 +29,40 @@ type Request struct {
 
 type Response struct {
 	resp.Response
-	Token string `json:"token"`
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+}
+
+// ChallengeResponse is returned by sign-in for users with two-factor
+// authentication, the challenge token is exchanged together with a code.
+type ChallengeResponse struct {
+	resp.Response
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type TwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 
 //go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Auth
@@ -35,7 +76,16 @@ type Auth interface {
 		ctx context.Context,
 		email string,
 		password string,
-	) (token string, err error)
+		ip string,
+	) (models.LoginResult, error)
+	VerifyTwoFactor(ctx context.Context, challengeToken string, code string) (models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	LogoutAll(ctx context.Context, userId int64) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, password string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
 }
 
 type AuthHandler struct {
@@ -95,6 +145,24 @@ func (h *AuthHandler) RegisterNewUser(w http.ResponseWriter, r *http.Request) {
 
 	userId, err := h.auth.RegisterNewUser(h.ctx, req.Email, req.Password)
 	if err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			log.Warn("weak password", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(policyErr.Error()))
+
+			return
+		}
+		if errors.Is(err, authService.ErrUserExists) {
+			log.Warn("user already exists", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("user already exists"))
+
+			return
+		}
+
 		log.Error("failed to register new user", sl.Err(err))
 
 		w.WriteHeader(http.StatusInternalServerError)
@@ -149,8 +217,53 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	token, err := h.auth.Login(h.ctx, req.Email, req.Password)
+	result, err := h.auth.Login(h.ctx, req.Email, req.Password, clientIP(r))
 	if err != nil {
+		var lockedErr *authService.LockedError
+		if errors.As(err, &lockedErr) {
+			log.Warn("too many login attempts", sl.Err(err))
+
+			retryAfter := int(math.Ceil(time.Until(lockedErr.Until).Seconds()))
+
+			w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			render.JSON(w, r, resp.Error("too many login attempts, try again later"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidCredentials) {
+			log.Warn("invalid email or password", sl.Err(err))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("invalid email or password"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrEmailNotVerified) {
+			log.Warn("email is not verified", sl.Err(err))
+
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error("email is not verified"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrAccountDisabled) {
+			log.Warn("account is disabled", sl.Err(err))
+
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error("account is disabled"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrResetRequired) {
+			log.Warn("password reset is required", sl.Err(err))
+
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error("password reset is required"))
+
+			return
+		}
+
 		log.Error("invalid email or password", sl.Err(err))
 
 		w.WriteHeader(http.StatusInternalServerError)
@@ -159,14 +272,376 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Info("user got token", slog.String("token", token))
+	if result.ChallengeToken != "" {
+		log.Info("two-factor code required")
+
+		render.JSON(w, r, ChallengeResponse{
+			Response:          resp.OK("Two-factor code required"),
+			TwoFactorRequired: true,
+			ChallengeToken:    result.ChallengeToken,
+			ExpiresAt:         result.ChallengeExpiresAt,
+		})
+
+		return
+	}
+
+	log.Info("user got token")
+
+	render.JSON(w, r, responseOK(result.Tokens))
+}
+
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.VerifyTwoFactor"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req TwoFactorRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	pair, err := h.auth.VerifyTwoFactor(h.ctx, req.ChallengeToken, req.Code)
+	if err != nil {
+		if errors.Is(err, authService.ErrInvalidChallenge) {
+			log.Warn("invalid challenge", sl.Err(err))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("invalid or expired challenge"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidCode) {
+			log.Warn("invalid two-factor code", sl.Err(err))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("invalid two-factor code"))
+
+			return
+		}
+
+		log.Error("failed to verify two-factor code", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to verify two-factor code"))
+
+		return
+	}
+
+	log.Info("user got token")
+
+	render.JSON(w, r, responseOK(pair))
+}
+
+func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.Refresh"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	req, ok := decodeRefreshRequest(w, r, log)
+	if !ok {
+		return
+	}
+
+	pair, err := h.auth.Refresh(h.ctx, req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, authService.ErrInvalidToken) || errors.Is(err, authService.ErrTokenReused) {
+			log.Warn("invalid refresh token", sl.Err(err))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("invalid refresh token"))
+
+			return
+		}
+
+		log.Error("failed to refresh token", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to refresh token"))
+
+		return
+	}
+
+	log.Info("token refreshed")
+
+	render.JSON(w, r, responseOK(pair))
+}
+
+func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.Logout"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	req, ok := decodeRefreshRequest(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.auth.Logout(h.ctx, req.RefreshToken); err != nil {
+		if errors.Is(err, authService.ErrInvalidToken) {
+			log.Warn("invalid refresh token", sl.Err(err))
+
+			w.WriteHeader(http.StatusUnauthorized)
+			render.JSON(w, r, resp.Error("invalid refresh token"))
+
+			return
+		}
+
+		log.Error("failed to logout", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to logout"))
+
+		return
+	}
+
+	log.Info("user logged out")
+
+	render.JSON(w, r, resp.OK("You successfully logged out"))
+}
+
+func (h *AuthHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.LogoutAll"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := h.auth.LogoutAll(h.ctx, userId); err != nil {
+		log.Error("failed to logout everywhere", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to logout"))
+
+		return
+	}
+
+	log.Info("user logged out everywhere", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("You successfully logged out everywhere"))
+}
+
+func (h *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.ForgotPassword"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req ForgotPasswordRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	if err := h.auth.ForgotPassword(h.ctx, req.Email); err != nil {
+		log.Error("failed to request password reset", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to request password reset"))
+
+		return
+	}
+
+	// the same answer for unknown emails does not reveal who has an account
+	render.JSON(w, r, resp.OK("If the account exists, a password reset email has been sent"))
+}
+
+func (h *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.ResetPassword"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req ResetPasswordRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	if err := h.auth.ResetPassword(h.ctx, req.Token, req.Password); err != nil {
+		var policyErr *password.PolicyError
+		if errors.As(err, &policyErr) {
+			log.Warn("weak password", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error(policyErr.Error()))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidResetToken) {
+			log.Warn("invalid reset token", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid or expired reset token"))
+
+			return
+		}
+
+		log.Error("failed to reset password", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to reset password"))
+
+		return
+	}
+
+	log.Info("password reset")
+
+	render.JSON(w, r, resp.OK("Password successfully reset"))
+}
+
+func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.VerifyEmail"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		log.Error("verification token is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("token is required"))
+
+		return
+	}
+
+	if err := h.auth.VerifyEmail(h.ctx, token); err != nil {
+		if errors.Is(err, authService.ErrInvalidVerifyToken) {
+			log.Warn("invalid verification token", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid or expired verification token"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrUserExists) {
+			log.Warn("new email is already taken", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("email is already taken"))
+
+			return
+		}
+
+		log.Error("failed to verify email", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to verify email"))
+
+		return
+	}
+
+	log.Info("email verified")
+
+	render.JSON(w, r, resp.OK("Email successfully verified"))
+}
+
+func (h *AuthHandler) ResendVerification(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.auth.ResendVerification"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req ResendVerificationRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	if err := h.auth.ResendVerification(h.ctx, req.Email); err != nil {
+		log.Error("failed to resend verification email", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to resend verification email"))
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("If the account exists and is not verified, a verification email has been sent"))
+}
+
+// clientIP returns the address of the client the request came from, it is
+// the one forwarded by the proxy only when http_server.trust_proxy is set.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
+
+func decodeRefreshRequest(w http.ResponseWriter, r *http.Request, log *slog.Logger) (RefreshRequest, bool) {
+	var req RefreshRequest
+
+	return req, decodeRequest(w, r, log, &req)
+}
+
+// decodeRequest decodes and validates the request body into req, writing
+// the error response and returning false when it is not acceptable.
+func decodeRequest(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return false
+	}
 
-	render.JSON(w, r, responseOK(token))
+	return true
 }
 
-func responseOK(token string) Response {
+func responseOK(pair models.TokenPair) Response {
 	return Response{
-		Response: resp.OK("You got token"),
-		Token:    token,
+		Response:     resp.OK("You got token"),
+		Token:        pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    pair.ExpiresAt,
 	}
 }
@@ -5,14 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/auth"
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/auth/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
 	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
 	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/Muaz717/todo-app/internal/lib/password"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -58,6 +65,26 @@ func TestRegisterNewUser(t *testing.T) {
 			statusCode: http.StatusBadRequest,
 			respError:  "field Email is not a valid Email",
 		},
+		{
+			name: "Weak password",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusBadRequest,
+			respError:  "weak password: found in a list of breached passwords",
+			mockError:  fmt.Errorf("wrapped: %w", &password.PolicyError{Reason: "found in a list of breached passwords"}),
+		},
+		{
+			name: "User exists",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusConflict,
+			respError:  "user already exists",
+			mockError:  authService.ErrUserExists,
+		},
 		{
 			name: "RegisterNewUser error",
 			req: auth.Request{
@@ -152,6 +179,56 @@ func TestLogin(t *testing.T) {
 			statusCode: http.StatusBadRequest,
 			respError:  "field Email is not a valid Email",
 		},
+		{
+			name: "Invalid credentials",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid email or password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name: "Email not verified",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "email is not verified",
+			mockError:  authService.ErrEmailNotVerified,
+		},
+		{
+			name: "Account disabled",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "account is disabled",
+			mockError:  authService.ErrAccountDisabled,
+		},
+		{
+			name: "Password reset required",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusForbidden,
+			respError:  "password reset is required",
+			mockError:  authService.ErrResetRequired,
+		},
+		{
+			name: "Too many attempts",
+			req: auth.Request{
+				Email:    "test@mail.ru",
+				Password: "test_password",
+			},
+			statusCode: http.StatusTooManyRequests,
+			respError:  "too many login attempts, try again later",
+			mockError:  &authService.LockedError{Until: time.Now().Add(time.Minute)},
+		},
 		{
 			name: "Login error",
 			req: auth.Request{
@@ -175,8 +252,8 @@ func TestLogin(t *testing.T) {
 
 			if tt.respError == "" || tt.mockError != nil {
 				authMock.
-					On("Login", ctx, tt.req.Email, tt.req.Password).
-					Return("", tt.mockError)
+					On("Login", ctx, tt.req.Email, tt.req.Password, "192.0.2.1").
+					Return(models.LoginResult{}, tt.mockError)
 			}
 
 			authHandler := auth.New(ctx, log, authMock)
@@ -200,7 +277,659 @@ func TestLogin(t *testing.T) {
 			assert.Equal(t, rr.Code, tt.statusCode)
 
 			assert.Equal(t, tt.respError, resp.Error)
+
+			if tt.statusCode == http.StatusTooManyRequests {
+				retryAfter, err := strconv.Atoi(rr.Header().Get("Retry-After"))
+				require.NoError(t, err)
+				assert.InDelta(t, 60, retryAfter, 1)
+			}
+		})
+
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.RefreshRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty refresh token",
+			statusCode: http.StatusBadRequest,
+			respError:  "field RefreshToken is a required field",
+		},
+		{
+			name:       "Invalid token",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid refresh token",
+			mockError:  authService.ErrInvalidToken,
+		},
+		{
+			name:       "Reused token",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid refresh token",
+			mockError:  authService.ErrTokenReused,
+		},
+		{
+			name:       "Refresh error",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to refresh token",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			pair := models.TokenPair{AccessToken: "access_token", RefreshToken: "next_refresh_token"}
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("Refresh", ctx, tt.req.RefreshToken).
+					Return(pair, tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.Refresh
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/refresh", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp auth.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, pair.AccessToken, resp.Token)
+				require.Equal(t, pair.RefreshToken, resp.RefreshToken)
+			}
+		})
+	}
+}
+
+func TestLogout(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.RefreshRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty refresh token",
+			statusCode: http.StatusBadRequest,
+			respError:  "field RefreshToken is a required field",
+		},
+		{
+			name:       "Invalid token",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid refresh token",
+			mockError:  authService.ErrInvalidToken,
+		},
+		{
+			name:       "Logout error",
+			req:        auth.RefreshRequest{RefreshToken: "refresh_token"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to logout",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("Logout", ctx, tt.req.RefreshToken).
+					Return(tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.Logout
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestLogoutAll(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "LogoutAll error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to logout",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			const userId = int64(1)
+
+			authMock := mocks.NewAuth(t)
+			authMock.
+				On("LogoutAll", ctx, userId).
+				Return(tt.mockError)
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.LogoutAll
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/logout-all", nil)
+			req = req.WithContext(context.WithValue(req.Context(), identification.Uid("user_id"), userId))
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestForgotPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.ForgotPasswordRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.ForgotPasswordRequest{Email: "user@example.com"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty email",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is a required field",
+		},
+		{
+			name:       "Invalid email",
+			req:        auth.ForgotPasswordRequest{Email: "user"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is not a valid Email",
+		},
+		{
+			name:       "ForgotPassword error",
+			req:        auth.ForgotPasswordRequest{Email: "user@example.com"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to request password reset",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("ForgotPassword", ctx, tt.req.Email).
+					Return(tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.ForgotPassword
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/forgot", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.ResetPasswordRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.ResetPasswordRequest{Token: "reset_token", Password: "new_password"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty token",
+			req:        auth.ResetPasswordRequest{Password: "new_password"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Token is a required field",
+		},
+		{
+			name:       "Empty password",
+			req:        auth.ResetPasswordRequest{Token: "reset_token"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Password is a required field",
+		},
+		{
+			name:       "Weak password",
+			req:        auth.ResetPasswordRequest{Token: "reset_token", Password: "short"},
+			statusCode: http.StatusBadRequest,
+			respError:  "weak password: must be at least 8 characters long",
+			mockError:  &password.PolicyError{Reason: "must be at least 8 characters long"},
+		},
+		{
+			name:       "Invalid token",
+			req:        auth.ResetPasswordRequest{Token: "reset_token", Password: "new_password"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid or expired reset token",
+			mockError:  authService.ErrInvalidResetToken,
+		},
+		{
+			name:       "ResetPassword error",
+			req:        auth.ResetPasswordRequest{Token: "reset_token", Password: "new_password"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to reset password",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("ResetPassword", ctx, tt.req.Token, tt.req.Password).
+					Return(tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.ResetPassword
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/password/reset", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			token:      "verify_token",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty token",
+			statusCode: http.StatusBadRequest,
+			respError:  "token is required",
+		},
+		{
+			name:       "Invalid token",
+			token:      "verify_token",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid or expired verification token",
+			mockError:  authService.ErrInvalidVerifyToken,
+		},
+		{
+			name:       "New email taken",
+			token:      "verify_token",
+			statusCode: http.StatusConflict,
+			respError:  "email is already taken",
+			mockError:  authService.ErrUserExists,
+		},
+		{
+			name:       "VerifyEmail error",
+			token:      "verify_token",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to verify email",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("VerifyEmail", ctx, tt.token).
+					Return(tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.VerifyEmail
+
+			req := httptest.NewRequest(http.MethodGet, "/auth/verify?token="+tt.token, nil)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestResendVerification(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.ResendVerificationRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.ResendVerificationRequest{Email: "user@example.com"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty email",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is a required field",
+		},
+		{
+			name:       "ResendVerification error",
+			req:        auth.ResendVerificationRequest{Email: "user@example.com"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to resend verification email",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("ResendVerification", ctx, tt.req.Email).
+					Return(tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.ResendVerification
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/verify/resend", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
 		})
+	}
+}
+
+func TestLoginTwoFactorChallenge(t *testing.T) {
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+
+	authMock := mocks.NewAuth(t)
+
+	req := auth.Request{Email: "test@mail.ru", Password: "test_password"}
+	result := models.LoginResult{
+		ChallengeToken:     "challenge_token",
+		ChallengeExpiresAt: time.Now().Add(5 * time.Minute).UTC().Truncate(time.Second),
+	}
+
+	authMock.
+		On("Login", ctx, req.Email, req.Password, "192.0.2.1").
+		Return(result, nil)
+
+	authHandler := auth.New(ctx, log, authMock)
 
+	var input bytes.Buffer
+	require.NoError(t, json.NewEncoder(&input).Encode(req))
+
+	rr := httptest.NewRecorder()
+	authHandler.Login(rr, httptest.NewRequest(http.MethodPost, "/auth/sign-in", &input))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var resp auth.ChallengeResponse
+
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+	assert.True(t, resp.TwoFactorRequired)
+	assert.Equal(t, result.ChallengeToken, resp.ChallengeToken)
+	assert.True(t, result.ChallengeExpiresAt.Equal(resp.ExpiresAt))
+}
+
+func TestVerifyTwoFactor(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        auth.TwoFactorRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        auth.TwoFactorRequest{ChallengeToken: "challenge_token", Code: "123456"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty code",
+			req:        auth.TwoFactorRequest{ChallengeToken: "challenge_token"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Code is a required field",
+		},
+		{
+			name:       "Invalid challenge",
+			req:        auth.TwoFactorRequest{ChallengeToken: "challenge_token", Code: "123456"},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid or expired challenge",
+			mockError:  authService.ErrInvalidChallenge,
+		},
+		{
+			name:       "Invalid code",
+			req:        auth.TwoFactorRequest{ChallengeToken: "challenge_token", Code: "123456"},
+			statusCode: http.StatusUnauthorized,
+			respError:  "invalid two-factor code",
+			mockError:  authService.ErrInvalidCode,
+		},
+		{
+			name:       "VerifyTwoFactor error",
+			req:        auth.TwoFactorRequest{ChallengeToken: "challenge_token", Code: "123456"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to verify two-factor code",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			authMock := mocks.NewAuth(t)
+
+			pair := models.TokenPair{AccessToken: "access_token", RefreshToken: "refresh_token"}
+
+			if tt.respError == "" || tt.mockError != nil {
+				authMock.
+					On("VerifyTwoFactor", ctx, tt.req.ChallengeToken, tt.req.Code).
+					Return(pair, tt.mockError)
+			}
+
+			authHandler := auth.New(ctx, log, authMock)
+			handler := authHandler.VerifyTwoFactor
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/verify", &input)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp auth.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, pair.AccessToken, resp.Token)
+				require.Equal(t, pair.RefreshToken, resp.RefreshToken)
+			}
+		})
 	}
 }
@@ -5,6 +5,7 @@ package mocks
 import (
 	context "context"
 
+	models "github.com/Muaz717/todo-app/internal/domain/models"
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,27 +14,109 @@ type Auth struct {
 	mock.Mock
 }
 
-// Login provides a mock function with given fields: ctx, email, password
-func (_m *Auth) Login(ctx context.Context, email string, password string) (string, error) {
-	ret := _m.Called(ctx, email, password)
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *Auth) ForgotPassword(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Login provides a mock function with given fields: ctx, email, password, ip
+func (_m *Auth) Login(ctx context.Context, email string, password string, ip string) (models.LoginResult, error) {
+	ret := _m.Called(ctx, email, password, ip)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Login")
 	}
 
-	var r0 string
+	var r0 models.LoginResult
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
-		return rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (models.LoginResult, error)); ok {
+		return rf(ctx, email, password, ip)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
-		r0 = rf(ctx, email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) models.LoginResult); ok {
+		r0 = rf(ctx, email, password, ip)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(models.LoginResult)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
-		r1 = rf(ctx, email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, email, password, ip)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Logout provides a mock function with given fields: ctx, refreshToken
+func (_m *Auth) Logout(ctx context.Context, refreshToken string) error {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LogoutAll provides a mock function with given fields: ctx, userId
+func (_m *Auth) LogoutAll(ctx context.Context, userId int64) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LogoutAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Refresh provides a mock function with given fields: ctx, refreshToken
+func (_m *Auth) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Refresh")
+	}
+
+	var r0 models.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (models.TokenPair, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) models.TokenPair); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		r0 = ret.Get(0).(models.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -69,6 +152,88 @@ func (_m *Auth) RegisterNewUser(ctx context.Context, email string, password stri
 	return r0, r1
 }
 
+// ResendVerification provides a mock function with given fields: ctx, email
+func (_m *Auth) ResendVerification(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *Auth) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *Auth) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// VerifyTwoFactor provides a mock function with given fields: ctx, challengeToken, code
+func (_m *Auth) VerifyTwoFactor(ctx context.Context, challengeToken string, code string) (models.TokenPair, error) {
+	ret := _m.Called(ctx, challengeToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyTwoFactor")
+	}
+
+	var r0 models.TokenPair
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (models.TokenPair, error)); ok {
+		return rf(ctx, challengeToken, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) models.TokenPair); ok {
+		r0 = rf(ctx, challengeToken, code)
+	} else {
+		r0 = ret.Get(0).(models.TokenPair)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, challengeToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewAuth creates a new instance of Auth. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewAuth(t interface {
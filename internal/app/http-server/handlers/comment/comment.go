@@ -0,0 +1,270 @@
+package comment
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	commentsrv "github.com/Muaz717/todo-app/internal/app/services/comment"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Comment
+type Comment interface {
+	Create(ctx context.Context, userId int64, itemId int64, parentId *int64, body string) (int64, error)
+	Comments(ctx context.Context, userId int64, itemId int64) ([]models.Comment, error)
+	Update(ctx context.Context, userId int64, itemId int64, commentId int64, body string) error
+	Delete(ctx context.Context, userId int64, itemId int64, commentId int64) error
+}
+
+type CommentHandler struct {
+	ctx     context.Context
+	log     *slog.Logger
+	comment Comment
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	comment Comment,
+) *CommentHandler {
+	return &CommentHandler{
+		ctx:     ctx,
+		log:     log,
+		comment: comment,
+	}
+}
+
+// Request is a new comment, ParentId makes it a reply to another comment on the item.
+type Request struct {
+	Body     string `json:"body" validate:"required,max=10000"`
+	ParentId *int64 `json:"parent_id,omitempty"`
+}
+
+type UpdateRequest struct {
+	Body string `json:"body" validate:"required,max=10000"`
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+func (h *CommentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.comment.Create"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, itemId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req Request
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	commentId, err := h.comment.Create(h.ctx, userId, itemId, req.ParentId, req.Body)
+	if err != nil {
+		writeError(w, r, log, err, "failed to create comment")
+
+		return
+	}
+
+	log.Info("comment created", slog.Int64("comment_id", commentId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Comment successfully created"),
+		Id:       commentId,
+	})
+}
+
+// Comments lists comment threads of the item in the path.
+func (h *CommentHandler) Comments(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.comment.Comments"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, itemId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	comments, err := h.comment.Comments(h.ctx, userId, itemId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get comments")
+
+		return
+	}
+
+	render.JSON(w, r, comments)
+}
+
+func (h *CommentHandler) Update(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.comment.Update"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, itemId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	commentId, ok := commentParam(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req UpdateRequest
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	if err := h.comment.Update(h.ctx, userId, itemId, commentId, req.Body); err != nil {
+		writeError(w, r, log, err, "failed to update comment")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Comment successfully updated"))
+}
+
+func (h *CommentHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.comment.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, itemId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	commentId, ok := commentParam(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.comment.Delete(h.ctx, userId, itemId, commentId); err != nil {
+		writeError(w, r, log, err, "failed to delete comment")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Comment successfully deleted"))
+}
+
+// target returns id of the user making the request and id of the item in the path.
+func (h *CommentHandler) target(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, int64, bool) {
+	itemId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return 0, 0, false
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return 0, 0, false
+	}
+
+	return userId, itemId, true
+}
+
+func commentParam(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	commentId, err := strconv.ParseInt(chi.URLParam(r, "commentId"), 10, 64)
+	if err != nil {
+		log.Error("invalid comment id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid comment id"))
+
+		return 0, false
+	}
+
+	return commentId, true
+}
+
+// decode reads and validates the request body into req.
+func decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return false
+	}
+
+	return true
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, msg string) {
+	for _, known := range []error{commentsrv.ErrItemNotFound, commentsrv.ErrCommentNotFound} {
+		if errors.Is(err, known) {
+			log.Warn(known.Error(), sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error(known.Error()))
+
+			return
+		}
+	}
+
+	log.Error(msg, sl.Err(err))
+
+	w.WriteHeader(http.StatusInternalServerError)
+	render.JSON(w, r, resp.Error(msg))
+}
@@ -0,0 +1,332 @@
+package comment_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/comment"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/comment/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	commentsrv "github.com/Muaz717/todo-app/internal/app/services/comment"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	parentId := int64(2)
+
+	tests := []struct {
+		name       string
+		req        comment.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        comment.Request{Body: "@bob@mail.ru take a look"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Reply",
+			req:        comment.Request{Body: "done", ParentId: &parentId},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty body",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Body is a required field",
+		},
+		{
+			name:       "Item not found",
+			req:        comment.Request{Body: "hello"},
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  commentsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Parent not found",
+			req:        comment.Request{Body: "hello", ParentId: &parentId},
+			statusCode: http.StatusNotFound,
+			respError:  "comment not found",
+			mockError:  commentsrv.ErrCommentNotFound,
+		},
+		{
+			name:       "Create error",
+			req:        comment.Request{Body: "hello"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create comment",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			commentMock := mocks.NewComment(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				commentMock.
+					On("Create", ctx, int64(1), int64(5), tt.req.ParentId, tt.req.Body).
+					Return(int64(3), tt.mockError)
+			}
+
+			handler := comment.New(ctx, log, commentMock).Create
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/5/comments", &body)
+			req = withUser(withParams(req, "id", "5"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp comment.CreateResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, int64(3), resp.Id)
+			}
+		})
+	}
+}
+
+func TestCommentsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			id:         "5",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "five",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Item not found",
+			id:         "5",
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  commentsrv.ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			commentMock := mocks.NewComment(t)
+
+			parentId := int64(3)
+			comments := []models.Comment{{
+				Id:      3,
+				ItemId:  5,
+				UserId:  1,
+				Body:    "hello",
+				Replies: []models.Comment{{Id: 4, ItemId: 5, ParentId: &parentId, UserId: 2, Body: "hi"}},
+			}}
+
+			if tt.respError == "" || tt.mockError != nil {
+				commentMock.
+					On("Comments", ctx, int64(1), int64(5)).
+					Return(comments, tt.mockError)
+			}
+
+			handler := comment.New(ctx, log, commentMock).Comments
+
+			req := httptest.NewRequest(http.MethodGet, "/api/items/"+tt.id+"/comments", nil)
+			req = withUser(withParams(req, "id", tt.id), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.Comment
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, comments, got)
+		})
+	}
+}
+
+func TestUpdateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		commentId  string
+		req        comment.UpdateRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			commentId:  "3",
+			req:        comment.UpdateRequest{Body: "edited"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid comment id",
+			commentId:  "three",
+			req:        comment.UpdateRequest{Body: "edited"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid comment id",
+		},
+		{
+			name:       "Empty body",
+			commentId:  "3",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Body is a required field",
+		},
+		{
+			name:       "Not the author",
+			commentId:  "3",
+			req:        comment.UpdateRequest{Body: "edited"},
+			statusCode: http.StatusNotFound,
+			respError:  "comment not found",
+			mockError:  commentsrv.ErrCommentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			commentMock := mocks.NewComment(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				commentMock.
+					On("Update", ctx, int64(1), int64(5), int64(3), tt.req.Body).
+					Return(tt.mockError)
+			}
+
+			handler := comment.New(ctx, log, commentMock).Update
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/items/5/comments/"+tt.commentId, &body)
+			req = withUser(withParams(req, "id", "5", "commentId", tt.commentId), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not the author",
+			statusCode: http.StatusNotFound,
+			respError:  "comment not found",
+			mockError:  commentsrv.ErrCommentNotFound,
+		},
+		{
+			name:       "Delete error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to delete comment",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			commentMock := mocks.NewComment(t)
+
+			commentMock.
+				On("Delete", ctx, int64(1), int64(5), int64(3)).
+				Return(tt.mockError)
+
+			handler := comment.New(ctx, log, commentMock).Delete
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/items/5/comments/3", nil)
+			req = withUser(withParams(req, "id", "5", "commentId", "3"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
+
+// withParams sets path parameters given as name and value pairs.
+func withParams(r *http.Request, params ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(params); i += 2 {
+		rctx.URLParams.Add(params[i], params[i+1])
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
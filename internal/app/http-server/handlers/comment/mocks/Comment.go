@@ -0,0 +1,123 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Comment is an autogenerated mock type for the Comment type
+type Comment struct {
+	mock.Mock
+}
+
+// Comments provides a mock function with given fields: ctx, userId, itemId
+func (_m *Comment) Comments(ctx context.Context, userId int64, itemId int64) ([]models.Comment, error) {
+	ret := _m.Called(ctx, userId, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Comments")
+	}
+
+	var r0 []models.Comment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Comment, error)); ok {
+		return rf(ctx, userId, itemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Comment); ok {
+		r0 = rf(ctx, userId, itemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Comment)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, itemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userId, itemId, parentId, body
+func (_m *Comment) Create(ctx context.Context, userId int64, itemId int64, parentId *int64, body string) (int64, error) {
+	ret := _m.Called(ctx, userId, itemId, parentId, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *int64, string) (int64, error)); ok {
+		return rf(ctx, userId, itemId, parentId, body)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, *int64, string) int64); ok {
+		r0 = rf(ctx, userId, itemId, parentId, body)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, *int64, string) error); ok {
+		r1 = rf(ctx, userId, itemId, parentId, body)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userId, itemId, commentId
+func (_m *Comment) Delete(ctx context.Context, userId int64, itemId int64, commentId int64) error {
+	ret := _m.Called(ctx, userId, itemId, commentId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, commentId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: ctx, userId, itemId, commentId, body
+func (_m *Comment) Update(ctx context.Context, userId int64, itemId int64, commentId int64, body string) error {
+	ret := _m.Called(ctx, userId, itemId, commentId, body)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, string) error); ok {
+		r0 = rf(ctx, userId, itemId, commentId, body)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewComment creates a new instance of Comment. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewComment(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Comment {
+	mock := &Comment{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,15 +2,24 @@ package item
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	itemsrv "github.com/Muaz717/todo-app/internal/app/services/item"
 	"github.com/Muaz717/todo-app/internal/domain/models"
 	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
+	"github.com/Muaz717/todo-app/internal/lib/etag"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
@@ -21,10 +30,24 @@ type Item interface {
 	Create(
 		ctx context.Context,
 		userId int64,
-		title string,
-		description string,
+		input models.CreateItemInput,
 	) (int64, error)
-	AllItems(ctx context.Context, userId int64) ([]models.Item, error)
+	AllItems(ctx context.Context, userId int64, filter models.ItemFilter) (models.ItemPage, error)
+	Item(ctx context.Context, userId int64, itemId int64) (models.Item, error)
+	History(ctx context.Context, userId int64, itemId int64) ([]models.ItemEvent, error)
+	Update(
+		ctx context.Context,
+		userId int64,
+		itemId int64,
+		input models.UpdateItemInput,
+	) (int64, error)
+	Complete(ctx context.Context, userId int64, itemId int64, cascade bool, version int64) (int64, error)
+	Reopen(ctx context.Context, userId int64, itemId int64, version int64) error
+	Delete(ctx context.Context, userId int64, itemId int64, version int64) error
+	Trash(ctx context.Context, userId int64) ([]models.Item, error)
+	Restore(ctx context.Context, userId int64, itemId int64, version int64) error
+	AttachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error
+	DetachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error
 }
 
 type ItemHandler struct {
@@ -46,8 +69,16 @@ func New(
 }
 
 type Request struct {
-	Title       string `json:"title" validate:"required"`
-	Description string `json:"description" validate:"required"`
+	Title       string     `json:"title" validate:"required"`
+	Description string     `json:"description" validate:"required"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	RemindAt    *time.Time `json:"remind_at,omitempty"`
+	Priority    int        `json:"priority" validate:"min=0,max=3"`
+	ListId      *int64     `json:"list_id,omitempty"`
+	ParentId    *int64     `json:"parent_id,omitempty"`
+	RRule       string     `json:"rrule,omitempty"`
+	WorkspaceId *int64     `json:"workspace_id,omitempty"`
+	AssigneeId  *int64     `json:"assignee_id,omitempty"`
 }
 
 func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
@@ -99,8 +130,86 @@ func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	itemId, err := h.item.Create(h.ctx, userId, req.Title, req.Description)
+	input := models.CreateItemInput{
+		Title:       req.Title,
+		Description: req.Description,
+		DueAt:       req.DueAt,
+		RemindAt:    req.RemindAt,
+		Priority:    req.Priority,
+		ListId:      req.ListId,
+		ParentId:    req.ParentId,
+		RRule:       req.RRule,
+		WorkspaceId: req.WorkspaceId,
+		AssigneeId:  req.AssigneeId,
+	}
+
+	itemId, err := h.item.Create(h.ctx, userId, input)
 	if err != nil {
+		if errors.Is(err, itemsrv.ErrParentNotFound) {
+			log.Warn("parent item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("parent item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrMaxDepthExceeded) {
+			log.Warn("max subtask depth exceeded", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("max subtask depth exceeded"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrInvalidRRule) {
+			log.Warn("invalid recurrence rule", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid recurrence rule"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrDueAtRequired) {
+			log.Warn("recurring item without due date", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("recurring item requires due date"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrWorkspaceNotFound) {
+			log.Warn("workspace not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("workspace not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrAssigneeNotMember) {
+			log.Warn("assignee is not a workspace member", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("assignee is not a workspace member"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrWorkspaceMismatch) {
+			log.Warn("parent, list and workspace differ", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("parent, list and workspace of the item differ"))
+
+			return
+		}
+
 		log.Error("failed to create item", sl.Err(err))
 
 		w.WriteHeader(http.StatusInternalServerError)
@@ -111,7 +220,10 @@ func (h *ItemHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	log.Info("item created", slog.Int64("item_id", itemId), slog.Int64("user_id", userId))
 
-	render.JSON(w, r, resp.OK("Item successfully created"))
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Item successfully created"),
+		Id:       itemId,
+	})
 }
 
 func (h *ItemHandler) AllItems(w http.ResponseWriter, r *http.Request) {
@@ -132,7 +244,17 @@ func (h *ItemHandler) AllItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items, err := h.item.AllItems(h.ctx, userId)
+	filter, err := parseItemFilter(r, userId)
+	if err != nil {
+		log.Error("invalid query", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error(err.Error()))
+
+		return
+	}
+
+	page, err := h.item.AllItems(h.ctx, userId, filter)
 	if err != nil {
 		log.Error("failed to get items", sl.Err(err))
 
@@ -142,7 +264,924 @@ func (h *ItemHandler) AllItems(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the tag covers the whole page, so any change seen in it is a new tag
+	body, err := json.Marshal(page)
+	if err != nil {
+		log.Error("failed to encode items", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get items"))
+
+		return
+	}
+
+	tag := etag.Body(body)
+	w.Header().Set("ETag", tag)
+
+	if match := r.Header.Get("If-None-Match"); match != "" && !etag.NoneMatch(match, tag) {
+		w.WriteHeader(http.StatusNotModified)
+
+		return
+	}
+
 	log.Info("All lists showed")
 
-	render.JSON(w, r, items)
+	render.JSON(w, r, page)
+}
+
+const (
+	defaultItemsLimit = 50
+	maxItemsLimit     = 200
+)
+
+// parseItemFilter reads listing options from the query string, assignee=me
+// stands for the user making the request. Returned errors are meant to be
+// shown to the client.
+func parseItemFilter(r *http.Request, userId int64) (models.ItemFilter, error) {
+	query := r.URL.Query()
+
+	filter := models.ItemFilter{
+		Tag:   query.Get("tag"),
+		Query: query.Get("q"),
+		Sort:  models.ItemSortCreated,
+		Limit: defaultItemsLimit,
+	}
+
+	switch query.Get("status") {
+	case "", "all":
+	case "open":
+		done := false
+		filter.Done = &done
+	case "done":
+		done := true
+		filter.Done = &done
+	default:
+		return models.ItemFilter{}, errors.New("invalid status")
+	}
+
+	if v := query.Get("due_from"); v != "" {
+		dueFrom, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return models.ItemFilter{}, errors.New("invalid due_from")
+		}
+		filter.DueFrom = &dueFrom
+	}
+
+	if v := query.Get("due_to"); v != "" {
+		dueTo, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return models.ItemFilter{}, errors.New("invalid due_to")
+		}
+		filter.DueTo = &dueTo
+	}
+
+	if v := query.Get("priority"); v != "" {
+		priority, err := strconv.Atoi(v)
+		if err != nil || priority < models.PriorityNone || priority > models.PriorityHigh {
+			return models.ItemFilter{}, errors.New("invalid priority")
+		}
+		filter.Priority = &priority
+	}
+
+	switch v := query.Get("assignee"); v {
+	case "":
+	case "me":
+		filter.AssigneeId = &userId
+	default:
+		assigneeId, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return models.ItemFilter{}, errors.New("invalid assignee")
+		}
+		filter.AssigneeId = &assigneeId
+	}
+
+	if v := query.Get("workspace"); v != "" {
+		workspaceId, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return models.ItemFilter{}, errors.New("invalid workspace")
+		}
+		filter.WorkspaceId = &workspaceId
+	}
+
+	switch v := query.Get("sort"); v {
+	case "":
+	case models.ItemSortCreated, models.ItemSortDueAt, models.ItemSortPriority, models.ItemSortTitle:
+		filter.Sort = v
+	default:
+		return models.ItemFilter{}, errors.New("invalid sort")
+	}
+
+	switch query.Get("order") {
+	case "", "asc":
+	case "desc":
+		filter.Desc = true
+	default:
+		return models.ItemFilter{}, errors.New("invalid order")
+	}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxItemsLimit {
+			return models.ItemFilter{}, errors.New("invalid limit")
+		}
+		filter.Limit = limit
+	}
+
+	if v := query.Get("cursor"); v != "" {
+		after, err := cursor.Decode(v)
+		if err != nil || !validItemCursor(after, filter) {
+			return models.ItemFilter{}, errors.New("invalid cursor")
+		}
+		filter.After = &after
+	}
+
+	return filter, nil
+}
+
+// validItemCursor reports whether the cursor was made for the order of the
+// filter and holds a value the sort column can be compared with.
+func validItemCursor(c models.Cursor, filter models.ItemFilter) bool {
+	if c.Sort != filter.Sort || c.Desc != filter.Desc {
+		return false
+	}
+
+	switch c.Sort {
+	case models.ItemSortDueAt:
+		if c.Value == "infinity" {
+			return true
+		}
+		t, err := time.Parse(time.RFC3339Nano, c.Value)
+
+		return err == nil && t.Year() >= 1
+	case models.ItemSortPriority:
+		_, err := strconv.ParseInt(c.Value, 10, 16)
+
+		return err == nil
+	case models.ItemSortTitle:
+		return utf8.ValidString(c.Value) && !strings.ContainsRune(c.Value, 0)
+	default:
+		return c.Value == ""
+	}
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+// ReplaceRequest is the whole item sent with PUT, omitted optional fields are cleared.
+type ReplaceRequest struct {
+	Title       string     `json:"title" validate:"required"`
+	Description string     `json:"description" validate:"required"`
+	DueAt       *time.Time `json:"due_at,omitempty"`
+	RemindAt    *time.Time `json:"remind_at,omitempty"`
+	Priority    int        `json:"priority" validate:"min=0,max=3"`
+	ListId      *int64     `json:"list_id,omitempty"`
+	RRule       string     `json:"rrule,omitempty"`
+	AssigneeId  *int64     `json:"assignee_id,omitempty"`
+}
+
+type UpdateRequest struct {
+	Title         *string    `json:"title,omitempty" validate:"omitnil,min=1"`
+	Description   *string    `json:"description,omitempty" validate:"omitnil,min=1"`
+	DueAt         *time.Time `json:"due_at,omitempty"`
+	ClearDueAt    bool       `json:"clear_due_at,omitempty"`
+	RemindAt      *time.Time `json:"remind_at,omitempty"`
+	ClearRemindAt bool       `json:"clear_remind_at,omitempty"`
+	Priority      *int       `json:"priority,omitempty" validate:"omitnil,min=0,max=3"`
+	ListId        *int64     `json:"list_id,omitempty"`
+	Position      *int       `json:"position,omitempty" validate:"omitnil,min=0"`
+	RRule         *string    `json:"rrule,omitempty"`
+	AssigneeId    *int64     `json:"assignee_id,omitempty"`
+	ClearAssignee bool       `json:"clear_assignee,omitempty"`
+}
+
+type CompleteResponse struct {
+	resp.Response
+	NextId int64 `json:"next_id,omitempty"`
+}
+
+func (h *ItemHandler) Item(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Item"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	item, err := h.item.Item(h.ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+
+		log.Error("failed to get item", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get item"))
+
+		return
+	}
+
+	log.Info("item showed", slog.Int64("item_id", itemId))
+
+	w.Header().Set("ETag", etag.Version(item.Version))
+	render.JSON(w, r, item)
+}
+
+// History lists changes made to the item in the path, oldest first.
+func (h *ItemHandler) History(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.History"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	events, err := h.item.History(h.ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+
+		log.Error("failed to get item history", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get item history"))
+
+		return
+	}
+
+	render.JSON(w, r, events)
+}
+
+func (h *ItemHandler) Update(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Update"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	var req ReplaceRequest
+
+	err = render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	input := models.UpdateItemInput{
+		Title:         &req.Title,
+		Description:   &req.Description,
+		DueAt:         req.DueAt,
+		ClearDueAt:    req.DueAt == nil,
+		RemindAt:      req.RemindAt,
+		ClearRemindAt: req.RemindAt == nil,
+		Priority:      &req.Priority,
+		ListId:        req.ListId,
+		ClearList:     req.ListId == nil,
+		RRule:         &req.RRule,
+		AssigneeId:    req.AssigneeId,
+		ClearAssignee: req.AssigneeId == nil,
+	}
+
+	h.update(w, r, log, itemId, input)
+}
+
+func (h *ItemHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Patch"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	var req UpdateRequest
+
+	err = render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	if req.Title == nil && req.Description == nil && req.DueAt == nil &&
+		!req.ClearDueAt && req.RemindAt == nil && !req.ClearRemindAt && req.Priority == nil &&
+		req.ListId == nil && req.Position == nil && req.RRule == nil &&
+		req.AssigneeId == nil && !req.ClearAssignee {
+		log.Error("nothing to update")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("nothing to update"))
+
+		return
+	}
+
+	input := models.UpdateItemInput{
+		Title:         req.Title,
+		Description:   req.Description,
+		DueAt:         req.DueAt,
+		ClearDueAt:    req.ClearDueAt,
+		RemindAt:      req.RemindAt,
+		ClearRemindAt: req.ClearRemindAt,
+		Priority:      req.Priority,
+		ListId:        req.ListId,
+		Position:      req.Position,
+		RRule:         req.RRule,
+		AssigneeId:    req.AssigneeId,
+		ClearAssignee: req.ClearAssignee,
+	}
+
+	h.update(w, r, log, itemId, input)
+}
+
+// ifMatch returns the item version of the If-Match header every change of an item
+// requires, "*" matches whatever version the item has and is zero. The request is
+// answered when the header is missing or matches no version.
+func ifMatch(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	match := r.Header.Get("If-Match")
+	if match == "" {
+		log.Error("If-Match header is missing")
+
+		w.WriteHeader(http.StatusPreconditionRequired)
+		render.JSON(w, r, resp.Error("If-Match header is required"))
+
+		return 0, false
+	}
+
+	version, ok := etag.ParseVersion(match)
+	if !ok {
+		log.Warn("If-Match header matches no version", slog.String("if_match", match))
+
+		w.WriteHeader(http.StatusPreconditionFailed)
+		render.JSON(w, r, resp.Error("item has been modified"))
+
+		return 0, false
+	}
+
+	return version, true
+}
+
+// update applies the change on top of the version in the required If-Match header,
+// "*" overwrites whatever version the item has.
+func (h *ItemHandler) update(
+	w http.ResponseWriter,
+	r *http.Request,
+	log *slog.Logger,
+	itemId int64,
+	input models.UpdateItemInput,
+) {
+	version, ok := ifMatch(w, r, log)
+	if !ok {
+		return
+	}
+
+	input.Version = version
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	version, err = h.item.Update(h.ctx, userId, itemId, input)
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrInvalidRRule) {
+			log.Warn("invalid recurrence rule", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid recurrence rule"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrDueAtRequired) {
+			log.Warn("recurring item without due date", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("recurring item requires due date"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrAssigneeNotMember) {
+			log.Warn("assignee is not a workspace member", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("assignee is not a workspace member"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrVersionMismatch) {
+			log.Warn("item has been modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("item has been modified"))
+
+			return
+		}
+
+		log.Error("failed to update item", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to update item"))
+
+		return
+	}
+
+	log.Info("item updated", slog.Int64("item_id", itemId), slog.Int64("user_id", userId))
+
+	w.Header().Set("ETag", etag.Version(version))
+	render.JSON(w, r, resp.OK("Item successfully updated"))
+}
+
+func (h *ItemHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Complete"
+
+	h.setDone(w, r, op, true)
+}
+
+func (h *ItemHandler) Reopen(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Reopen"
+
+	h.setDone(w, r, op, false)
+}
+
+func (h *ItemHandler) setDone(w http.ResponseWriter, r *http.Request, op string, done bool) {
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	version, ok := ifMatch(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	var nextId int64
+
+	if done {
+		cascade := r.URL.Query().Get("cascade") == "true"
+
+		nextId, err = h.item.Complete(h.ctx, userId, itemId, cascade, version)
+	} else {
+		err = h.item.Reopen(h.ctx, userId, itemId, version)
+	}
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrVersionMismatch) {
+			log.Warn("item has been modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("item has been modified"))
+
+			return
+		}
+
+		log.Error("failed to change item state", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to change item state"))
+
+		return
+	}
+
+	log.Info("item state changed", slog.Int64("item_id", itemId), slog.Bool("done", done))
+
+	if done {
+		render.JSON(w, r, CompleteResponse{
+			Response: resp.OK("Item successfully completed"),
+			NextId:   nextId,
+		})
+	} else {
+		render.JSON(w, r, resp.OK("Item successfully reopened"))
+	}
+}
+
+func (h *ItemHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	version, ok := ifMatch(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	err = h.item.Delete(h.ctx, userId, itemId, version)
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrVersionMismatch) {
+			log.Warn("item has been modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("item has been modified"))
+
+			return
+		}
+
+		log.Error("failed to delete item", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to delete item"))
+
+		return
+	}
+
+	log.Info("item deleted", slog.Int64("item_id", itemId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Item successfully deleted"))
+}
+
+// Trash lists deleted items the user can restore.
+func (h *ItemHandler) Trash(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Trash"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	items, err := h.item.Trash(h.ctx, userId)
+	if err != nil {
+		log.Error("failed to get trash", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get trash"))
+
+		return
+	}
+
+	render.JSON(w, r, items)
+}
+
+func (h *ItemHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.Restore"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	version, ok := ifMatch(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	err = h.item.Restore(h.ctx, userId, itemId, version)
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrVersionMismatch) {
+			log.Warn("item has been modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("item has been modified"))
+
+			return
+		}
+
+		log.Error("failed to restore item", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to restore item"))
+
+		return
+	}
+
+	log.Info("item restored", slog.Int64("item_id", itemId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Item successfully restored"))
+}
+
+func (h *ItemHandler) AttachTag(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.AttachTag"
+
+	h.tagItem(w, r, op, true)
+}
+
+func (h *ItemHandler) DetachTag(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.item.DetachTag"
+
+	h.tagItem(w, r, op, false)
+}
+
+func (h *ItemHandler) tagItem(w http.ResponseWriter, r *http.Request, op string, attach bool) {
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	itemId, err := parseItemId(r)
+	if err != nil {
+		log.Error("invalid item id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid item id"))
+
+		return
+	}
+
+	tagId, err := strconv.ParseInt(chi.URLParam(r, "tagId"), 10, 64)
+	if err != nil {
+		log.Error("invalid tag id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid tag id"))
+
+		return
+	}
+
+	version, ok := ifMatch(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if attach {
+		err = h.item.AttachTag(h.ctx, userId, itemId, tagId, version)
+	} else {
+		err = h.item.DetachTag(h.ctx, userId, itemId, tagId, version)
+	}
+	if err != nil {
+		if errors.Is(err, itemsrv.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("item not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("tag not found"))
+
+			return
+		}
+		if errors.Is(err, itemsrv.ErrVersionMismatch) {
+			log.Warn("item has been modified", sl.Err(err))
+
+			w.WriteHeader(http.StatusPreconditionFailed)
+			render.JSON(w, r, resp.Error("item has been modified"))
+
+			return
+		}
+
+		log.Error("failed to change item tags", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to change item tags"))
+
+		return
+	}
+
+	log.Info("item tags changed", slog.Int64("item_id", itemId), slog.Int64("tag_id", tagId))
+
+	if attach {
+		render.JSON(w, r, resp.OK("Tag successfully attached"))
+	} else {
+		render.JSON(w, r, resp.OK("Tag successfully detached"))
+	}
+}
+
+func parseItemId(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
 }
@@ -8,13 +8,17 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/item"
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/item/mocks"
 	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	itemsrv "github.com/Muaz717/todo-app/internal/app/services/item"
 	"github.com/Muaz717/todo-app/internal/domain/models"
 	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
 	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -25,6 +29,7 @@ func TestCreateHandler(t *testing.T) {
 		name        string
 		title       string
 		description string
+		priority    int
 		statusCode  int
 		userId      int64
 		respError   string
@@ -51,6 +56,69 @@ func TestCreateHandler(t *testing.T) {
 			userId:     1,
 			respError:  "field Description is a required field",
 		},
+		{
+			name:        "Invalid priority",
+			title:       "test_title",
+			description: "test_description",
+			priority:    5,
+			statusCode:  http.StatusBadRequest,
+			userId:      1,
+			respError:   "field Priority is not valid",
+		},
+		{
+			name:        "List not found",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusNotFound,
+			respError:   "list not found",
+			mockError:   itemsrv.ErrListNotFound,
+		},
+		{
+			name:        "Parent not found",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusNotFound,
+			respError:   "parent item not found",
+			mockError:   itemsrv.ErrParentNotFound,
+		},
+		{
+			name:        "Max depth exceeded",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusBadRequest,
+			respError:   "max subtask depth exceeded",
+			mockError:   itemsrv.ErrMaxDepthExceeded,
+		},
+		{
+			name:        "Workspace mismatch",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusBadRequest,
+			respError:   "parent, list and workspace of the item differ",
+			mockError:   itemsrv.ErrWorkspaceMismatch,
+		},
+		{
+			name:        "Invalid rrule",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusBadRequest,
+			respError:   "invalid recurrence rule",
+			mockError:   itemsrv.ErrInvalidRRule,
+		},
+		{
+			name:        "Recurring without due date",
+			title:       "test_title",
+			description: "test_description",
+			userId:      1,
+			statusCode:  http.StatusBadRequest,
+			respError:   "recurring item requires due date",
+			mockError:   itemsrv.ErrDueAtRequired,
+		},
 		{
 			name:        "Create error",
 			title:       "test_title",
@@ -75,7 +143,7 @@ func TestCreateHandler(t *testing.T) {
 
 			if tt.respError == "" || tt.mockError != nil {
 				itemHandlerMock.
-					On("Create", ctx, mock.AnythingOfType("int64"), mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+					On("Create", ctx, mock.AnythingOfType("int64"), mock.AnythingOfType("models.CreateItemInput")).
 					Return(int64(1), tt.mockError)
 			}
 
@@ -85,6 +153,7 @@ func TestCreateHandler(t *testing.T) {
 			reqBody := item.Request{
 				Title:       tt.title,
 				Description: tt.description,
+				Priority:    tt.priority,
 			}
 
 			var input bytes.Buffer
@@ -114,8 +183,15 @@ func TestCreateHandler(t *testing.T) {
 	}
 }
 func TestAllItemsHandler(t *testing.T) {
+	done := true
+	priority := models.PriorityHigh
+	dueFrom := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	me, workspaceId := int64(1), int64(4)
+
 	tests := []struct {
 		name       string
+		query      string
+		filter     models.ItemFilter
 		statusCode int
 		userId     int64
 		respError  string
@@ -123,11 +199,102 @@ func TestAllItemsHandler(t *testing.T) {
 	}{
 		{
 			name:       "Success",
+			filter:     models.ItemFilter{Sort: models.ItemSortCreated, Limit: 50},
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:  "Filters",
+			query: "?status=done&priority=3&due_from=2024-10-01T00:00:00Z&tag=home&q=milk&sort=due_at&order=desc&limit=10",
+			filter: models.ItemFilter{
+				Done:     &done,
+				DueFrom:  &dueFrom,
+				Priority: &priority,
+				Tag:      "home",
+				Query:    "milk",
+				Sort:     models.ItemSortDueAt,
+				Desc:     true,
+				Limit:    10,
+			},
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:  "Assigned to me",
+			query: "?assignee=me&workspace=4",
+			filter: models.ItemFilter{
+				Sort:        models.ItemSortCreated,
+				Limit:       50,
+				AssigneeId:  &me,
+				WorkspaceId: &workspaceId,
+			},
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:       "Invalid assignee",
+			query:      "?assignee=someone",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid assignee",
+		},
+		{
+			name:  "Cursor",
+			query: "?sort=title&cursor=" + cursor.Encode(models.Cursor{Value: "milk", Id: 7, Sort: models.ItemSortTitle}),
+			filter: models.ItemFilter{
+				Sort:  models.ItemSortTitle,
+				Limit: 50,
+				After: &models.Cursor{Value: "milk", Id: 7, Sort: models.ItemSortTitle},
+			},
 			statusCode: http.StatusOK,
 			userId:     1,
 		},
+		{
+			name:       "Invalid sort",
+			query:      "?sort=password",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid sort",
+		},
+		{
+			name:       "Invalid limit",
+			query:      "?limit=1000",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid limit",
+		},
+		{
+			name:       "Invalid cursor",
+			query:      "?cursor=%21%21",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid cursor",
+		},
+		{
+			name:       "Cursor of another sort",
+			query:      "?sort=due_at&cursor=" + cursor.Encode(models.Cursor{Value: "milk", Id: 7, Sort: models.ItemSortTitle}),
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid cursor",
+		},
+		{
+			name: "Cursor of another order",
+			query: "?sort=title&order=desc&cursor=" +
+				cursor.Encode(models.Cursor{Value: "milk", Id: 7, Sort: models.ItemSortTitle}),
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid cursor",
+		},
+		{
+			name:       "Cursor with invalid value",
+			query:      "?sort=priority&cursor=" + cursor.Encode(models.Cursor{Value: "high", Id: 7, Sort: models.ItemSortPriority}),
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid cursor",
+		},
 		{
 			name:       "AllItems error",
+			filter:     models.ItemFilter{Sort: models.ItemSortCreated, Limit: 50},
 			userId:     1,
 			statusCode: http.StatusInternalServerError,
 			respError:  "failed to get items",
@@ -148,14 +315,14 @@ func TestAllItemsHandler(t *testing.T) {
 
 			if tt.respError == "" || tt.mockError != nil {
 				itemHandlerMock.
-					On("AllItems", ctx, mock.AnythingOfType("int64")).
-					Return([]models.Item{}, tt.mockError)
+					On("AllItems", ctx, mock.AnythingOfType("int64"), tt.filter).
+					Return(models.ItemPage{}, tt.mockError)
 			}
 
 			itemHandler := item.New(ctx, log, itemHandlerMock)
 			handler := itemHandler.AllItems
 
-			req := httptest.NewRequest(http.MethodGet, "/api/items/", nil)
+			req := httptest.NewRequest(http.MethodGet, "/api/items/"+tt.query, nil)
 
 			uidStr := identification.Uid("user_id")
 
@@ -179,3 +346,1026 @@ func TestAllItemsHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAllItemsNotModified(t *testing.T) {
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+
+	itemHandlerMock := mocks.NewItem(t)
+
+	itemHandlerMock.
+		On("AllItems", ctx, int64(1), models.ItemFilter{Sort: models.ItemSortCreated, Limit: 50}).
+		Return(models.ItemPage{Items: []models.Item{{Id: 1, Title: "Buy milk", Version: 2}}}, nil)
+
+	handler := item.New(ctx, log, itemHandlerMock).AllItems
+
+	request := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/items/", nil)
+		req = req.WithContext(context.WithValue(req.Context(), identification.Uid("user_id"), int64(1)))
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+
+		return rr
+	}
+
+	first := request("")
+	require.Equal(t, http.StatusOK, first.Code)
+
+	tag := first.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	cached := request(tag)
+	require.Equal(t, http.StatusNotModified, cached.Code)
+	require.Empty(t, cached.Body.String())
+
+	stale := request(`"stale"`)
+	require.Equal(t, http.StatusOK, stale.Code)
+	require.Equal(t, tag, stale.Header().Get("ETag"))
+}
+
+func TestItemHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		itemId     string
+		statusCode int
+		userId     int64
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			itemId:     "1",
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:       "Invalid id",
+			itemId:     "abc",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Not found",
+			itemId:     "1",
+			statusCode: http.StatusNotFound,
+			userId:     1,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Item error",
+			itemId:     "1",
+			statusCode: http.StatusInternalServerError,
+			userId:     1,
+			respError:  "failed to get item",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("Item", ctx, tt.userId, mock.AnythingOfType("int64")).
+					Return(models.Item{Version: 3}, tt.mockError)
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.Item
+
+			req := httptest.NewRequest(http.MethodGet, "/api/items/"+tt.itemId, nil)
+			req = withUserAndItem(req, tt.userId, tt.itemId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if rr.Code != http.StatusOK {
+				body := rr.Body.String()
+
+				var resp resp.Response
+				require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			require.Equal(t, `"3"`, rr.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestHistoryHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		itemId     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			itemId:     "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			itemId:     "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Not found",
+			itemId:     "1",
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "History error",
+			itemId:     "1",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get item history",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			actorId, email := int64(1), "bob@mail.ru"
+			oldValue, newValue := "2024-05-01T10:00:00Z", "2024-05-02T10:00:00Z"
+			events := []models.ItemEvent{
+				{Id: 1, ItemId: 1, ActorId: &actorId, ActorEmail: &email, Kind: models.ItemEventCreated},
+				{
+					Id:         2,
+					ItemId:     1,
+					ActorId:    &actorId,
+					ActorEmail: &email,
+					Kind:       models.ItemEventUpdated,
+					Field:      "due_at",
+					OldValue:   &oldValue,
+					NewValue:   &newValue,
+				},
+			}
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("History", ctx, int64(1), int64(1)).
+					Return(events, tt.mockError)
+			}
+
+			handler := item.New(ctx, log, itemHandlerMock).History
+
+			req := httptest.NewRequest(http.MethodGet, "/api/items/"+tt.itemId+"/history", nil)
+			req = withUserAndItem(req, 1, tt.itemId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.ItemEvent
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, events, got)
+		})
+	}
+}
+
+func TestUpdateHandler(t *testing.T) {
+	listId := int64(2)
+
+	tests := []struct {
+		name        string
+		itemId      string
+		title       string
+		description string
+		listId      *int64
+		ifMatch     string
+		statusCode  int
+		userId      int64
+		respError   string
+		mockError   error
+	}{
+		{
+			name:        "Success",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			listId:      &listId,
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusOK,
+			userId:      1,
+		},
+		{
+			name:        "Omitted list",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusOK,
+			userId:      1,
+		},
+		{
+			name:        "Invalid id",
+			itemId:      "abc",
+			title:       "test_title",
+			description: "test_description",
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusBadRequest,
+			userId:      1,
+			respError:   "invalid item id",
+		},
+		{
+			name:        "Empty title",
+			itemId:      "1",
+			description: "test_description",
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusBadRequest,
+			userId:      1,
+			respError:   "field Title is a required field",
+		},
+		{
+			name:        "Not found",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusNotFound,
+			userId:      1,
+			respError:   "item not found",
+			mockError:   itemsrv.ErrItemNotFound,
+		},
+		{
+			name:        "Update error",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			ifMatch:     `"3"`,
+			statusCode:  http.StatusInternalServerError,
+			userId:      1,
+			respError:   "failed to update item",
+			mockError:   errors.New("unexpected error"),
+		},
+		{
+			name:        "Missing If-Match",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			statusCode:  http.StatusPreconditionRequired,
+			userId:      1,
+			respError:   "If-Match header is required",
+		},
+		{
+			name:        "Stale version",
+			itemId:      "1",
+			title:       "test_title",
+			description: "test_description",
+			ifMatch:     `"2"`,
+			statusCode:  http.StatusPreconditionFailed,
+			userId:      1,
+			respError:   "item has been modified",
+			mockError:   itemsrv.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("Update", ctx, tt.userId, int64(1), mock.MatchedBy(func(input models.UpdateItemInput) bool {
+						// a list left out of the replaced item takes it out of the list
+						if tt.listId == nil {
+							return input.ClearList && input.ListId == nil
+						}
+						return !input.ClearList && input.ListId != nil && *input.ListId == *tt.listId
+					})).
+					Return(int64(4), tt.mockError)
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.Update
+
+			reqBody := item.ReplaceRequest{
+				Title:       tt.title,
+				Description: tt.description,
+				ListId:      tt.listId,
+			}
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(reqBody)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/items/"+tt.itemId, &input)
+			req = withUserAndItem(req, tt.userId, tt.itemId)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, `"4"`, rr.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestPatchHandler(t *testing.T) {
+	title := "test_title"
+	empty := ""
+
+	tests := []struct {
+		name       string
+		req        item.UpdateRequest
+		ifMatch    string
+		version    int64
+		statusCode int
+		userId     int64
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        item.UpdateRequest{Title: &title},
+			ifMatch:    `"3"`,
+			version:    3,
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:       "Any version",
+			req:        item.UpdateRequest{Title: &title},
+			ifMatch:    "*",
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:       "Weak If-Match",
+			req:        item.UpdateRequest{Title: &title},
+			ifMatch:    `W/"3"`,
+			statusCode: http.StatusPreconditionFailed,
+			userId:     1,
+			respError:  "item has been modified",
+		},
+		{
+			name:       "Nothing to update",
+			req:        item.UpdateRequest{},
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "nothing to update",
+		},
+		{
+			name:       "Empty title",
+			req:        item.UpdateRequest{Title: &empty},
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "field Title is not valid",
+		},
+		{
+			name:       "Not found",
+			req:        item.UpdateRequest{Title: &title},
+			ifMatch:    `"3"`,
+			version:    3,
+			statusCode: http.StatusNotFound,
+			userId:     1,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("Update", ctx, tt.userId, int64(1), models.UpdateItemInput{
+						Title:       tt.req.Title,
+						Description: tt.req.Description,
+						Version:     tt.version,
+					}).
+					Return(int64(4), tt.mockError)
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.Patch
+
+			var input bytes.Buffer
+			err := json.NewEncoder(&input).Encode(tt.req)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/items/1", &input)
+			req = withUserAndItem(req, tt.userId, "1")
+			req.Header.Set("If-Match", tt.ifMatch)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		itemId     string
+		statusCode int
+		userId     int64
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusOK,
+			userId:     1,
+		},
+		{
+			name:       "Invalid id",
+			itemId:     "abc",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Not found",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusNotFound,
+			userId:     1,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Delete error",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusInternalServerError,
+			userId:     1,
+			respError:  "failed to delete item",
+			mockError:  errors.New("unexpected error"),
+		},
+		{
+			name:       "Missing If-Match",
+			itemId:     "1",
+			statusCode: http.StatusPreconditionRequired,
+			userId:     1,
+			respError:  "If-Match header is required",
+		},
+		{
+			name:       "Stale version",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusPreconditionFailed,
+			userId:     1,
+			respError:  "item has been modified",
+			mockError:  itemsrv.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("Delete", ctx, tt.userId, int64(1), int64(3)).
+					Return(tt.mockError)
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.Delete
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/items/"+tt.itemId, nil)
+			req = withUserAndItem(req, tt.userId, tt.itemId)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestTrashHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Trash error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get trash",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+			items := []models.Item{{Id: 1, Title: "Buy milk", Tags: []string{}, WorkspaceId: 1, DeletedAt: &deletedAt}}
+
+			itemHandlerMock.
+				On("Trash", ctx, int64(1)).
+				Return(items, tt.mockError)
+
+			handler := item.New(ctx, log, itemHandlerMock).Trash
+
+			req := httptest.NewRequest(http.MethodGet, "/api/trash", nil)
+			req = req.WithContext(context.WithValue(req.Context(), identification.Uid("user_id"), int64(1)))
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.Item
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, items, got)
+		})
+	}
+}
+
+func TestRestoreHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		itemId     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			itemId:     "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Not in trash",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Restore error",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to restore item",
+			mockError:  errors.New("unexpected error"),
+		},
+		{
+			name:       "Missing If-Match",
+			itemId:     "1",
+			statusCode: http.StatusPreconditionRequired,
+			respError:  "If-Match header is required",
+		},
+		{
+			name:       "Stale version",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusPreconditionFailed,
+			respError:  "item has been modified",
+			mockError:  itemsrv.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On("Restore", ctx, int64(1), int64(1), int64(3)).
+					Return(tt.mockError)
+			}
+
+			handler := item.New(ctx, log, itemHandlerMock).Restore
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/"+tt.itemId+"/restore", nil)
+			req = withUserAndItem(req, 1, tt.itemId)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestSetDoneHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		method     string
+		itemId     string
+		cascade    bool
+		nextId     int64
+		statusCode int
+		userId     int64
+		respMsg    string
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Complete",
+			ifMatch:    `"3"`,
+			method:     "Complete",
+			itemId:     "1",
+			statusCode: http.StatusOK,
+			userId:     1,
+			respMsg:    "Item successfully completed",
+		},
+		{
+			name:       "Complete with cascade",
+			ifMatch:    `"3"`,
+			method:     "Complete",
+			itemId:     "1",
+			cascade:    true,
+			statusCode: http.StatusOK,
+			userId:     1,
+			respMsg:    "Item successfully completed",
+		},
+		{
+			name:       "Complete recurring",
+			ifMatch:    `"3"`,
+			method:     "Complete",
+			itemId:     "1",
+			nextId:     2,
+			statusCode: http.StatusOK,
+			userId:     1,
+			respMsg:    "Item successfully completed",
+		},
+		{
+			name:       "Reopen",
+			ifMatch:    `"3"`,
+			method:     "Reopen",
+			itemId:     "1",
+			statusCode: http.StatusOK,
+			userId:     1,
+			respMsg:    "Item successfully reopened",
+		},
+		{
+			name:       "Invalid id",
+			method:     "Complete",
+			itemId:     "abc",
+			statusCode: http.StatusBadRequest,
+			userId:     1,
+			respError:  "invalid item id",
+		},
+		{
+			name:       "Not found",
+			ifMatch:    `"3"`,
+			method:     "Reopen",
+			itemId:     "1",
+			statusCode: http.StatusNotFound,
+			userId:     1,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Complete error",
+			ifMatch:    `"3"`,
+			method:     "Complete",
+			itemId:     "1",
+			statusCode: http.StatusInternalServerError,
+			userId:     1,
+			respError:  "failed to change item state",
+			mockError:  errors.New("unexpected error"),
+		},
+		{
+			name:       "Missing If-Match",
+			method:     "Complete",
+			itemId:     "1",
+			statusCode: http.StatusPreconditionRequired,
+			userId:     1,
+			respError:  "If-Match header is required",
+		},
+		{
+			name:       "Stale version",
+			method:     "Reopen",
+			ifMatch:    `"3"`,
+			itemId:     "1",
+			statusCode: http.StatusPreconditionFailed,
+			userId:     1,
+			respError:  "item has been modified",
+			mockError:  itemsrv.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				if tt.method == "Complete" {
+					itemHandlerMock.
+						On("Complete", ctx, tt.userId, int64(1), tt.cascade, int64(3)).
+						Return(tt.nextId, tt.mockError)
+				} else {
+					itemHandlerMock.
+						On("Reopen", ctx, tt.userId, int64(1), int64(3)).
+						Return(tt.mockError)
+				}
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.Complete
+			if tt.method == "Reopen" {
+				handler = itemHandler.Reopen
+			}
+
+			target := "/api/items/" + tt.itemId + "/complete"
+			if tt.cascade {
+				target += "?cascade=true"
+			}
+
+			req := httptest.NewRequest(http.MethodPost, target, nil)
+			req = withUserAndItem(req, tt.userId, tt.itemId)
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			body := rr.Body.String()
+
+			var resp item.CompleteResponse
+
+			require.NoError(t, json.Unmarshal([]byte(body), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			require.Equal(t, tt.respError, resp.Error)
+			require.Equal(t, tt.respMsg, resp.Msg)
+			require.Equal(t, tt.nextId, resp.NextId)
+		})
+	}
+}
+
+func TestTagItemHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		ifMatch    string
+		method     string
+		tagId      string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Attach",
+			ifMatch:    `"3"`,
+			method:     "AttachTag",
+			tagId:      "2",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Detach",
+			ifMatch:    `"3"`,
+			method:     "DetachTag",
+			tagId:      "2",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid tag id",
+			method:     "AttachTag",
+			tagId:      "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid tag id",
+		},
+		{
+			name:       "Tag not found",
+			ifMatch:    `"3"`,
+			method:     "AttachTag",
+			tagId:      "2",
+			statusCode: http.StatusNotFound,
+			respError:  "tag not found",
+			mockError:  itemsrv.ErrTagNotFound,
+		},
+		{
+			name:       "Item not found",
+			ifMatch:    `"3"`,
+			method:     "DetachTag",
+			tagId:      "2",
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  itemsrv.ErrItemNotFound,
+		},
+		{
+			name:       "Missing If-Match",
+			method:     "AttachTag",
+			tagId:      "2",
+			statusCode: http.StatusPreconditionRequired,
+			respError:  "If-Match header is required",
+		},
+		{
+			name:       "Stale version",
+			method:     "DetachTag",
+			ifMatch:    `"3"`,
+			tagId:      "2",
+			statusCode: http.StatusPreconditionFailed,
+			respError:  "item has been modified",
+			mockError:  itemsrv.ErrVersionMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			itemHandlerMock := mocks.NewItem(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				itemHandlerMock.
+					On(tt.method, ctx, int64(1), int64(1), int64(2), int64(3)).
+					Return(tt.mockError)
+			}
+
+			itemHandler := item.New(ctx, log, itemHandlerMock)
+			handler := itemHandler.AttachTag
+			if tt.method == "DetachTag" {
+				handler = itemHandler.DetachTag
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/1/tags/"+tt.tagId, nil)
+			req = withUserAndItem(req, 1, "1")
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+			chi.RouteContext(req.Context()).URLParams.Add("tagId", tt.tagId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUserAndItem(req *http.Request, userId int64, itemId string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", itemId)
+
+	ctx := context.WithValue(req.Context(), identification.Uid("user_id"), userId)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return req.WithContext(ctx)
+}
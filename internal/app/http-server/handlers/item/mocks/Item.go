@@ -15,14 +15,246 @@ type Item struct {
 	mock.Mock
 }
 
-// AllItems provides a mock function with given fields: ctx, userId
-func (_m *Item) AllItems(ctx context.Context, userId int64) ([]models.Item, error) {
-	ret := _m.Called(ctx, userId)
+// AllItems provides a mock function with given fields: ctx, userId, filter
+func (_m *Item) AllItems(ctx context.Context, userId int64, filter models.ItemFilter) (models.ItemPage, error) {
+	ret := _m.Called(ctx, userId, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AllItems")
 	}
 
+	var r0 models.ItemPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.ItemFilter) (models.ItemPage, error)); ok {
+		return rf(ctx, userId, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.ItemFilter) models.ItemPage); ok {
+		r0 = rf(ctx, userId, filter)
+	} else {
+		r0 = ret.Get(0).(models.ItemPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.ItemFilter) error); ok {
+		r1 = rf(ctx, userId, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AttachTag provides a mock function with given fields: ctx, userId, itemId, tagId, version
+func (_m *Item) AttachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	ret := _m.Called(ctx, userId, itemId, tagId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AttachTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, tagId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Complete provides a mock function with given fields: ctx, userId, itemId, cascade, version
+func (_m *Item) Complete(ctx context.Context, userId int64, itemId int64, cascade bool, version int64) (int64, error) {
+	ret := _m.Called(ctx, userId, itemId, cascade, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, bool, int64) (int64, error)); ok {
+		return rf(ctx, userId, itemId, cascade, version)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, bool, int64) int64); ok {
+		r0 = rf(ctx, userId, itemId, cascade, version)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, bool, int64) error); ok {
+		r1 = rf(ctx, userId, itemId, cascade, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userId, input
+func (_m *Item) Create(ctx context.Context, userId int64, input models.CreateItemInput) (int64, error) {
+	ret := _m.Called(ctx, userId, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateItemInput) (int64, error)); ok {
+		return rf(ctx, userId, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateItemInput) int64); ok {
+		r0 = rf(ctx, userId, input)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.CreateItemInput) error); ok {
+		r1 = rf(ctx, userId, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userId, itemId, version
+func (_m *Item) Delete(ctx context.Context, userId int64, itemId int64, version int64) error {
+	ret := _m.Called(ctx, userId, itemId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DetachTag provides a mock function with given fields: ctx, userId, itemId, tagId, version
+func (_m *Item) DetachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	ret := _m.Called(ctx, userId, itemId, tagId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DetachTag")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, tagId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// History provides a mock function with given fields: ctx, userId, itemId
+func (_m *Item) History(ctx context.Context, userId int64, itemId int64) ([]models.ItemEvent, error) {
+	ret := _m.Called(ctx, userId, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []models.ItemEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.ItemEvent, error)); ok {
+		return rf(ctx, userId, itemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.ItemEvent); ok {
+		r0 = rf(ctx, userId, itemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.ItemEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, itemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Item provides a mock function with given fields: ctx, userId, itemId
+func (_m *Item) Item(ctx context.Context, userId int64, itemId int64) (models.Item, error) {
+	ret := _m.Called(ctx, userId, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Item")
+	}
+
+	var r0 models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.Item, error)); ok {
+		return rf(ctx, userId, itemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.Item); ok {
+		r0 = rf(ctx, userId, itemId)
+	} else {
+		r0 = ret.Get(0).(models.Item)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, itemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Reopen provides a mock function with given fields: ctx, userId, itemId, version
+func (_m *Item) Reopen(ctx context.Context, userId int64, itemId int64, version int64) error {
+	ret := _m.Called(ctx, userId, itemId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reopen")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, userId, itemId, version
+func (_m *Item) Restore(ctx context.Context, userId int64, itemId int64, version int64) error {
+	ret := _m.Called(ctx, userId, itemId, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, itemId, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Trash provides a mock function with given fields: ctx, userId
+func (_m *Item) Trash(ctx context.Context, userId int64) ([]models.Item, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Trash")
+	}
+
 	var r0 []models.Item
 	var r1 error
 	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Item, error)); ok {
@@ -45,27 +277,27 @@ func (_m *Item) AllItems(ctx context.Context, userId int64) ([]models.Item, erro
 	return r0, r1
 }
 
-// Create provides a mock function with given fields: ctx, userId, title, description
-func (_m *Item) Create(ctx context.Context, userId int64, title string, description string) (int64, error) {
-	ret := _m.Called(ctx, userId, title, description)
+// Update provides a mock function with given fields: ctx, userId, itemId, input
+func (_m *Item) Update(ctx context.Context, userId int64, itemId int64, input models.UpdateItemInput) (int64, error) {
+	ret := _m.Called(ctx, userId, itemId, input)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for Update")
 	}
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) (int64, error)); ok {
-		return rf(ctx, userId, title, description)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.UpdateItemInput) (int64, error)); ok {
+		return rf(ctx, userId, itemId, input)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) int64); ok {
-		r0 = rf(ctx, userId, title, description)
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.UpdateItemInput) int64); ok {
+		r0 = rf(ctx, userId, itemId, input)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, int64, string, string) error); ok {
-		r1 = rf(ctx, userId, title, description)
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, models.UpdateItemInput) error); ok {
+		r1 = rf(ctx, userId, itemId, input)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,55 @@
+package jwks
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+// cacheControl lets verifiers cache the keys for a while, new keys are
+// published long before tokens signed by them may show up.
+const cacheControl = "public, max-age=300"
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Keys
+type Keys interface {
+	PublicKeys() []jwt.JWK
+}
+
+type Response struct {
+	Keys []jwt.JWK `json:"keys"`
+}
+
+type JWKSHandler struct {
+	ctx  context.Context
+	log  *slog.Logger
+	keys Keys
+}
+
+func New(ctx context.Context, log *slog.Logger, keys Keys) *JWKSHandler {
+	return &JWKSHandler{
+		ctx:  ctx,
+		log:  log,
+		keys: keys,
+	}
+}
+
+func (h *JWKSHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.jwks.JWKS"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	keys := h.keys.PublicKeys()
+
+	log.Debug("public keys showed", slog.Int("count", len(keys)))
+
+	w.Header().Set("Cache-Control", cacheControl)
+
+	render.JSON(w, r, Response{Keys: keys})
+}
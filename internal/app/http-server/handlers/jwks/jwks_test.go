@@ -0,0 +1,64 @@
+package jwks_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/jwks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/jwks/mocks"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKSHandler(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []jwt.JWK
+	}{
+		{
+			name: "Keys",
+			keys: []jwt.JWK{
+				{Kty: "OKP", Kid: "new", Use: "sig", Alg: "EdDSA", Crv: "Ed25519", X: "x"},
+				{Kty: "RSA", Kid: "old", Use: "sig", Alg: "RS256", N: "n", E: "AQAB"},
+			},
+		},
+		{
+			name: "No keys",
+			keys: []jwt.JWK{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			keysMock := mocks.NewKeys(t)
+			keysMock.On("PublicKeys").Return(tt.keys)
+
+			handler := jwks.New(ctx, log, keysMock).JWKS
+
+			req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.NotEmpty(t, rr.Header().Get("Cache-Control"))
+
+			var resp jwks.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.keys, resp.Keys)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	jwt "github.com/Muaz717/todo-app/internal/lib/jwt"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Keys is an autogenerated mock type for the Keys type
+type Keys struct {
+	mock.Mock
+}
+
+// PublicKeys provides a mock function with given fields:
+func (_m *Keys) PublicKeys() []jwt.JWK {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKeys")
+	}
+
+	var r0 []jwt.JWK
+	if rf, ok := ret.Get(0).(func() []jwt.JWK); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]jwt.JWK)
+		}
+	}
+
+	return r0
+}
+
+// NewKeys creates a new instance of Keys. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeys(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Keys {
+	mock := &Keys{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,450 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	listsrv "github.com/Muaz717/todo-app/internal/app/services/list"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=List
+type List interface {
+	Create(ctx context.Context, userId int64, input models.CreateListInput) (int64, error)
+	AllLists(ctx context.Context, userId int64, withArchived bool) ([]models.List, error)
+	List(ctx context.Context, userId int64, listId int64) (models.List, error)
+	Update(
+		ctx context.Context,
+		userId int64,
+		listId int64,
+		input models.UpdateListInput,
+	) error
+	Delete(ctx context.Context, userId int64, listId int64) error
+	Items(ctx context.Context, userId int64, listId int64) ([]models.Item, error)
+}
+
+type ListHandler struct {
+	ctx  context.Context
+	log  *slog.Logger
+	list List
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	list List,
+) *ListHandler {
+	return &ListHandler{
+		ctx:  ctx,
+		log:  log,
+		list: list,
+	}
+}
+
+type Request struct {
+	Name     string `json:"name" validate:"required,max=255"`
+	Color    string `json:"color,omitempty" validate:"max=16"`
+	Position *int   `json:"position,omitempty" validate:"omitnil,min=0"`
+	// WorkspaceId is the workspace the list goes to, the personal one when omitted.
+	WorkspaceId *int64 `json:"workspace_id,omitempty"`
+}
+
+type UpdateRequest struct {
+	Name     *string `json:"name,omitempty" validate:"omitnil,min=1,max=255"`
+	Color    *string `json:"color,omitempty" validate:"omitnil,max=16"`
+	Position *int    `json:"position,omitempty" validate:"omitnil,min=0"`
+	Archived *bool   `json:"archived,omitempty"`
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+func (h *ListHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.Create"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req Request
+
+	err := render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	input := models.CreateListInput{
+		Name:        req.Name,
+		Color:       req.Color,
+		Position:    req.Position,
+		WorkspaceId: req.WorkspaceId,
+	}
+
+	listId, err := h.list.Create(h.ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, listsrv.ErrWorkspaceNotFound) {
+			log.Warn("workspace not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("workspace not found"))
+
+			return
+		}
+
+		log.Error("failed to create list", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to create list"))
+
+		return
+	}
+
+	log.Info("list created", slog.Int64("list_id", listId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("List successfully created"),
+		Id:       listId,
+	})
+}
+
+func (h *ListHandler) AllLists(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.AllLists"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	withArchived := r.URL.Query().Get("archived") == "true"
+
+	lists, err := h.list.AllLists(h.ctx, userId, withArchived)
+	if err != nil {
+		log.Error("failed to get lists", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get lists"))
+
+		return
+	}
+
+	log.Info("all lists showed")
+
+	render.JSON(w, r, lists)
+}
+
+func (h *ListHandler) List(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.List"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	listId, err := parseListId(r)
+	if err != nil {
+		log.Error("invalid list id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid list id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	list, err := h.list.List(h.ctx, userId, listId)
+	if err != nil {
+		if errors.Is(err, listsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+
+		log.Error("failed to get list", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get list"))
+
+		return
+	}
+
+	log.Info("list showed", slog.Int64("list_id", listId))
+
+	render.JSON(w, r, list)
+}
+
+func (h *ListHandler) Update(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.Update"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	listId, err := parseListId(r)
+	if err != nil {
+		log.Error("invalid list id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid list id"))
+
+		return
+	}
+
+	var req UpdateRequest
+
+	err = render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	if req.Name == nil && req.Color == nil && req.Position == nil && req.Archived == nil {
+		log.Error("nothing to update")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("nothing to update"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	input := models.UpdateListInput{
+		Name:     req.Name,
+		Color:    req.Color,
+		Position: req.Position,
+		Archived: req.Archived,
+	}
+
+	err = h.list.Update(h.ctx, userId, listId, input)
+	if err != nil {
+		if errors.Is(err, listsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+
+		log.Error("failed to update list", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to update list"))
+
+		return
+	}
+
+	log.Info("list updated", slog.Int64("list_id", listId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("List successfully updated"))
+}
+
+func (h *ListHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	listId, err := parseListId(r)
+	if err != nil {
+		log.Error("invalid list id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid list id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	err = h.list.Delete(h.ctx, userId, listId)
+	if err != nil {
+		if errors.Is(err, listsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+
+		log.Error("failed to delete list", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to delete list"))
+
+		return
+	}
+
+	log.Info("list deleted", slog.Int64("list_id", listId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("List successfully deleted"))
+}
+
+func (h *ListHandler) Items(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.list.Items"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	listId, err := parseListId(r)
+	if err != nil {
+		log.Error("invalid list id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid list id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	items, err := h.list.Items(h.ctx, userId, listId)
+	if err != nil {
+		if errors.Is(err, listsrv.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("list not found"))
+
+			return
+		}
+
+		log.Error("failed to get list items", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get list items"))
+
+		return
+	}
+
+	log.Info("list items showed", slog.Int64("list_id", listId))
+
+	render.JSON(w, r, items)
+}
+
+func parseListId(r *http.Request) (int64, error) {
+	return strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+}
@@ -0,0 +1,387 @@
+package list_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/list"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/list/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	listsrv "github.com/Muaz717/todo-app/internal/app/services/list"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	workspaceId := int64(4)
+
+	tests := []struct {
+		name       string
+		req        list.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        list.Request{Name: "work", Color: "#ff0000"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty name",
+			req:        list.Request{Color: "#ff0000"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Name is a required field",
+		},
+		{
+			name:       "Workspace not found",
+			req:        list.Request{Name: "work", WorkspaceId: &workspaceId},
+			statusCode: http.StatusNotFound,
+			respError:  "workspace not found",
+			mockError:  listsrv.ErrWorkspaceNotFound,
+		},
+		{
+			name:       "Create error",
+			req:        list.Request{Name: "work"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create list",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			listMock := mocks.NewList(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				listMock.
+					On("Create", ctx, int64(1), models.CreateListInput{
+						Name:        tt.req.Name,
+						Color:       tt.req.Color,
+						Position:    tt.req.Position,
+						WorkspaceId: tt.req.WorkspaceId,
+					}).
+					Return(int64(1), tt.mockError)
+			}
+
+			handler := list.New(ctx, log, listMock).Create
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/lists/", &input)
+			req = withUserAndList(req, 1, "")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp list.CreateResponse
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, int64(1), resp.Id)
+			}
+		})
+	}
+}
+
+func TestAllListsHandler(t *testing.T) {
+	tests := []struct {
+		name         string
+		query        string
+		withArchived bool
+		statusCode   int
+		respError    string
+		mockError    error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:         "With archived",
+			query:        "?archived=true",
+			withArchived: true,
+			statusCode:   http.StatusOK,
+		},
+		{
+			name:       "AllLists error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get lists",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			listMock := mocks.NewList(t)
+
+			listMock.
+				On("AllLists", ctx, int64(1), tt.withArchived).
+				Return([]models.List{}, tt.mockError)
+
+			handler := list.New(ctx, log, listMock).AllLists
+
+			req := httptest.NewRequest(http.MethodGet, "/api/lists/"+tt.query, nil)
+			req = withUserAndList(req, 1, "")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if rr.Code != http.StatusOK {
+				var resp resp.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+				require.Equal(t, tt.respError, resp.Error)
+			}
+		})
+	}
+}
+
+func TestUpdateHandler(t *testing.T) {
+	name := "home"
+	archived := true
+
+	tests := []struct {
+		name       string
+		listId     string
+		req        list.UpdateRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			listId:     "1",
+			req:        list.UpdateRequest{Name: &name},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Archive",
+			listId:     "1",
+			req:        list.UpdateRequest{Archived: &archived},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			listId:     "abc",
+			req:        list.UpdateRequest{Name: &name},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid list id",
+		},
+		{
+			name:       "Nothing to update",
+			listId:     "1",
+			statusCode: http.StatusBadRequest,
+			respError:  "nothing to update",
+		},
+		{
+			name:       "Not found",
+			listId:     "1",
+			req:        list.UpdateRequest{Name: &name},
+			statusCode: http.StatusNotFound,
+			respError:  "list not found",
+			mockError:  listsrv.ErrListNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			listMock := mocks.NewList(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				listMock.
+					On("Update", ctx, int64(1), int64(1), mock.AnythingOfType("models.UpdateListInput")).
+					Return(tt.mockError)
+			}
+
+			handler := list.New(ctx, log, listMock).Update
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/lists/"+tt.listId, &input)
+			req = withUserAndList(req, 1, tt.listId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		listId     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			listId:     "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not found",
+			listId:     "1",
+			statusCode: http.StatusNotFound,
+			respError:  "list not found",
+			mockError:  listsrv.ErrListNotFound,
+		},
+		{
+			name:       "Delete error",
+			listId:     "1",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to delete list",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			listMock := mocks.NewList(t)
+
+			listMock.
+				On("Delete", ctx, int64(1), int64(1)).
+				Return(tt.mockError)
+
+			handler := list.New(ctx, log, listMock).Delete
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/lists/"+tt.listId, nil)
+			req = withUserAndList(req, 1, tt.listId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestItemsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		listId     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			listId:     "1",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			listId:     "abc",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid list id",
+		},
+		{
+			name:       "Not found",
+			listId:     "1",
+			statusCode: http.StatusNotFound,
+			respError:  "list not found",
+			mockError:  listsrv.ErrListNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			listMock := mocks.NewList(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				listMock.
+					On("Items", ctx, int64(1), int64(1)).
+					Return([]models.Item{}, tt.mockError)
+			}
+
+			handler := list.New(ctx, log, listMock).Items
+
+			req := httptest.NewRequest(http.MethodGet, "/api/lists/"+tt.listId+"/items", nil)
+			req = withUserAndList(req, 1, tt.listId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if rr.Code != http.StatusOK {
+				var resp resp.Response
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+				require.Equal(t, tt.respError, resp.Error)
+			}
+		})
+	}
+}
+
+func withUserAndList(req *http.Request, userId int64, listId string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", listId)
+
+	ctx := context.WithValue(req.Context(), identification.Uid("user_id"), userId)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,182 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+// List is an autogenerated mock type for the List type
+type List struct {
+	mock.Mock
+}
+
+// AllLists provides a mock function with given fields: ctx, userId, withArchived
+func (_m *List) AllLists(ctx context.Context, userId int64, withArchived bool) ([]models.List, error) {
+	ret := _m.Called(ctx, userId, withArchived)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllLists")
+	}
+
+	var r0 []models.List
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) ([]models.List, error)); ok {
+		return rf(ctx, userId, withArchived)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) []models.List); ok {
+		r0 = rf(ctx, userId, withArchived)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool) error); ok {
+		r1 = rf(ctx, userId, withArchived)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: ctx, userId, input
+func (_m *List) Create(ctx context.Context, userId int64, input models.CreateListInput) (int64, error) {
+	ret := _m.Called(ctx, userId, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateListInput) (int64, error)); ok {
+		return rf(ctx, userId, input)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, models.CreateListInput) int64); ok {
+		r0 = rf(ctx, userId, input)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, models.CreateListInput) error); ok {
+		r1 = rf(ctx, userId, input)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: ctx, userId, listId
+func (_m *List) Delete(ctx context.Context, userId int64, listId int64) error {
+	ret := _m.Called(ctx, userId, listId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, listId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Items provides a mock function with given fields: ctx, userId, listId
+func (_m *List) Items(ctx context.Context, userId int64, listId int64) ([]models.Item, error) {
+	ret := _m.Called(ctx, userId, listId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Items")
+	}
+
+	var r0 []models.Item
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Item, error)); ok {
+		return rf(ctx, userId, listId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Item); ok {
+		r0 = rf(ctx, userId, listId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Item)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, listId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// List provides a mock function with given fields: ctx, userId, listId
+func (_m *List) List(ctx context.Context, userId int64, listId int64) (models.List, error) {
+	ret := _m.Called(ctx, userId, listId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 models.List
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.List, error)); ok {
+		return rf(ctx, userId, listId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.List); ok {
+		r0 = rf(ctx, userId, listId)
+	} else {
+		r0 = ret.Get(0).(models.List)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, listId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: ctx, userId, listId, input
+func (_m *List) Update(ctx context.Context, userId int64, listId int64, input models.UpdateListInput) error {
+	ret := _m.Called(ctx, userId, listId, input)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, models.UpdateListInput) error); ok {
+		r0 = rf(ctx, userId, listId, input)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewList creates a new instance of List. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewList(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *List {
+	mock := &List{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
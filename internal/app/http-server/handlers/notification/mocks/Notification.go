@@ -0,0 +1,95 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Notification is an autogenerated mock type for the Notification type
+type Notification struct {
+	mock.Mock
+}
+
+// Notifications provides a mock function with given fields: ctx, userId, unread
+func (_m *Notification) Notifications(ctx context.Context, userId int64, unread bool) ([]models.Notification, error) {
+	ret := _m.Called(ctx, userId, unread)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Notifications")
+	}
+
+	var r0 []models.Notification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) ([]models.Notification, error)); ok {
+		return rf(ctx, userId, unread)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, bool) []models.Notification); ok {
+		r0 = rf(ctx, userId, unread)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Notification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, bool) error); ok {
+		r1 = rf(ctx, userId, unread)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Read provides a mock function with given fields: ctx, userId, notificationId
+func (_m *Notification) Read(ctx context.Context, userId int64, notificationId int64) error {
+	ret := _m.Called(ctx, userId, notificationId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Read")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, notificationId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadAll provides a mock function with given fields: ctx, userId
+func (_m *Notification) ReadAll(ctx context.Context, userId int64) error {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReadAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNotification creates a new instance of Notification. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNotification(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Notification {
+	mock := &Notification{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	notificationsrv "github.com/Muaz717/todo-app/internal/app/services/notification"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Notification
+type Notification interface {
+	Notifications(ctx context.Context, userId int64, unread bool) ([]models.Notification, error)
+	Read(ctx context.Context, userId int64, notificationId int64) error
+	ReadAll(ctx context.Context, userId int64) error
+}
+
+type NotificationHandler struct {
+	ctx          context.Context
+	log          *slog.Logger
+	notification Notification
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	notification Notification,
+) *NotificationHandler {
+	return &NotificationHandler{
+		ctx:          ctx,
+		log:          log,
+		notification: notification,
+	}
+}
+
+// Notifications lists notifications of the user, ?unread=true leaves out read ones.
+func (h *NotificationHandler) Notifications(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.notification.Notifications"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	unread := false
+	if v := r.URL.Query().Get("unread"); v != "" {
+		var err error
+
+		unread, err = strconv.ParseBool(v)
+		if err != nil {
+			log.Error("invalid unread", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid unread"))
+
+			return
+		}
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	notifications, err := h.notification.Notifications(h.ctx, userId, unread)
+	if err != nil {
+		log.Error("failed to get notifications", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get notifications"))
+
+		return
+	}
+
+	render.JSON(w, r, notifications)
+}
+
+// Read marks the notification in the path read.
+func (h *NotificationHandler) Read(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.notification.Read"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	notificationId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := h.notification.Read(h.ctx, userId, notificationId); err != nil {
+		if errors.Is(err, notificationsrv.ErrNotificationNotFound) {
+			log.Warn("notification not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("notification not found"))
+
+			return
+		}
+
+		log.Error("failed to read notification", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to read notification"))
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Notification read"))
+}
+
+// ReadAll marks every notification of the user read.
+func (h *NotificationHandler) ReadAll(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.notification.ReadAll"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := h.notification.ReadAll(h.ctx, userId); err != nil {
+		log.Error("failed to read notifications", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to read notifications"))
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Notifications read"))
+}
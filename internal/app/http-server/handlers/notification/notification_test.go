@@ -0,0 +1,172 @@
+package notification_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/notification"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/notification/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	notificationsrv "github.com/Muaz717/todo-app/internal/app/services/notification"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		unread     bool
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "All",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Unread",
+			query:      "?unread=true",
+			unread:     true,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid unread",
+			query:      "?unread=maybe",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid unread",
+		},
+		{
+			name:       "Notifications error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get notifications",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			notificationMock := mocks.NewNotification(t)
+
+			commentId := int64(3)
+			notifications := []models.Notification{{
+				Id:        7,
+				Kind:      models.NotificationMention,
+				ActorId:   2,
+				ItemId:    5,
+				CommentId: &commentId,
+			}}
+
+			if tt.respError == "" || tt.mockError != nil {
+				notificationMock.
+					On("Notifications", ctx, int64(1), tt.unread).
+					Return(notifications, tt.mockError)
+			}
+
+			handler := notification.New(ctx, log, notificationMock).Notifications
+
+			req := httptest.NewRequest(http.MethodGet, "/api/notifications"+tt.query, nil)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.Notification
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, notifications, got)
+		})
+	}
+}
+
+func TestReadHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			id:         "7",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "seven",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid id",
+		},
+		{
+			name:       "Not found",
+			id:         "7",
+			statusCode: http.StatusNotFound,
+			respError:  "notification not found",
+			mockError:  notificationsrv.ErrNotificationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			notificationMock := mocks.NewNotification(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				notificationMock.
+					On("Read", ctx, int64(1), int64(7)).
+					Return(tt.mockError)
+			}
+
+			handler := notification.New(ctx, log, notificationMock).Read
+
+			req := httptest.NewRequest(http.MethodPost, "/api/notifications/"+tt.id+"/read", nil)
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.id)
+			req = withUser(req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx)), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
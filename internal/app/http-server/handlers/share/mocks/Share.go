@@ -0,0 +1,229 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Share is an autogenerated mock type for the Share type
+type Share struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: ctx, userId, shareId
+func (_m *Share) Accept(ctx context.Context, userId int64, shareId int64) error {
+	ret := _m.Called(ctx, userId, shareId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accept")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, shareId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Decline provides a mock function with given fields: ctx, userId, shareId
+func (_m *Share) Decline(ctx context.Context, userId int64, shareId int64) error {
+	ret := _m.Called(ctx, userId, shareId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decline")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, shareId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, userId, shareId
+func (_m *Share) Delete(ctx context.Context, userId int64, shareId int64) error {
+	ret := _m.Called(ctx, userId, shareId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, shareId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ItemShares provides a mock function with given fields: ctx, ownerId, itemId
+func (_m *Share) ItemShares(ctx context.Context, ownerId int64, itemId int64) ([]models.Share, error) {
+	ret := _m.Called(ctx, ownerId, itemId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ItemShares")
+	}
+
+	var r0 []models.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Share, error)); ok {
+		return rf(ctx, ownerId, itemId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Share); ok {
+		r0 = rf(ctx, ownerId, itemId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, ownerId, itemId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListShares provides a mock function with given fields: ctx, ownerId, listId
+func (_m *Share) ListShares(ctx context.Context, ownerId int64, listId int64) ([]models.Share, error) {
+	ret := _m.Called(ctx, ownerId, listId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListShares")
+	}
+
+	var r0 []models.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Share, error)); ok {
+		return rf(ctx, ownerId, listId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Share); ok {
+		r0 = rf(ctx, ownerId, listId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, ownerId, listId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ShareItem provides a mock function with given fields: ctx, ownerId, itemId, email, role
+func (_m *Share) ShareItem(ctx context.Context, ownerId int64, itemId int64, email string, role string) (int64, error) {
+	ret := _m.Called(ctx, ownerId, itemId, email, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShareItem")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) (int64, error)); ok {
+		return rf(ctx, ownerId, itemId, email, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) int64); ok {
+		r0 = rf(ctx, ownerId, itemId, email, role)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string, string) error); ok {
+		r1 = rf(ctx, ownerId, itemId, email, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ShareList provides a mock function with given fields: ctx, ownerId, listId, email, role
+func (_m *Share) ShareList(ctx context.Context, ownerId int64, listId int64, email string, role string) (int64, error) {
+	ret := _m.Called(ctx, ownerId, listId, email, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ShareList")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) (int64, error)); ok {
+		return rf(ctx, ownerId, listId, email, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) int64); ok {
+		r0 = rf(ctx, ownerId, listId, email, role)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string, string) error); ok {
+		r1 = rf(ctx, ownerId, listId, email, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Shares provides a mock function with given fields: ctx, userId, status
+func (_m *Share) Shares(ctx context.Context, userId int64, status string) ([]models.Share, error) {
+	ret := _m.Called(ctx, userId, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Shares")
+	}
+
+	var r0 []models.Share
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]models.Share, error)); ok {
+		return rf(ctx, userId, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []models.Share); ok {
+		r0 = rf(ctx, userId, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Share)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userId, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewShare creates a new instance of Share. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewShare(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Share {
+	mock := &Share{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,327 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	sharesrv "github.com/Muaz717/todo-app/internal/app/services/share"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Share
+type Share interface {
+	ShareItem(ctx context.Context, ownerId int64, itemId int64, email string, role string) (int64, error)
+	ShareList(ctx context.Context, ownerId int64, listId int64, email string, role string) (int64, error)
+	ItemShares(ctx context.Context, ownerId int64, itemId int64) ([]models.Share, error)
+	ListShares(ctx context.Context, ownerId int64, listId int64) ([]models.Share, error)
+	Shares(ctx context.Context, userId int64, status string) ([]models.Share, error)
+	Accept(ctx context.Context, userId int64, shareId int64) error
+	Decline(ctx context.Context, userId int64, shareId int64) error
+	Delete(ctx context.Context, userId int64, shareId int64) error
+}
+
+type ShareHandler struct {
+	ctx   context.Context
+	log   *slog.Logger
+	share Share
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	share Share,
+) *ShareHandler {
+	return &ShareHandler{
+		ctx:   ctx,
+		log:   log,
+		share: share,
+	}
+}
+
+type Request struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+// ShareItem invites a user to the item in the path.
+func (h *ShareHandler) ShareItem(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.ShareItem"
+
+	h.invite(w, r, op, h.share.ShareItem)
+}
+
+// ShareList invites a user to the list in the path.
+func (h *ShareHandler) ShareList(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.ShareList"
+
+	h.invite(w, r, op, h.share.ShareList)
+}
+
+func (h *ShareHandler) invite(
+	w http.ResponseWriter,
+	r *http.Request,
+	op string,
+	share func(ctx context.Context, ownerId int64, id int64, email string, role string) (int64, error),
+) {
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req Request
+
+	err := render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return
+	}
+
+	userId, id, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	shareId, err := share(h.ctx, userId, id, req.Email, req.Role)
+	if err != nil {
+		writeError(w, r, log, err, "failed to share")
+
+		return
+	}
+
+	log.Info("invitation sent", slog.Int64("share_id", shareId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Invitation sent"),
+		Id:       shareId,
+	})
+}
+
+// ItemShares lists everyone the item in the path was shared with.
+func (h *ShareHandler) ItemShares(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.ItemShares"
+
+	h.members(w, r, op, h.share.ItemShares)
+}
+
+// ListShares lists everyone the list in the path was shared with.
+func (h *ShareHandler) ListShares(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.ListShares"
+
+	h.members(w, r, op, h.share.ListShares)
+}
+
+func (h *ShareHandler) members(
+	w http.ResponseWriter,
+	r *http.Request,
+	op string,
+	shares func(ctx context.Context, ownerId int64, id int64) ([]models.Share, error),
+) {
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, id, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	members, err := shares(h.ctx, userId, id)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get shares")
+
+		return
+	}
+
+	render.JSON(w, r, members)
+}
+
+// Shares lists shares granted to the user, ?status= narrows them down,
+// e.g. to pending invitations.
+func (h *ShareHandler) Shares(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.Shares"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	shares, err := h.share.Shares(h.ctx, userId, r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, log, err, "failed to get shares")
+
+		return
+	}
+
+	render.JSON(w, r, shares)
+}
+
+func (h *ShareHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.Accept"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, shareId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.share.Accept(h.ctx, userId, shareId); err != nil {
+		writeError(w, r, log, err, "failed to accept invitation")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Invitation accepted"))
+}
+
+func (h *ShareHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.Decline"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, shareId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.share.Decline(h.ctx, userId, shareId); err != nil {
+		writeError(w, r, log, err, "failed to decline invitation")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Invitation declined"))
+}
+
+// Delete revokes a share granted by the user or leaves a share granted to them.
+func (h *ShareHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.share.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, shareId, ok := h.target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.share.Delete(h.ctx, userId, shareId); err != nil {
+		writeError(w, r, log, err, "failed to delete share")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Share deleted"))
+}
+
+// target returns id of the user making the request and the id in the path.
+func (h *ShareHandler) target(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid id"))
+
+		return 0, 0, false
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return 0, 0, false
+	}
+
+	return userId, id, true
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, msg string) {
+	for _, known := range []struct {
+		err    error
+		status int
+	}{
+		{sharesrv.ErrItemNotFound, http.StatusNotFound},
+		{sharesrv.ErrListNotFound, http.StatusNotFound},
+		{sharesrv.ErrUserNotFound, http.StatusNotFound},
+		{sharesrv.ErrShareNotFound, http.StatusNotFound},
+		{sharesrv.ErrInvalidRole, http.StatusBadRequest},
+		{sharesrv.ErrInvalidStatus, http.StatusBadRequest},
+		{sharesrv.ErrSelfShare, http.StatusBadRequest},
+	} {
+		if errors.Is(err, known.err) {
+			log.Warn(known.err.Error(), sl.Err(err))
+
+			w.WriteHeader(known.status)
+			render.JSON(w, r, resp.Error(known.err.Error()))
+
+			return
+		}
+	}
+
+	log.Error(msg, sl.Err(err))
+
+	w.WriteHeader(http.StatusInternalServerError)
+	render.JSON(w, r, resp.Error(msg))
+}
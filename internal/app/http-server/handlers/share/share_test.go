@@ -0,0 +1,354 @@
+package share_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/share"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/share/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	sharesrv "github.com/Muaz717/todo-app/internal/app/services/share"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareItemHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        share.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        share.Request{Email: "friend@mail.ru", Role: models.ShareEditor},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty email",
+			req:        share.Request{Role: models.ShareViewer},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is a required field",
+		},
+		{
+			name:       "Invalid email",
+			req:        share.Request{Email: "friend", Role: models.ShareViewer},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is not a valid Email",
+		},
+		{
+			name:       "Empty role",
+			req:        share.Request{Email: "friend@mail.ru"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Role is a required field",
+		},
+		{
+			name:       "Invalid role",
+			req:        share.Request{Email: "friend@mail.ru", Role: "owner"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid role",
+			mockError:  sharesrv.ErrInvalidRole,
+		},
+		{
+			name:       "Self",
+			req:        share.Request{Email: "test@mail.ru", Role: models.ShareViewer},
+			statusCode: http.StatusBadRequest,
+			respError:  "can not share with yourself",
+			mockError:  sharesrv.ErrSelfShare,
+		},
+		{
+			name:       "User not found",
+			req:        share.Request{Email: "nobody@mail.ru", Role: models.ShareViewer},
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  sharesrv.ErrUserNotFound,
+		},
+		{
+			name:       "Item not found",
+			req:        share.Request{Email: "friend@mail.ru", Role: models.ShareViewer},
+			statusCode: http.StatusNotFound,
+			respError:  "item not found",
+			mockError:  sharesrv.ErrItemNotFound,
+		},
+		{
+			name:       "ShareItem error",
+			req:        share.Request{Email: "friend@mail.ru", Role: models.ShareViewer},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to share",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			shareMock := mocks.NewShare(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				shareMock.
+					On("ShareItem", ctx, int64(1), int64(5), tt.req.Email, tt.req.Role).
+					Return(int64(3), tt.mockError)
+			}
+
+			handler := share.New(ctx, log, shareMock).ShareItem
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/items/5/shares", &body)
+			req = withUser(withId(req, "5"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp share.CreateResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, int64(3), resp.Id)
+			}
+		})
+	}
+}
+
+func TestListSharesHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			id:         "5",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "five",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid id",
+		},
+		{
+			name:       "List not found",
+			id:         "5",
+			statusCode: http.StatusNotFound,
+			respError:  "list not found",
+			mockError:  sharesrv.ErrListNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			shareMock := mocks.NewShare(t)
+
+			listId := int64(5)
+			shares := []models.Share{{Id: 3, ListId: &listId, UserId: 2, Role: models.ShareViewer, Status: models.SharePending}}
+
+			if tt.respError == "" || tt.mockError != nil {
+				shareMock.
+					On("ListShares", ctx, int64(1), listId).
+					Return(shares, tt.mockError)
+			}
+
+			handler := share.New(ctx, log, shareMock).ListShares
+
+			req := httptest.NewRequest(http.MethodGet, "/api/lists/"+tt.id+"/shares", nil)
+			req = withUser(withId(req, tt.id), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.Share
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, shares, got)
+		})
+	}
+}
+
+func TestSharesHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "All",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Pending",
+			status:     models.SharePending,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid status",
+			status:     "revoked",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid status",
+			mockError:  sharesrv.ErrInvalidStatus,
+		},
+		{
+			name:       "Shares error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to get shares",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			shareMock := mocks.NewShare(t)
+
+			shareMock.
+				On("Shares", ctx, int64(1), tt.status).
+				Return([]models.Share{}, tt.mockError)
+
+			handler := share.New(ctx, log, shareMock).Shares
+
+			req := httptest.NewRequest(http.MethodGet, "/api/shares?status="+tt.status, nil)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+			}
+		})
+	}
+}
+
+func TestRespondHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Accept",
+			method:     "Accept",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Decline",
+			method:     "Decline",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Delete",
+			method:     "Delete",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Accept not pending",
+			method:     "Accept",
+			statusCode: http.StatusNotFound,
+			respError:  "share not found",
+			mockError:  sharesrv.ErrShareNotFound,
+		},
+		{
+			name:       "Delete error",
+			method:     "Delete",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to delete share",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			shareMock := mocks.NewShare(t)
+
+			shareMock.
+				On(tt.method, ctx, int64(1), int64(3)).
+				Return(tt.mockError)
+
+			h := share.New(ctx, log, shareMock)
+
+			handler := map[string]http.HandlerFunc{
+				"Accept":  h.Accept,
+				"Decline": h.Decline,
+				"Delete":  h.Delete,
+			}[tt.method]
+
+			req := httptest.NewRequest(http.MethodPost, "/api/shares/3", nil)
+			req = withUser(withId(req, "3"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
+
+func withId(r *http.Request, id string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", id)
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
@@ -0,0 +1,292 @@
+package tag
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	tagsrv "github.com/Muaz717/todo-app/internal/app/services/tag"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Tag
+type Tag interface {
+	Create(ctx context.Context, userId int64, name string) (int64, error)
+	AllTags(ctx context.Context, userId int64) ([]models.Tag, error)
+	Rename(ctx context.Context, userId int64, tagId int64, name string) error
+	Delete(ctx context.Context, userId int64, tagId int64) error
+}
+
+type TagHandler struct {
+	ctx context.Context
+	log *slog.Logger
+	tag Tag
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	tag Tag,
+) *TagHandler {
+	return &TagHandler{
+		ctx: ctx,
+		log: log,
+		tag: tag,
+	}
+}
+
+type Request struct {
+	Name string `json:"name" validate:"required,max=64"`
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+func (h *TagHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.tag.Create"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	req, ok := decodeRequest(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	tagId, err := h.tag.Create(h.ctx, userId, req.Name)
+	if err != nil {
+		if errors.Is(err, tagsrv.ErrTagExists) {
+			log.Warn("tag already exists", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("tag already exists"))
+
+			return
+		}
+
+		log.Error("failed to create tag", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to create tag"))
+
+		return
+	}
+
+	log.Info("tag created", slog.Int64("tag_id", tagId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Tag successfully created"),
+		Id:       tagId,
+	})
+}
+
+func (h *TagHandler) AllTags(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.tag.AllTags"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	tags, err := h.tag.AllTags(h.ctx, userId)
+	if err != nil {
+		log.Error("failed to get tags", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get tags"))
+
+		return
+	}
+
+	log.Info("all tags showed")
+
+	render.JSON(w, r, tags)
+}
+
+func (h *TagHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.tag.Rename"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	tagId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid tag id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid tag id"))
+
+		return
+	}
+
+	req, ok := decodeRequest(w, r, log)
+	if !ok {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	err = h.tag.Rename(h.ctx, userId, tagId, req.Name)
+	if err != nil {
+		if errors.Is(err, tagsrv.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("tag not found"))
+
+			return
+		}
+		if errors.Is(err, tagsrv.ErrTagExists) {
+			log.Warn("tag already exists", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("tag already exists"))
+
+			return
+		}
+
+		log.Error("failed to rename tag", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to rename tag"))
+
+		return
+	}
+
+	log.Info("tag renamed", slog.Int64("tag_id", tagId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Tag successfully renamed"))
+}
+
+func (h *TagHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.tag.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	tagId, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		log.Error("invalid tag id", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid tag id"))
+
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	err = h.tag.Delete(h.ctx, userId, tagId)
+	if err != nil {
+		if errors.Is(err, tagsrv.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			w.WriteHeader(http.StatusNotFound)
+			render.JSON(w, r, resp.Error("tag not found"))
+
+			return
+		}
+
+		log.Error("failed to delete tag", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to delete tag"))
+
+		return
+	}
+
+	log.Info("tag deleted", slog.Int64("tag_id", tagId), slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Tag successfully deleted"))
+}
+
+// decodeRequest decodes and validates the request body, writing
+// the error response and returning false when it is not acceptable.
+func decodeRequest(w http.ResponseWriter, r *http.Request, log *slog.Logger) (Request, bool) {
+	var req Request
+
+	err := render.DecodeJSON(r.Body, &req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return Request{}, false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return Request{}, false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return Request{}, false
+	}
+
+	return req, true
+}
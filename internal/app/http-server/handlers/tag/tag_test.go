@@ -0,0 +1,229 @@
+package tag_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/tag"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/tag/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	tagsrv "github.com/Muaz717/todo-app/internal/app/services/tag"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		tagName    string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			tagName:    "home",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty name",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Name is a required field",
+		},
+		{
+			name:       "Duplicate",
+			tagName:    "Home",
+			statusCode: http.StatusConflict,
+			respError:  "tag already exists",
+			mockError:  tagsrv.ErrTagExists,
+		},
+		{
+			name:       "Create error",
+			tagName:    "home",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create tag",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			tagMock := mocks.NewTag(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				tagMock.
+					On("Create", ctx, int64(1), tt.tagName).
+					Return(int64(1), tt.mockError)
+			}
+
+			handler := tag.New(ctx, log, tagMock).Create
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tag.Request{Name: tt.tagName}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/tags/", &input)
+			req = withUserAndTag(req, 1, "")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestRenameHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		tagId      string
+		tagName    string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			tagId:      "1",
+			tagName:    "work",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			tagId:      "abc",
+			tagName:    "work",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid tag id",
+		},
+		{
+			name:       "Not found",
+			tagId:      "1",
+			tagName:    "work",
+			statusCode: http.StatusNotFound,
+			respError:  "tag not found",
+			mockError:  tagsrv.ErrTagNotFound,
+		},
+		{
+			name:       "Duplicate",
+			tagId:      "1",
+			tagName:    "WORK",
+			statusCode: http.StatusConflict,
+			respError:  "tag already exists",
+			mockError:  tagsrv.ErrTagExists,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			tagMock := mocks.NewTag(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				tagMock.
+					On("Rename", ctx, int64(1), int64(1), tt.tagName).
+					Return(tt.mockError)
+			}
+
+			handler := tag.New(ctx, log, tagMock).Rename
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tag.Request{Name: tt.tagName}))
+
+			req := httptest.NewRequest(http.MethodPatch, "/api/tags/"+tt.tagId, &input)
+			req = withUserAndTag(req, 1, tt.tagId)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Not found",
+			statusCode: http.StatusNotFound,
+			respError:  "tag not found",
+			mockError:  tagsrv.ErrTagNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			tagMock := mocks.NewTag(t)
+
+			tagMock.
+				On("Delete", ctx, int64(1), int64(1)).
+				Return(tt.mockError)
+
+			handler := tag.New(ctx, log, tagMock).Delete
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/tags/1", nil)
+			req = withUserAndTag(req, 1, "1")
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUserAndTag(req *http.Request, userId int64, tagId string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", tagId)
+
+	ctx := context.WithValue(req.Context(), identification.Uid("user_id"), userId)
+	ctx = context.WithValue(ctx, chi.RouteCtxKey, rctx)
+
+	return req.WithContext(ctx)
+}
@@ -0,0 +1,105 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TwoFactor is an autogenerated mock type for the TwoFactor type
+type TwoFactor struct {
+	mock.Mock
+}
+
+// ConfirmTwoFactor provides a mock function with given fields: ctx, userId, code
+func (_m *TwoFactor) ConfirmTwoFactor(ctx context.Context, userId int64, code string) ([]string, error) {
+	ret := _m.Called(ctx, userId, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTwoFactor")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]string, error)); ok {
+		return rf(ctx, userId, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []string); ok {
+		r0 = rf(ctx, userId, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userId, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DisableTwoFactor provides a mock function with given fields: ctx, userId, password, code
+func (_m *TwoFactor) DisableTwoFactor(ctx context.Context, userId int64, password string, code string) error {
+	ret := _m.Called(ctx, userId, password, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableTwoFactor")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string, string) error); ok {
+		r0 = rf(ctx, userId, password, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnrollTwoFactor provides a mock function with given fields: ctx, userId, password
+func (_m *TwoFactor) EnrollTwoFactor(ctx context.Context, userId int64, password string) (models.Enrollment, error) {
+	ret := _m.Called(ctx, userId, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollTwoFactor")
+	}
+
+	var r0 models.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (models.Enrollment, error)); ok {
+		return rf(ctx, userId, password)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) models.Enrollment); ok {
+		r0 = rf(ctx, userId, password)
+	} else {
+		r0 = ret.Get(0).(models.Enrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userId, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTwoFactor creates a new instance of TwoFactor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTwoFactor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TwoFactor {
+	mock := &TwoFactor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,318 @@
+package twofactor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=TwoFactor
+type TwoFactor interface {
+	EnrollTwoFactor(ctx context.Context, userId int64, password string) (models.Enrollment, error)
+	ConfirmTwoFactor(ctx context.Context, userId int64, code string) ([]string, error)
+	DisableTwoFactor(ctx context.Context, userId int64, password string, code string) error
+}
+
+type TwoFactorHandler struct {
+	ctx       context.Context
+	log       *slog.Logger
+	twoFactor TwoFactor
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	twoFactor TwoFactor,
+) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		ctx:       ctx,
+		log:       log,
+		twoFactor: twoFactor,
+	}
+}
+
+type Request struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// EnrollRequest carries the password of the user, a session alone
+// is not enough to change how they sign in.
+type EnrollRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+// DisableRequest carries the password of the user and a TOTP or a recovery code.
+type DisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// EnrollResponse carries the secret both as an otpauth:// URI and
+// as a QR code PNG, encoded in base64, for authenticator apps to scan.
+type EnrollResponse struct {
+	resp.Response
+	models.Enrollment
+}
+
+// ConfirmResponse carries the recovery codes, they are shown only once.
+type ConfirmResponse struct {
+	resp.Response
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+func (h *TwoFactorHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.twofactor.Enroll"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req EnrollRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	enrollment, err := h.twoFactor.EnrollTwoFactor(h.ctx, userId, req.Password)
+	if err != nil {
+		if reauthFailed(w, r, log, err) {
+			return
+		}
+		if errors.Is(err, authService.ErrTwoFactorEnabled) {
+			log.Warn("two-factor authentication is already enabled", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("two-factor authentication is already enabled"))
+
+			return
+		}
+
+		log.Error("failed to enroll two-factor authentication", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to enroll two-factor authentication"))
+
+		return
+	}
+
+	log.Info("two-factor authentication enrolled", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, EnrollResponse{
+		Response:   resp.OK("Confirm the secret with a code"),
+		Enrollment: enrollment,
+	})
+}
+
+func (h *TwoFactorHandler) Confirm(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.twofactor.Confirm"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req Request
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	codes, err := h.twoFactor.ConfirmTwoFactor(h.ctx, userId, req.Code)
+	if err != nil {
+		if errors.Is(err, authService.ErrTwoFactorEnabled) {
+			log.Warn("two-factor authentication is already enabled", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("two-factor authentication is already enabled"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrTwoFactorNotEnrolled) {
+			log.Warn("two-factor authentication is not enrolled", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("two-factor authentication is not enrolled"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidCode) {
+			log.Warn("invalid two-factor code", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid two-factor code"))
+
+			return
+		}
+
+		log.Error("failed to confirm two-factor authentication", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to confirm two-factor authentication"))
+
+		return
+	}
+
+	log.Info("two-factor authentication enabled", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, ConfirmResponse{
+		Response:      resp.OK("Two-factor authentication enabled"),
+		RecoveryCodes: codes,
+	})
+}
+
+func (h *TwoFactorHandler) Disable(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.twofactor.Disable"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req DisableRequest
+	if !decodeRequest(w, r, log, &req) {
+		return
+	}
+
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return
+	}
+
+	if err := h.twoFactor.DisableTwoFactor(h.ctx, userId, req.Password, req.Code); err != nil {
+		if reauthFailed(w, r, log, err) {
+			return
+		}
+		if errors.Is(err, authService.ErrTwoFactorDisabled) {
+			log.Warn("two-factor authentication is not enabled", sl.Err(err))
+
+			w.WriteHeader(http.StatusConflict)
+			render.JSON(w, r, resp.Error("two-factor authentication is not enabled"))
+
+			return
+		}
+		if errors.Is(err, authService.ErrInvalidCode) {
+			log.Warn("invalid two-factor code", sl.Err(err))
+
+			w.WriteHeader(http.StatusBadRequest)
+			render.JSON(w, r, resp.Error("invalid two-factor code"))
+
+			return
+		}
+
+		log.Error("failed to disable two-factor authentication", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to disable two-factor authentication"))
+
+		return
+	}
+
+	log.Info("two-factor authentication disabled", slog.Int64("user_id", userId))
+
+	render.JSON(w, r, resp.OK("Two-factor authentication disabled"))
+}
+
+// reauthFailed writes the response for a wrong or locked out password.
+func reauthFailed(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) bool {
+	var lockedErr *authService.LockedError
+	if errors.As(err, &lockedErr) {
+		log.Warn("too many attempts", sl.Err(err))
+
+		retryAfter := int(math.Ceil(time.Until(lockedErr.Until).Seconds()))
+
+		w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+		w.WriteHeader(http.StatusTooManyRequests)
+		render.JSON(w, r, resp.Error("too many attempts, try again later"))
+
+		return true
+	}
+	if errors.Is(err, authService.ErrInvalidCredentials) {
+		log.Warn("invalid password", sl.Err(err))
+
+		w.WriteHeader(http.StatusForbidden)
+		render.JSON(w, r, resp.Error("invalid password"))
+
+		return true
+	}
+	if errors.Is(err, authService.ErrUserNotFound) {
+		log.Warn("user not found", sl.Err(err))
+
+		w.WriteHeader(http.StatusNotFound)
+		render.JSON(w, r, resp.Error("user not found"))
+
+		return true
+	}
+
+	return false
+}
+
+func decodeRequest(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return false
+	}
+
+	return true
+}
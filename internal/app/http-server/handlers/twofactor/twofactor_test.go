@@ -0,0 +1,300 @@
+package twofactor_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/twofactor"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/twofactor/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnrollHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        twofactor.EnrollRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        twofactor.EnrollRequest{Password: "test_password"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty password",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Password is a required field",
+		},
+		{
+			name:       "Wrong password",
+			req:        twofactor.EnrollRequest{Password: "wrong_password"},
+			statusCode: http.StatusForbidden,
+			respError:  "invalid password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name:       "Locked out",
+			req:        twofactor.EnrollRequest{Password: "wrong_password"},
+			statusCode: http.StatusTooManyRequests,
+			respError:  "too many attempts, try again later",
+			mockError:  &authService.LockedError{Until: time.Now().Add(time.Minute)},
+		},
+		{
+			name:       "Already enabled",
+			req:        twofactor.EnrollRequest{Password: "test_password"},
+			statusCode: http.StatusConflict,
+			respError:  "two-factor authentication is already enabled",
+			mockError:  authService.ErrTwoFactorEnabled,
+		},
+		{
+			name:       "Enroll error",
+			req:        twofactor.EnrollRequest{Password: "test_password"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to enroll two-factor authentication",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			twoFactorMock := mocks.NewTwoFactor(t)
+
+			enrollment := models.Enrollment{
+				Secret: "JBSWY3DPEHPK3PXP",
+				URI:    "otpauth://totp/todo-app:test@mail.ru?secret=JBSWY3DPEHPK3PXP",
+				QRCode: []byte{0x89, 'P', 'N', 'G'},
+			}
+
+			if tt.respError == "" || tt.mockError != nil {
+				twoFactorMock.
+					On("EnrollTwoFactor", ctx, int64(1), tt.req.Password).
+					Return(enrollment, tt.mockError)
+			}
+
+			handler := twofactor.New(ctx, log, twoFactorMock).Enroll
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/enroll", &input)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp twofactor.EnrollResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, enrollment, resp.Enrollment)
+			}
+		})
+	}
+}
+
+func TestConfirmHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        twofactor.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        twofactor.Request{Code: "123456"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty code",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Code is a required field",
+		},
+		{
+			name:       "Invalid code",
+			req:        twofactor.Request{Code: "123456"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid two-factor code",
+			mockError:  authService.ErrInvalidCode,
+		},
+		{
+			name:       "Not enrolled",
+			req:        twofactor.Request{Code: "123456"},
+			statusCode: http.StatusConflict,
+			respError:  "two-factor authentication is not enrolled",
+			mockError:  authService.ErrTwoFactorNotEnrolled,
+		},
+		{
+			name:       "Already enabled",
+			req:        twofactor.Request{Code: "123456"},
+			statusCode: http.StatusConflict,
+			respError:  "two-factor authentication is already enabled",
+			mockError:  authService.ErrTwoFactorEnabled,
+		},
+		{
+			name:       "Confirm error",
+			req:        twofactor.Request{Code: "123456"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to confirm two-factor authentication",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			twoFactorMock := mocks.NewTwoFactor(t)
+
+			codes := []string{"abcde-fghij", "klmno-pqrst"}
+
+			if tt.respError == "" || tt.mockError != nil {
+				twoFactorMock.
+					On("ConfirmTwoFactor", ctx, int64(1), tt.req.Code).
+					Return(codes, tt.mockError)
+			}
+
+			handler := twofactor.New(ctx, log, twoFactorMock).Confirm
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/confirm", &input)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp twofactor.ConfirmResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, codes, resp.RecoveryCodes)
+			}
+		})
+	}
+}
+
+func TestDisableHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        twofactor.DisableRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        twofactor.DisableRequest{Password: "test_password", Code: "abcde-fghij"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty code",
+			req:        twofactor.DisableRequest{Password: "test_password"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Code is a required field",
+		},
+		{
+			name:       "Empty password",
+			req:        twofactor.DisableRequest{Code: "123456"},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Password is a required field",
+		},
+		{
+			name:       "Wrong password",
+			req:        twofactor.DisableRequest{Password: "wrong_password", Code: "123456"},
+			statusCode: http.StatusForbidden,
+			respError:  "invalid password",
+			mockError:  authService.ErrInvalidCredentials,
+		},
+		{
+			name:       "Invalid code",
+			req:        twofactor.DisableRequest{Password: "test_password", Code: "123456"},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid two-factor code",
+			mockError:  authService.ErrInvalidCode,
+		},
+		{
+			name:       "Not enabled",
+			req:        twofactor.DisableRequest{Password: "test_password", Code: "123456"},
+			statusCode: http.StatusConflict,
+			respError:  "two-factor authentication is not enabled",
+			mockError:  authService.ErrTwoFactorDisabled,
+		},
+		{
+			name:       "Disable error",
+			req:        twofactor.DisableRequest{Password: "test_password", Code: "123456"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to disable two-factor authentication",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			twoFactorMock := mocks.NewTwoFactor(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				twoFactorMock.
+					On("DisableTwoFactor", ctx, int64(1), tt.req.Password, tt.req.Code).
+					Return(tt.mockError)
+			}
+
+			handler := twofactor.New(ctx, log, twoFactorMock).Disable
+
+			var input bytes.Buffer
+			require.NoError(t, json.NewEncoder(&input).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/auth/2fa/disable", &input)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
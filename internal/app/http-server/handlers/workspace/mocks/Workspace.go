@@ -0,0 +1,359 @@
+// Code generated by mockery v2.46.2. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	models "github.com/Muaz717/todo-app/internal/domain/models"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Workspace is an autogenerated mock type for the Workspace type
+type Workspace struct {
+	mock.Mock
+}
+
+// Accept provides a mock function with given fields: ctx, userId, invitationId
+func (_m *Workspace) Accept(ctx context.Context, userId int64, invitationId int64) error {
+	ret := _m.Called(ctx, userId, invitationId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Accept")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, invitationId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Create provides a mock function with given fields: ctx, userId, name
+func (_m *Workspace) Create(ctx context.Context, userId int64, name string) (int64, error) {
+	ret := _m.Called(ctx, userId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) (int64, error)); ok {
+		return rf(ctx, userId, name)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) int64); ok {
+		r0 = rf(ctx, userId, name)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userId, name)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Decline provides a mock function with given fields: ctx, userId, invitationId
+func (_m *Workspace) Decline(ctx context.Context, userId int64, invitationId int64) error {
+	ret := _m.Called(ctx, userId, invitationId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decline")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, invitationId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: ctx, userId, workspaceId
+func (_m *Workspace) Delete(ctx context.Context, userId int64, workspaceId int64) error {
+	ret := _m.Called(ctx, userId, workspaceId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) error); ok {
+		r0 = rf(ctx, userId, workspaceId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Invitations provides a mock function with given fields: ctx, userId, status
+func (_m *Workspace) Invitations(ctx context.Context, userId int64, status string) ([]models.Invitation, error) {
+	ret := _m.Called(ctx, userId, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invitations")
+	}
+
+	var r0 []models.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) ([]models.Invitation, error)); ok {
+		return rf(ctx, userId, status)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, string) []models.Invitation); ok {
+		r0 = rf(ctx, userId, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, string) error); ok {
+		r1 = rf(ctx, userId, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Invite provides a mock function with given fields: ctx, userId, workspaceId, email, role
+func (_m *Workspace) Invite(ctx context.Context, userId int64, workspaceId int64, email string, role string) (int64, error) {
+	ret := _m.Called(ctx, userId, workspaceId, email, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invite")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) (int64, error)); ok {
+		return rf(ctx, userId, workspaceId, email, role)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string, string) int64); ok {
+		r0 = rf(ctx, userId, workspaceId, email, role)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64, string, string) error); ok {
+		r1 = rf(ctx, userId, workspaceId, email, role)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Members provides a mock function with given fields: ctx, userId, workspaceId
+func (_m *Workspace) Members(ctx context.Context, userId int64, workspaceId int64) ([]models.Member, error) {
+	ret := _m.Called(ctx, userId, workspaceId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Members")
+	}
+
+	var r0 []models.Member
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Member, error)); ok {
+		return rf(ctx, userId, workspaceId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Member); ok {
+		r0 = rf(ctx, userId, workspaceId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Member)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, workspaceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveMember provides a mock function with given fields: ctx, userId, workspaceId, memberId
+func (_m *Workspace) RemoveMember(ctx context.Context, userId int64, workspaceId int64, memberId int64) error {
+	ret := _m.Called(ctx, userId, workspaceId, memberId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveMember")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, workspaceId, memberId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Rename provides a mock function with given fields: ctx, userId, workspaceId, name
+func (_m *Workspace) Rename(ctx context.Context, userId int64, workspaceId int64, name string) error {
+	ret := _m.Called(ctx, userId, workspaceId, name)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Rename")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, string) error); ok {
+		r0 = rf(ctx, userId, workspaceId, name)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevokeInvitation provides a mock function with given fields: ctx, userId, workspaceId, invitationId
+func (_m *Workspace) RevokeInvitation(ctx context.Context, userId int64, workspaceId int64, invitationId int64) error {
+	ret := _m.Called(ctx, userId, workspaceId, invitationId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeInvitation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64) error); ok {
+		r0 = rf(ctx, userId, workspaceId, invitationId)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRole provides a mock function with given fields: ctx, userId, workspaceId, memberId, role
+func (_m *Workspace) SetRole(ctx context.Context, userId int64, workspaceId int64, memberId int64, role string) error {
+	ret := _m.Called(ctx, userId, workspaceId, memberId, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64, int64, string) error); ok {
+		r0 = rf(ctx, userId, workspaceId, memberId, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Workspace provides a mock function with given fields: ctx, userId, workspaceId
+func (_m *Workspace) Workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error) {
+	ret := _m.Called(ctx, userId, workspaceId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Workspace")
+	}
+
+	var r0 models.Workspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (models.Workspace, error)); ok {
+		return rf(ctx, userId, workspaceId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) models.Workspace); ok {
+		r0 = rf(ctx, userId, workspaceId)
+	} else {
+		r0 = ret.Get(0).(models.Workspace)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, workspaceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// WorkspaceInvitations provides a mock function with given fields: ctx, userId, workspaceId
+func (_m *Workspace) WorkspaceInvitations(ctx context.Context, userId int64, workspaceId int64) ([]models.Invitation, error) {
+	ret := _m.Called(ctx, userId, workspaceId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WorkspaceInvitations")
+	}
+
+	var r0 []models.Invitation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) ([]models.Invitation, error)); ok {
+		return rf(ctx, userId, workspaceId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) []models.Invitation); ok {
+		r0 = rf(ctx, userId, workspaceId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Invitation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, userId, workspaceId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Workspaces provides a mock function with given fields: ctx, userId
+func (_m *Workspace) Workspaces(ctx context.Context, userId int64) ([]models.Workspace, error) {
+	ret := _m.Called(ctx, userId)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Workspaces")
+	}
+
+	var r0 []models.Workspace
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) ([]models.Workspace, error)); ok {
+		return rf(ctx, userId)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) []models.Workspace); ok {
+		r0 = rf(ctx, userId)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.Workspace)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, userId)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWorkspace creates a new instance of Workspace. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWorkspace(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Workspace {
+	mock := &Workspace{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,554 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	workspacesrv "github.com/Muaz717/todo-app/internal/app/services/workspace"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+)
+
+//go:generate go run github.com/vektra/mockery/v2@v2.46.2 --name=Workspace
+type Workspace interface {
+	Create(ctx context.Context, userId int64, name string) (int64, error)
+	Workspaces(ctx context.Context, userId int64) ([]models.Workspace, error)
+	Workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error)
+	Rename(ctx context.Context, userId int64, workspaceId int64, name string) error
+	Delete(ctx context.Context, userId int64, workspaceId int64) error
+	Members(ctx context.Context, userId int64, workspaceId int64) ([]models.Member, error)
+	SetRole(ctx context.Context, userId int64, workspaceId int64, memberId int64, role string) error
+	RemoveMember(ctx context.Context, userId int64, workspaceId int64, memberId int64) error
+	Invite(ctx context.Context, userId int64, workspaceId int64, email string, role string) (int64, error)
+	Invitations(ctx context.Context, userId int64, status string) ([]models.Invitation, error)
+	WorkspaceInvitations(ctx context.Context, userId int64, workspaceId int64) ([]models.Invitation, error)
+	RevokeInvitation(ctx context.Context, userId int64, workspaceId int64, invitationId int64) error
+	Accept(ctx context.Context, userId int64, invitationId int64) error
+	Decline(ctx context.Context, userId int64, invitationId int64) error
+}
+
+type WorkspaceHandler struct {
+	ctx       context.Context
+	log       *slog.Logger
+	workspace Workspace
+}
+
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	workspace Workspace,
+) *WorkspaceHandler {
+	return &WorkspaceHandler{
+		ctx:       ctx,
+		log:       log,
+		workspace: workspace,
+	}
+}
+
+type Request struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type RoleRequest struct {
+	Role string `json:"role" validate:"required"`
+}
+
+type InviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required"`
+}
+
+type CreateResponse struct {
+	resp.Response
+	Id int64 `json:"id"`
+}
+
+func (h *WorkspaceHandler) Create(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Create"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	var req Request
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	userId, ok := user(w, r, log)
+	if !ok {
+		return
+	}
+
+	workspaceId, err := h.workspace.Create(h.ctx, userId, req.Name)
+	if err != nil {
+		writeError(w, r, log, err, "failed to create workspace")
+
+		return
+	}
+
+	log.Info("workspace created", slog.Int64("workspace_id", workspaceId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Workspace successfully created"),
+		Id:       workspaceId,
+	})
+}
+
+// Workspaces lists workspaces the user is a member of.
+func (h *WorkspaceHandler) Workspaces(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Workspaces"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, ok := user(w, r, log)
+	if !ok {
+		return
+	}
+
+	workspaces, err := h.workspace.Workspaces(h.ctx, userId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get workspaces")
+
+		return
+	}
+
+	render.JSON(w, r, workspaces)
+}
+
+func (h *WorkspaceHandler) Workspace(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Workspace"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	workspace, err := h.workspace.Workspace(h.ctx, userId, workspaceId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get workspace")
+
+		return
+	}
+
+	render.JSON(w, r, workspace)
+}
+
+func (h *WorkspaceHandler) Rename(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Rename"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req Request
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	if err := h.workspace.Rename(h.ctx, userId, workspaceId, req.Name); err != nil {
+		writeError(w, r, log, err, "failed to rename workspace")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Workspace successfully renamed"))
+}
+
+func (h *WorkspaceHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Delete"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.workspace.Delete(h.ctx, userId, workspaceId); err != nil {
+		writeError(w, r, log, err, "failed to delete workspace")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Workspace successfully deleted"))
+}
+
+func (h *WorkspaceHandler) Members(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Members"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	members, err := h.workspace.Members(h.ctx, userId, workspaceId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get members")
+
+		return
+	}
+
+	render.JSON(w, r, members)
+}
+
+// SetRole changes the role of the member in the path.
+func (h *WorkspaceHandler) SetRole(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.SetRole"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	memberId, ok := pathId(w, r, log, "userId")
+	if !ok {
+		return
+	}
+
+	var req RoleRequest
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	if err := h.workspace.SetRole(h.ctx, userId, workspaceId, memberId, req.Role); err != nil {
+		writeError(w, r, log, err, "failed to set role")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Role successfully changed"))
+}
+
+// RemoveMember removes the member in the path, members remove themselves to leave.
+func (h *WorkspaceHandler) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.RemoveMember"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	memberId, ok := pathId(w, r, log, "userId")
+	if !ok {
+		return
+	}
+
+	if err := h.workspace.RemoveMember(h.ctx, userId, workspaceId, memberId); err != nil {
+		writeError(w, r, log, err, "failed to remove member")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Member successfully removed"))
+}
+
+func (h *WorkspaceHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Invite"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	var req InviteRequest
+
+	if !decode(w, r, log, &req) {
+		return
+	}
+
+	invitationId, err := h.workspace.Invite(h.ctx, userId, workspaceId, req.Email, req.Role)
+	if err != nil {
+		writeError(w, r, log, err, "failed to invite")
+
+		return
+	}
+
+	log.Info("invitation sent", slog.Int64("invitation_id", invitationId))
+
+	render.JSON(w, r, CreateResponse{
+		Response: resp.OK("Invitation sent"),
+		Id:       invitationId,
+	})
+}
+
+// WorkspaceInvitations lists invitations to the workspace in the path nobody accepted yet.
+func (h *WorkspaceHandler) WorkspaceInvitations(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.WorkspaceInvitations"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.workspace.WorkspaceInvitations(h.ctx, userId, workspaceId)
+	if err != nil {
+		writeError(w, r, log, err, "failed to get invitations")
+
+		return
+	}
+
+	render.JSON(w, r, invitations)
+}
+
+func (h *WorkspaceHandler) RevokeInvitation(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.RevokeInvitation"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, workspaceId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	invitationId, ok := pathId(w, r, log, "invitationId")
+	if !ok {
+		return
+	}
+
+	if err := h.workspace.RevokeInvitation(h.ctx, userId, workspaceId, invitationId); err != nil {
+		writeError(w, r, log, err, "failed to revoke invitation")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Invitation revoked"))
+}
+
+// Invitations lists invitations of the user, ?status= narrows them down,
+// e.g. to pending ones.
+func (h *WorkspaceHandler) Invitations(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Invitations"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, ok := user(w, r, log)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.workspace.Invitations(h.ctx, userId, r.URL.Query().Get("status"))
+	if err != nil {
+		writeError(w, r, log, err, "failed to get invitations")
+
+		return
+	}
+
+	render.JSON(w, r, invitations)
+}
+
+func (h *WorkspaceHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Accept"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, invitationId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.workspace.Accept(h.ctx, userId, invitationId); err != nil {
+		writeError(w, r, log, err, "failed to accept invitation")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Invitation accepted"))
+}
+
+func (h *WorkspaceHandler) Decline(w http.ResponseWriter, r *http.Request) {
+	const op = "handlers.workspace.Decline"
+
+	log := h.log.With(
+		slog.String("op", op),
+		slog.String("request_id", middleware.GetReqID(r.Context())),
+	)
+
+	userId, invitationId, ok := target(w, r, log)
+	if !ok {
+		return
+	}
+
+	if err := h.workspace.Decline(h.ctx, userId, invitationId); err != nil {
+		writeError(w, r, log, err, "failed to decline invitation")
+
+		return
+	}
+
+	render.JSON(w, r, resp.OK("Invitation declined"))
+}
+
+// decode reads and validates the request body into req.
+func decode(w http.ResponseWriter, r *http.Request, log *slog.Logger, req any) bool {
+	err := render.DecodeJSON(r.Body, req)
+	if errors.Is(err, io.EOF) {
+		log.Error("request body is empty")
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("empty request"))
+
+		return false
+	}
+	if err != nil {
+		log.Error("failed to decode request body", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("failed to decode request"))
+
+		return false
+	}
+
+	if err := validator.New().Struct(req); err != nil {
+		validateErr := err.(validator.ValidationErrors)
+
+		log.Error("invalid request", sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.ValidationError(validateErr))
+
+		return false
+	}
+
+	return true
+}
+
+func user(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, bool) {
+	userId, err := identification.GetUserId(r)
+	if err != nil {
+		log.Error("failed to get user id", sl.Err(err))
+
+		w.WriteHeader(http.StatusInternalServerError)
+		render.JSON(w, r, resp.Error("failed to get user id"))
+
+		return 0, false
+	}
+
+	return userId, true
+}
+
+// target returns id of the user making the request and the id in the path.
+func target(w http.ResponseWriter, r *http.Request, log *slog.Logger) (int64, int64, bool) {
+	id, ok := pathId(w, r, log, "id")
+	if !ok {
+		return 0, 0, false
+	}
+
+	userId, ok := user(w, r, log)
+	if !ok {
+		return 0, 0, false
+	}
+
+	return userId, id, true
+}
+
+func pathId(w http.ResponseWriter, r *http.Request, log *slog.Logger, name string) (int64, bool) {
+	id, err := strconv.ParseInt(chi.URLParam(r, name), 10, 64)
+	if err != nil {
+		log.Error("invalid id", slog.String("param", name), sl.Err(err))
+
+		w.WriteHeader(http.StatusBadRequest)
+		render.JSON(w, r, resp.Error("invalid id"))
+
+		return 0, false
+	}
+
+	return id, true
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error, msg string) {
+	for _, known := range []struct {
+		err    error
+		status int
+	}{
+		{workspacesrv.ErrWorkspaceNotFound, http.StatusNotFound},
+		{workspacesrv.ErrMemberNotFound, http.StatusNotFound},
+		{workspacesrv.ErrInvitationNotFound, http.StatusNotFound},
+		{workspacesrv.ErrUserNotFound, http.StatusNotFound},
+		{workspacesrv.ErrForbidden, http.StatusForbidden},
+		{workspacesrv.ErrMemberExists, http.StatusConflict},
+		{workspacesrv.ErrPersonalWorkspace, http.StatusConflict},
+		{workspacesrv.ErrOwnerLeave, http.StatusConflict},
+		{workspacesrv.ErrWorkspaceNotEmpty, http.StatusConflict},
+		{workspacesrv.ErrInvalidRole, http.StatusBadRequest},
+		{workspacesrv.ErrInvalidStatus, http.StatusBadRequest},
+	} {
+		if errors.Is(err, known.err) {
+			log.Warn(known.err.Error(), sl.Err(err))
+
+			w.WriteHeader(known.status)
+			render.JSON(w, r, resp.Error(known.err.Error()))
+
+			return
+		}
+	}
+
+	log.Error(msg, sl.Err(err))
+
+	w.WriteHeader(http.StatusInternalServerError)
+	render.JSON(w, r, resp.Error(msg))
+}
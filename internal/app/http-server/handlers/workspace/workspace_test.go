@@ -0,0 +1,519 @@
+package workspace_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/workspace"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/workspace/mocks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	workspacesrv "github.com/Muaz717/todo-app/internal/app/services/workspace"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        workspace.Request
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        workspace.Request{Name: "Team"},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty name",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Name is a required field",
+		},
+		{
+			name:       "Create error",
+			req:        workspace.Request{Name: "Team"},
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to create workspace",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				workspaceMock.
+					On("Create", ctx, int64(1), tt.req.Name).
+					Return(int64(4), tt.mockError)
+			}
+
+			handler := workspace.New(ctx, log, workspaceMock).Create
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/workspaces", &body)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp workspace.CreateResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, int64(4), resp.Id)
+			}
+		})
+	}
+}
+
+func TestDeleteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			id:         "4",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid id",
+			id:         "four",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid id",
+		},
+		{
+			name:       "Not found",
+			id:         "4",
+			statusCode: http.StatusNotFound,
+			respError:  "workspace not found",
+			mockError:  workspacesrv.ErrWorkspaceNotFound,
+		},
+		{
+			name:       "Not owner",
+			id:         "4",
+			statusCode: http.StatusForbidden,
+			respError:  "not allowed in this workspace",
+			mockError:  workspacesrv.ErrForbidden,
+		},
+		{
+			name:       "Personal",
+			id:         "4",
+			statusCode: http.StatusConflict,
+			respError:  "personal workspace can not be shared or deleted",
+			mockError:  workspacesrv.ErrPersonalWorkspace,
+		},
+		{
+			name:       "Not empty",
+			id:         "4",
+			statusCode: http.StatusConflict,
+			respError:  "workspace still has items, delete them first",
+			mockError:  workspacesrv.ErrWorkspaceNotEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				workspaceMock.
+					On("Delete", ctx, int64(1), int64(4)).
+					Return(tt.mockError)
+			}
+
+			handler := workspace.New(ctx, log, workspaceMock).Delete
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/workspaces/"+tt.id, nil)
+			req = withUser(withParams(req, "id", tt.id), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestSetRoleHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        workspace.RoleRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        workspace.RoleRequest{Role: models.WorkspaceGuest},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Empty role",
+			statusCode: http.StatusBadRequest,
+			respError:  "field Role is a required field",
+		},
+		{
+			name:       "Invalid role",
+			req:        workspace.RoleRequest{Role: models.WorkspaceOwner},
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid role",
+			mockError:  workspacesrv.ErrInvalidRole,
+		},
+		{
+			name:       "Member not found",
+			req:        workspace.RoleRequest{Role: models.WorkspaceMember},
+			statusCode: http.StatusNotFound,
+			respError:  "member not found",
+			mockError:  workspacesrv.ErrMemberNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				workspaceMock.
+					On("SetRole", ctx, int64(1), int64(4), int64(2), tt.req.Role).
+					Return(tt.mockError)
+			}
+
+			handler := workspace.New(ctx, log, workspaceMock).SetRole
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/workspaces/4/members/2/role", &body)
+			req = withUser(withParams(req, "id", "4", "userId", "2"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestRemoveMemberHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Owner leaves",
+			statusCode: http.StatusConflict,
+			respError:  "owner can not leave the workspace",
+			mockError:  workspacesrv.ErrOwnerLeave,
+		},
+		{
+			name:       "RemoveMember error",
+			statusCode: http.StatusInternalServerError,
+			respError:  "failed to remove member",
+			mockError:  errors.New("unexpected error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			workspaceMock.
+				On("RemoveMember", ctx, int64(1), int64(4), int64(1)).
+				Return(tt.mockError)
+
+			handler := workspace.New(ctx, log, workspaceMock).RemoveMember
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/workspaces/4/members/1", nil)
+			req = withUser(withParams(req, "id", "4", "userId", "1"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func TestInviteHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        workspace.InviteRequest
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Success",
+			req:        workspace.InviteRequest{Email: "friend@mail.ru", Role: models.WorkspaceMember},
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid email",
+			req:        workspace.InviteRequest{Email: "friend", Role: models.WorkspaceMember},
+			statusCode: http.StatusBadRequest,
+			respError:  "field Email is not a valid Email",
+		},
+		{
+			name:       "Already a member",
+			req:        workspace.InviteRequest{Email: "friend@mail.ru", Role: models.WorkspaceMember},
+			statusCode: http.StatusConflict,
+			respError:  "user is already a member",
+			mockError:  workspacesrv.ErrMemberExists,
+		},
+		{
+			name:       "Admin by admin",
+			req:        workspace.InviteRequest{Email: "friend@mail.ru", Role: models.WorkspaceAdmin},
+			statusCode: http.StatusForbidden,
+			respError:  "not allowed in this workspace",
+			mockError:  workspacesrv.ErrForbidden,
+		},
+		{
+			name:       "User not found",
+			req:        workspace.InviteRequest{Email: "nobody@mail.ru", Role: models.WorkspaceGuest},
+			statusCode: http.StatusNotFound,
+			respError:  "user not found",
+			mockError:  workspacesrv.ErrUserNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			if tt.respError == "" || tt.mockError != nil {
+				workspaceMock.
+					On("Invite", ctx, int64(1), int64(4), tt.req.Email, tt.req.Role).
+					Return(int64(7), tt.mockError)
+			}
+
+			handler := workspace.New(ctx, log, workspaceMock).Invite
+
+			var body bytes.Buffer
+			require.NoError(t, json.NewEncoder(&body).Encode(tt.req))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/workspaces/4/invitations", &body)
+			req = withUser(withParams(req, "id", "4"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp workspace.CreateResponse
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+
+			if tt.respError == "" {
+				require.Equal(t, int64(7), resp.Id)
+			}
+		})
+	}
+}
+
+func TestInvitationsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Pending",
+			status:     models.InvitationPending,
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Invalid status",
+			status:     "revoked",
+			statusCode: http.StatusBadRequest,
+			respError:  "invalid status",
+			mockError:  workspacesrv.ErrInvalidStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			invitations := []models.Invitation{{Id: 7, WorkspaceId: 4, UserId: 1, Role: models.WorkspaceMember, Status: tt.status}}
+
+			workspaceMock.
+				On("Invitations", ctx, int64(1), tt.status).
+				Return(invitations, tt.mockError)
+
+			handler := workspace.New(ctx, log, workspaceMock).Invitations
+
+			req := httptest.NewRequest(http.MethodGet, "/api/workspaces/invitations?status="+tt.status, nil)
+			req = withUser(req, 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			require.Equal(t, tt.statusCode, rr.Code)
+
+			if tt.respError != "" {
+				var resp resp.Response
+
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+				require.Equal(t, tt.respError, resp.Error)
+
+				return
+			}
+
+			var got []models.Invitation
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &got))
+			require.Equal(t, invitations, got)
+		})
+	}
+}
+
+func TestRespondHandlers(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		statusCode int
+		respError  string
+		mockError  error
+	}{
+		{
+			name:       "Accept",
+			method:     "Accept",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Decline",
+			method:     "Decline",
+			statusCode: http.StatusOK,
+		},
+		{
+			name:       "Accept not pending",
+			method:     "Accept",
+			statusCode: http.StatusNotFound,
+			respError:  "invitation not found",
+			mockError:  workspacesrv.ErrInvitationNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			log := slogdiscard.NewDiscardLogger()
+
+			workspaceMock := mocks.NewWorkspace(t)
+
+			workspaceMock.
+				On(tt.method, ctx, int64(1), int64(7)).
+				Return(tt.mockError)
+
+			h := workspace.New(ctx, log, workspaceMock)
+
+			handler := map[string]http.HandlerFunc{
+				"Accept":  h.Accept,
+				"Decline": h.Decline,
+			}[tt.method]
+
+			req := httptest.NewRequest(http.MethodPost, "/api/workspaces/invitations/7", nil)
+			req = withUser(withParams(req, "id", "7"), 1)
+
+			rr := httptest.NewRecorder()
+			handler(rr, req)
+
+			var resp resp.Response
+
+			require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &resp))
+
+			require.Equal(t, tt.statusCode, rr.Code)
+			require.Equal(t, tt.respError, resp.Error)
+		})
+	}
+}
+
+func withUser(r *http.Request, userId int64) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), identification.Uid("user_id"), userId))
+}
+
+// withParams sets path parameters given as name and value pairs.
+func withParams(r *http.Request, params ...string) *http.Request {
+	rctx := chi.NewRouteContext()
+	for i := 0; i+1 < len(params); i += 2 {
+		rctx.URLParams.Add(params[i], params[i+1])
+	}
+
+	return r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+}
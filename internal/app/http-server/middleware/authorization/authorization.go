@@ -0,0 +1,79 @@
+package authorization
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/go-chi/render"
+)
+
+// RoleProvider returns the role of an enabled user,
+// disabled users are storage.ErrUserNotFound.
+type RoleProvider interface {
+	UserRole(ctx context.Context, userId int64) (string, error)
+}
+
+// RequireRole lets through users holding one of roles. It must run after
+// identification, the role is read on every request, so taking it away
+// or disabling the user takes effect at once.
+func RequireRole(log *slog.Logger, provider RoleProvider, roles ...string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		const op = "middleware.Authorization.RequireRole"
+
+		log := log.With(
+			slog.String("component", "authorization"),
+			slog.String("op", op),
+		)
+
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			userId, err := identification.GetUserId(r)
+			if err != nil {
+				log.Error("failed to get user id", sl.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("failed to get user id"))
+
+				return
+			}
+
+			role, err := provider.UserRole(r.Context(), userId)
+			if err != nil {
+				if errors.Is(err, storage.ErrUserNotFound) {
+					log.Warn("user not found or disabled", slog.Int64("user_id", userId))
+
+					w.WriteHeader(http.StatusForbidden)
+					render.JSON(w, r, resp.Error("access denied"))
+
+					return
+				}
+
+				log.Error("failed to get user role", sl.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("failed to check access"))
+
+				return
+			}
+
+			if !slices.Contains(roles, role) {
+				log.Warn("role is not allowed", slog.Int64("user_id", userId), slog.String("role", role))
+
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.Error("access denied"))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
@@ -5,13 +5,16 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
-	"os"
+	"slices"
 	"strings"
 
+	apitokensrv "github.com/Muaz717/todo-app/internal/app/services/apitoken"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
 	resp "github.com/Muaz717/todo-app/internal/lib/api/response"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
 	"github.com/go-chi/render"
-	"github.com/golang-jwt/jwt/v5"
 )
 
 const (
@@ -20,7 +23,33 @@ const (
 
 type Uid string
 
-func New(log *slog.Logger) func(next http.Handler) http.Handler {
+// scopesKey keeps scopes of the personal access token the request was made with,
+// requests authenticated with a session JWT carry none and may do anything.
+const scopesKey = Uid("scopes")
+
+// verifiedKey keeps whether the email of a session user is verified.
+const verifiedKey = Uid("email_verified")
+
+// TokenAuthenticator resolves personal access tokens.
+type TokenAuthenticator interface {
+	Authenticate(ctx context.Context, secret string) (models.APIToken, error)
+}
+
+// UserChecker reports whether a session user may still make requests,
+// missing users are storage.ErrUserNotFound.
+type UserChecker interface {
+	UserActive(ctx context.Context, userId int64) (bool, error)
+}
+
+// New authenticates requests by a Bearer JWT or a personal access token.
+// The user of a JWT is checked on every request, so disabling the user
+// takes effect at once rather than when the token expires.
+func New(
+	log *slog.Logger,
+	verifier jwt.Verifier,
+	tokens TokenAuthenticator,
+	users UserChecker,
+) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		const op = "middleware.Identification.New"
 
@@ -42,9 +71,30 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 				return
 			}
 
-			tokenParsed, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-				return []byte(os.Getenv("MY_SECRET")), nil
-			})
+			ctx := r.Context()
+
+			if strings.HasPrefix(token, apitokensrv.Prefix) {
+				apiToken, err := tokens.Authenticate(ctx, token)
+				if err != nil {
+					log.Error("failed to authenticate api token", sl.Err(err))
+
+					w.WriteHeader(http.StatusUnauthorized)
+					render.JSON(w, r, resp.Error("invalid api token"))
+
+					return
+				}
+
+				log.Info("api token successfully authenticated", slog.Int64("token_id", apiToken.Id))
+
+				ctx = context.WithValue(ctx, Uid("user_id"), apiToken.UserId)
+				ctx = context.WithValue(ctx, scopesKey, apiToken.Scopes)
+
+				next.ServeHTTP(w, r.WithContext(ctx))
+
+				return
+			}
+
+			claims, err := jwt.Parse(token, verifier)
 			if err != nil {
 				log.Error("failed to parse token", sl.Err(err))
 
@@ -54,16 +104,30 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 				return
 			}
 
-			claims := tokenParsed.Claims.(jwt.MapClaims)
+			active, err := users.UserActive(ctx, claims.UserId)
+			if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+				log.Error("failed to check user", sl.Err(err))
+
+				w.WriteHeader(http.StatusInternalServerError)
+				render.JSON(w, r, resp.Error("failed to check user"))
+
+				return
+			}
+			if !active {
+				log.Warn("user is disabled or not found", slog.Int64("user_id", claims.UserId))
+
+				w.WriteHeader(http.StatusUnauthorized)
+				render.JSON(w, r, resp.Error("user is disabled"))
 
-			userId := int64(claims["uid"].(float64))
+				return
+			}
 
 			log.Info("token successfully parsed")
 
 			uidStr := Uid("user_id")
 
-			ctx := r.Context()
-			withValue := context.WithValue(ctx, uidStr, userId)
+			withValue := context.WithValue(ctx, uidStr, claims.UserId)
+			withValue = context.WithValue(withValue, verifiedKey, claims.EmailVerified)
 			r = r.WithContext(withValue)
 
 			next.ServeHTTP(w, r)
@@ -73,6 +137,66 @@ func New(log *slog.Logger) func(next http.Handler) http.Handler {
 	}
 }
 
+// RequireScope lets through requests with a safe method holding the read scope
+// and other requests holding the write scope.
+func RequireScope(read string, write string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		fn := func(w http.ResponseWriter, r *http.Request) {
+			scope := write
+			if r.Method == http.MethodGet || r.Method == http.MethodHead {
+				scope = read
+			}
+
+			scopes, limited := r.Context().Value(scopesKey).([]string)
+			if limited && !slices.Contains(scopes, scope) {
+				w.WriteHeader(http.StatusForbidden)
+				render.JSON(w, r, resp.Error("token lacks scope "+scope))
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		}
+
+		return http.HandlerFunc(fn)
+	}
+}
+
+// RequireSession rejects requests made with a personal access token,
+// e.g. tokens must not be able to mint other tokens.
+func RequireSession(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		if _, limited := r.Context().Value(scopesKey).([]string); limited {
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error("not allowed with an api token"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// RequireVerifiedToWrite lets users whose email is not verified yet
+// make requests with a safe method only.
+func RequireVerifiedToWrite(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		verified, ok := r.Context().Value(verifiedKey).(bool)
+		if ok && !verified && r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusForbidden)
+			render.JSON(w, r, resp.Error("email is not verified"))
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 func GetUserId(r *http.Request) (int64, error) {
 	userId := r.Context().Value(Uid("user_id"))
 	if userId == "" {
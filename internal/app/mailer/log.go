@@ -0,0 +1,26 @@
+package mailer
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Log writes emails to the log instead of sending them, it is meant for local development
+// only: bodies carry links with live tokens, they are logged so the links can be followed.
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (l *Log) Send(_ context.Context, msg Message) error {
+	l.log.Info("email",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+		slog.String("body", msg.Body),
+	)
+
+	return nil
+}
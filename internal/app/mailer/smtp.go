@@ -0,0 +1,135 @@
+package mailer
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/config"
+)
+
+// defaultSendTimeout bounds sending when the config sets no timeout.
+const defaultSendTimeout = 10 * time.Second
+
+// SMTP sends emails through an SMTP relay, STARTTLS is used when the server offers it.
+type SMTP struct {
+	cfg config.SMTP
+}
+
+func NewSMTP(cfg config.SMTP) *SMTP {
+	return &SMTP{cfg: cfg}
+}
+
+// Send delivers the message, the whole conversation with the server is bound
+// by the timeout of the config and by the deadline and cancellation of ctx.
+func (s *SMTP) Send(ctx context.Context, msg Message) error {
+	const op = "mailer.SMTP.Send"
+
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("%s: invalid recipient %q", op, msg.To)
+	}
+
+	if err := s.send(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// send is smtp.SendMail over a connection that gives up at the deadline.
+func (s *SMTP) send(ctx context.Context, msg Message) error {
+	timeout := s.cfg.SendTimeout
+	if timeout <= 0 {
+		timeout = defaultSendTimeout
+	}
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	dialer := net.Dialer{Deadline: deadline}
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return err
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+
+		return err
+	}
+
+	// cancelling ctx interrupts the conversation in flight
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		conn.Close()
+
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: s.cfg.Host}); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("server does not support AUTH")
+		}
+
+		if err := c.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := c.Mail(s.cfg.From); err != nil {
+		return err
+	}
+
+	if err := c.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(s.format(msg)); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return c.Quit()
+}
+
+func (s *SMTP) format(msg Message) []byte {
+	var b strings.Builder
+
+	b.WriteString("From: " + s.cfg.From + "\r\n")
+	b.WriteString("To: " + msg.To + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return []byte(b.String())
+}
@@ -0,0 +1,145 @@
+package mailer_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// serveSMTP accepts a single connection and answers it as a minimal SMTP
+// server, the received envelope and data are sent to the returned channel.
+func serveSMTP(t *testing.T) (string, <-chan []string) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan []string, 1)
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		var lines []string
+
+		reply("220 localhost ESMTP")
+
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+
+			switch cmd := strings.ToUpper(strings.SplitN(line, " ", 2)[0]); cmd {
+			case "EHLO", "HELO":
+				reply("250 localhost")
+			case "MAIL", "RCPT":
+				lines = append(lines, line)
+				reply("250 OK")
+			case "DATA":
+				reply("354 end data with <CR><LF>.<CR><LF>")
+
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					data = strings.TrimRight(data, "\r\n")
+					if data == "." {
+						break
+					}
+					lines = append(lines, data)
+				}
+
+				reply("250 OK")
+			case "QUIT":
+				reply("221 bye")
+				received <- lines
+
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+
+	return ln.Addr().String(), received
+}
+
+func TestSMTPSend(t *testing.T) {
+	addr, received := serveSMTP(t)
+
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	m := mailer.NewSMTP(config.SMTP{Host: host, Port: port, From: "todo@example.com"})
+
+	err = m.Send(context.Background(), mailer.Message{
+		To:      "user@example.com",
+		Subject: "Reset your password",
+		Body:    "first line\nsecond line",
+	})
+	require.NoError(t, err)
+
+	lines := <-received
+
+	require.Contains(t, lines, "MAIL FROM:<todo@example.com>")
+	require.Contains(t, lines, "RCPT TO:<user@example.com>")
+	require.Contains(t, lines, "To: user@example.com")
+	require.Contains(t, lines, "Subject: Reset your password")
+	require.Contains(t, lines, "first line")
+	require.Contains(t, lines, "second line")
+}
+
+func TestSMTPSendInvalidRecipient(t *testing.T) {
+	m := mailer.NewSMTP(config.SMTP{Host: "127.0.0.1", Port: "1", From: "todo@example.com"})
+
+	err := m.Send(context.Background(), mailer.Message{
+		To:      "user@example.com\r\nBcc: other@example.com",
+		Subject: "Reset your password",
+	})
+	require.Error(t, err)
+}
+
+func TestSMTPSendStalledServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	// the server accepts the connection and never greets
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			t.Cleanup(func() { conn.Close() })
+		}
+	}()
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	require.NoError(t, err)
+
+	m := mailer.NewSMTP(config.SMTP{Host: host, Port: port, From: "todo@example.com"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+
+	err = m.Send(ctx, mailer.Message{To: "user@example.com", Subject: "Reset your password"})
+	require.Error(t, err)
+	require.Less(t, time.Since(start), 5*time.Second)
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+// Email mails reminders to the owners of the items.
+type Email struct {
+	mailer mailer.Mailer
+}
+
+func NewEmail(mailer mailer.Mailer) *Email {
+	return &Email{mailer: mailer}
+}
+
+func (e *Email) Notify(ctx context.Context, reminder models.Reminder) error {
+	const op = "notifier.Email.Notify"
+
+	var body strings.Builder
+
+	fmt.Fprintf(&body, "Reminder: %s\n", reminder.Title)
+	if reminder.DueAt != nil {
+		fmt.Fprintf(&body, "Due at: %s\n", reminder.DueAt.Format(time.RFC1123))
+	}
+
+	msg := mailer.Message{
+		To:      reminder.Email,
+		Subject: "Reminder: " + reminder.Title,
+		Body:    body.String(),
+	}
+
+	if err := e.mailer.Send(ctx, msg); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package notifier
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+// Log only writes reminders to the log, it is meant for local development.
+type Log struct {
+	log *slog.Logger
+}
+
+func NewLog(log *slog.Logger) *Log {
+	return &Log{log: log}
+}
+
+func (l *Log) Notify(_ context.Context, reminder models.Reminder) error {
+	l.log.Info("reminder",
+		slog.Int64("item_id", reminder.ItemId),
+		slog.Int64("user_id", reminder.UserId),
+		slog.String("title", reminder.Title),
+		slog.Time("remind_at", reminder.RemindAt),
+	)
+
+	return nil
+}
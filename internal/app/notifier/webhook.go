@@ -0,0 +1,54 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Webhook posts reminders as JSON to the configured url.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{
+		url:    url,
+		client: &http.Client{Timeout: webhookTimeout},
+	}
+}
+
+func (wh *Webhook) Notify(ctx context.Context, reminder models.Reminder) error {
+	const op = "notifier.Webhook.Notify"
+
+	body, err := json.Marshal(reminder)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := wh.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("%s: unexpected status %d", op, res.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,232 @@
+package adminsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Admin struct {
+	log         *slog.Logger
+	users       UserProvider
+	userManager UserManager
+	resets      ResetRequirer
+	resetter    PasswordResetter
+}
+
+type UserProvider interface {
+	Users(ctx context.Context, filter models.UserFilter) ([]models.UserSummary, error)
+	UserDetails(ctx context.Context, userId int64) (models.UserDetails, error)
+}
+
+type UserManager interface {
+	SetUserDisabled(ctx context.Context, userId int64, disabled bool) error
+	SetUserRole(ctx context.Context, userId int64, role string) error
+}
+
+// ResetRequirer refuses sign-in of a user until the password is reset
+// and revokes every token of the user.
+type ResetRequirer interface {
+	RequirePasswordReset(ctx context.Context, userId int64) error
+}
+
+// PasswordResetter mails a password reset link to the owner of email.
+type PasswordResetter interface {
+	ForgotPassword(ctx context.Context, email string) error
+}
+
+var (
+	ErrUserNotFound = errors.New("user not found")
+	ErrInvalidRole  = errors.New("invalid role")
+	// ErrSelfAction is returned when admins try to lock themselves out.
+	ErrSelfAction = errors.New("admins can not disable or demote themselves")
+)
+
+func New(
+	log *slog.Logger,
+	users UserProvider,
+	userManager UserManager,
+	resets ResetRequirer,
+	resetter PasswordResetter,
+) *Admin {
+	return &Admin{
+		log:         log,
+		users:       users,
+		userManager: userManager,
+		resets:      resets,
+		resetter:    resetter,
+	}
+}
+
+// Users returns a page of users matching the filter, NextCursor
+// is set when there may be more users after the page.
+func (a *Admin) Users(ctx context.Context, filter models.UserFilter) (models.UserPage, error) {
+	const op = "services.admin.Users"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	limit := filter.Limit
+	filter.Limit = limit + 1
+
+	users, err := a.users.Users(ctx, filter)
+	if err != nil {
+		log.Error("failed to get users", sl.Err(err))
+
+		return models.UserPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := models.UserPage{Users: users}
+
+	if len(users) > limit {
+		page.Users = users[:limit]
+		page.NextCursor = cursor.Encode(models.Cursor{Id: users[limit-1].Id})
+	}
+
+	return page, nil
+}
+
+func (a *Admin) User(ctx context.Context, userId int64) (models.UserDetails, error) {
+	const op = "services.admin.User"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	user, err := a.users.UserDetails(ctx, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return models.UserDetails{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.UserDetails{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// SetDisabled disables or enables the user, adminId is the admin doing it.
+func (a *Admin) SetDisabled(ctx context.Context, adminId int64, userId int64, disabled bool) error {
+	const op = "services.admin.SetDisabled"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("admin_id", adminId),
+		slog.Int64("user_id", userId),
+		slog.Bool("disabled", disabled),
+	)
+
+	if disabled && adminId == userId {
+		return fmt.Errorf("%s: %w", op, ErrSelfAction)
+	}
+
+	if err := a.userManager.SetUserDisabled(ctx, userId, disabled); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to change user state", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user state changed")
+
+	return nil
+}
+
+func (a *Admin) SetRole(ctx context.Context, adminId int64, userId int64, role string) error {
+	const op = "services.admin.SetRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("admin_id", adminId),
+		slog.Int64("user_id", userId),
+		slog.String("role", role),
+	)
+
+	if role != models.RoleUser && role != models.RoleAdmin {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
+	if role != models.RoleAdmin && adminId == userId {
+		return fmt.Errorf("%s: %w", op, ErrSelfAction)
+	}
+
+	if err := a.userManager.SetUserRole(ctx, userId, role); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to set user role", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user role changed")
+
+	return nil
+}
+
+// ForcePasswordReset signs the user out everywhere, revokes their API tokens
+// and mails a password reset link, sign-in is refused until the password is reset.
+func (a *Admin) ForcePasswordReset(ctx context.Context, adminId int64, userId int64) error {
+	const op = "services.admin.ForcePasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("admin_id", adminId),
+		slog.Int64("user_id", userId),
+	)
+
+	user, err := a.users.UserDetails(ctx, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.resets.RequirePasswordReset(ctx, userId); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to require password reset", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.resetter.ForgotPassword(ctx, user.Email); err != nil {
+		log.Error("failed to send password reset", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset forced")
+
+	return nil
+}
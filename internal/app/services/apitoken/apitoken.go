@@ -0,0 +1,195 @@
+package apitokensrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/token"
+)
+
+// Prefix marks personal access tokens so they are told apart from JWTs.
+const Prefix = "tdp_"
+
+// shownPrefixLen is how much of a token is kept in clear to recognize it in listings.
+const shownPrefixLen = len(Prefix) + 6
+
+type APIToken struct {
+	log *slog.Logger
+	APITokenSaver
+	APITokenProvider
+	APITokenRevoker
+}
+
+type APITokenSaver interface {
+	SaveAPIToken(
+		ctx context.Context,
+		userId int64,
+		input models.CreateAPITokenInput,
+		prefix string,
+		tokenHash string,
+	) (models.APIToken, error)
+}
+
+type APITokenProvider interface {
+	AllAPITokens(ctx context.Context, userId int64) ([]models.APIToken, error)
+	UseAPIToken(ctx context.Context, tokenHash string) (models.APIToken, error)
+}
+
+type APITokenRevoker interface {
+	RevokeAPIToken(ctx context.Context, userId int64, tokenId int64) error
+}
+
+var (
+	ErrTokenNotFound  = errors.New("token not found")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrInvalidScope   = errors.New("invalid scope")
+	ErrInvalidExpires = errors.New("expiry is in the past")
+)
+
+func New(
+	log *slog.Logger,
+	apiTokenSaver APITokenSaver,
+	apiTokenProvider APITokenProvider,
+	apiTokenRevoker APITokenRevoker,
+) *APIToken {
+	return &APIToken{
+		log:              log,
+		APITokenSaver:    apiTokenSaver,
+		APITokenProvider: apiTokenProvider,
+		APITokenRevoker:  apiTokenRevoker,
+	}
+}
+
+// Create issues a new token, the returned secret is never shown again.
+func (a *APIToken) Create(
+	ctx context.Context,
+	userId int64,
+	input models.CreateAPITokenInput,
+) (models.APIToken, string, error) {
+	const op = "services.apitoken.Create"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("Creating api token")
+
+	for _, scope := range input.Scopes {
+		if !slices.Contains(models.Scopes, scope) {
+			log.Warn("invalid scope", slog.String("scope", scope))
+
+			return models.APIToken{}, "", fmt.Errorf("%s: %w: %s", op, ErrInvalidScope, scope)
+		}
+	}
+
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		log.Warn("token expiry is in the past")
+
+		return models.APIToken{}, "", fmt.Errorf("%s: %w", op, ErrInvalidExpires)
+	}
+
+	secret, err := token.Generate()
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+
+		return models.APIToken{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+	secret = Prefix + secret
+
+	input.Name = strings.TrimSpace(input.Name)
+	slices.Sort(input.Scopes)
+	input.Scopes = slices.Compact(input.Scopes)
+
+	apiToken, err := a.APITokenSaver.SaveAPIToken(ctx, userId, input, secret[:shownPrefixLen], token.Hash(secret))
+	if err != nil {
+		log.Error("failed to save api token", sl.Err(err))
+
+		return models.APIToken{}, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api token saved", slog.Int64("id", apiToken.Id))
+
+	return apiToken, secret, nil
+}
+
+func (a *APIToken) AllTokens(ctx context.Context, userId int64) ([]models.APIToken, error) {
+	const op = "services.apitoken.AllTokens"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Getting api tokens")
+
+	tokens, err := a.APITokenProvider.AllAPITokens(ctx, userId)
+	if err != nil {
+		log.Error("failed to get api tokens", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got api tokens")
+
+	return tokens, nil
+}
+
+func (a *APIToken) Revoke(ctx context.Context, userId int64, tokenId int64) error {
+	const op = "services.apitoken.Revoke"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("token_id", tokenId),
+	)
+
+	log.Info("Revoking api token")
+
+	if err := a.APITokenRevoker.RevokeAPIToken(ctx, userId, tokenId); err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("api token not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTokenNotFound)
+		}
+
+		log.Error("failed to revoke api token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("api token revoked")
+
+	return nil
+}
+
+// Authenticate returns the token the secret belongs to,
+// unknown, revoked and expired tokens fail with ErrInvalidToken.
+func (a *APIToken) Authenticate(ctx context.Context, secret string) (models.APIToken, error) {
+	const op = "services.apitoken.Authenticate"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	apiToken, err := a.APITokenProvider.UseAPIToken(ctx, token.Hash(secret))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) || errors.Is(err, storage.ErrTokenExpired) {
+			log.Warn("invalid api token", sl.Err(err))
+
+			return models.APIToken{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to get api token", sl.Err(err))
+
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return apiToken, nil
+}
@@ -0,0 +1,435 @@
+// Package archivesrv exports the data of a user as a ZIP archive of JSON
+// files and imports such archives into empty accounts.
+//
+// An archive holds manifest.json, profile.json, lists.json, tags.json and
+// items.json. Items are ordered by id, so parents come before their subtasks.
+package archivesrv
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	itemsrv "github.com/Muaz717/todo-app/internal/app/services/item"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/rrule"
+	"golang.org/x/text/language"
+)
+
+const (
+	manifestFile = "manifest.json"
+	profileFile  = "profile.json"
+	listsFile    = "lists.json"
+	tagsFile     = "tags.json"
+	itemsFile    = "items.json"
+)
+
+type Archive struct {
+	log      *slog.Logger
+	exporter Exporter
+	importer Importer
+}
+
+type Exporter interface {
+	ExportUserData(ctx context.Context, userId int64, sink models.ExportSink) error
+}
+
+type Importer interface {
+	HasUserData(ctx context.Context, userId int64) (bool, error)
+	ImportUserData(ctx context.Context, userId int64, data models.Import) error
+}
+
+var (
+	ErrInvalidArchive  = errors.New("invalid archive")
+	ErrAccountNotEmpty = errors.New("account already has data")
+)
+
+func New(
+	log *slog.Logger,
+	exporter Exporter,
+	importer Importer,
+) *Archive {
+	return &Archive{
+		log:      log,
+		exporter: exporter,
+		importer: importer,
+	}
+}
+
+// Export writes the archive of the user to w as it is read from storage.
+func (a *Archive) Export(ctx context.Context, userId int64, w io.Writer) error {
+	const op = "services.archive.Export"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("exporting user data")
+
+	zw := zip.NewWriter(w)
+	sink := &zipSink{zw: zw}
+
+	manifest := models.Manifest{
+		Version:    models.ArchiveVersion,
+		ExportedAt: time.Now().UTC(),
+	}
+
+	if err := sink.writeFile(manifestFile, manifest); err != nil {
+		log.Error("failed to write manifest", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.exporter.ExportUserData(ctx, userId, sink); err != nil {
+		log.Error("failed to export user data", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := sink.finish(); err != nil {
+		log.Error("failed to write items", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := zw.Close(); err != nil {
+		log.Error("failed to close archive", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user data exported", slog.Int("items", sink.items))
+
+	return nil
+}
+
+// Import restores an archive into the account of the user, which must not
+// have any items, lists or tags yet. Items are decoded as they are saved.
+func (a *Archive) Import(ctx context.Context, userId int64, r io.ReaderAt, size int64) error {
+	const op = "services.archive.Import"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("importing user data")
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		log.Warn("failed to open archive", sl.Err(err))
+
+		return fmt.Errorf("%s: %w: %w", op, ErrInvalidArchive, err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	for _, name := range []string{manifestFile, profileFile, listsFile, tagsFile, itemsFile} {
+		if files[name] == nil {
+			log.Warn("file is missing from archive", slog.String("file", name))
+
+			return fmt.Errorf("%s: %w: %s is missing", op, ErrInvalidArchive, name)
+		}
+	}
+
+	var manifest models.Manifest
+	if err := readJSON(files[manifestFile], &manifest); err != nil {
+		log.Warn("invalid manifest", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if manifest.Version != models.ArchiveVersion {
+		log.Warn("unsupported archive version", slog.Int("version", manifest.Version))
+
+		return fmt.Errorf("%s: %w: unsupported version %d", op, ErrInvalidArchive, manifest.Version)
+	}
+
+	hasData, err := a.importer.HasUserData(ctx, userId)
+	if err != nil {
+		log.Error("failed to check user data", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if hasData {
+		log.Warn("account already has data")
+
+		return fmt.Errorf("%s: %w", op, ErrAccountNotEmpty)
+	}
+
+	var (
+		profile models.Profile
+		lists   []models.List
+		tags    []models.Tag
+	)
+
+	if err := readJSON(files[profileFile], &profile); err != nil {
+		log.Warn("invalid profile", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := readJSON(files[listsFile], &lists); err != nil {
+		log.Warn("invalid lists", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := readJSON(files[tagsFile], &tags); err != nil {
+		log.Warn("invalid tags", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := validate(lists, tags); err != nil {
+		log.Warn("invalid archive", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	data := models.Import{
+		Profile: profileInput(profile),
+		Lists:   lists,
+		Tags:    tags,
+		Items:   readItems(files[itemsFile]),
+	}
+
+	if err := a.importer.ImportUserData(ctx, userId, data); err != nil {
+		if errors.Is(err, ErrInvalidArchive) ||
+			errors.Is(err, storage.ErrListNotFound) ||
+			errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("invalid archive", sl.Err(err))
+
+			return fmt.Errorf("%s: %w: %w", op, ErrInvalidArchive, err)
+		}
+
+		log.Error("failed to import user data", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user data imported")
+
+	return nil
+}
+
+// zipSink writes the data of a user into the archive, items are
+// encoded one by one into a JSON array.
+type zipSink struct {
+	zw    *zip.Writer
+	w     io.Writer
+	enc   *json.Encoder
+	items int
+}
+
+func (s *zipSink) Profile(profile models.Profile) error {
+	return s.writeFile(profileFile, profile)
+}
+
+func (s *zipSink) Lists(lists []models.List) error {
+	if lists == nil {
+		lists = []models.List{}
+	}
+
+	return s.writeFile(listsFile, lists)
+}
+
+func (s *zipSink) Tags(tags []models.Tag) error {
+	if tags == nil {
+		tags = []models.Tag{}
+	}
+
+	return s.writeFile(tagsFile, tags)
+}
+
+func (s *zipSink) Item(item models.Item) error {
+	sep := ",\n"
+
+	if s.w == nil {
+		w, err := s.zw.Create(itemsFile)
+		if err != nil {
+			return err
+		}
+
+		s.w, s.enc, sep = w, json.NewEncoder(w), "[\n"
+	}
+
+	if _, err := io.WriteString(s.w, sep); err != nil {
+		return err
+	}
+
+	s.items++
+
+	return s.enc.Encode(item)
+}
+
+// finish closes the items array, it writes an empty one if there were no items.
+func (s *zipSink) finish() error {
+	if s.w == nil {
+		return s.writeFile(itemsFile, []models.Item{})
+	}
+
+	_, err := io.WriteString(s.w, "]\n")
+
+	return err
+}
+
+func (s *zipSink) writeFile(name string, v any) error {
+	w, err := s.zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(w).Encode(v)
+}
+
+func readJSON(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err)
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(rc).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err)
+	}
+
+	return nil
+}
+
+// readItems decodes the items array lazily, each item is checked
+// before it is handed over.
+func readItems(f *zip.File) iter.Seq2[models.Item, error] {
+	return func(yield func(models.Item, error) bool) {
+		rc, err := f.Open()
+		if err != nil {
+			yield(models.Item{}, fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err))
+
+			return
+		}
+		defer rc.Close()
+
+		dec := json.NewDecoder(rc)
+
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			yield(models.Item{}, fmt.Errorf("%w: %s is not an array", ErrInvalidArchive, f.Name))
+
+			return
+		}
+
+		// depths of the items read so far, top level items have depth 1
+		depths := make(map[int64]int)
+
+		for dec.More() {
+			var item models.Item
+
+			if err := dec.Decode(&item); err != nil {
+				yield(models.Item{}, fmt.Errorf("%w: %s: %w", ErrInvalidArchive, f.Name, err))
+
+				return
+			}
+
+			depth := 1
+			if item.ParentId != nil {
+				depth = depths[*item.ParentId] + 1
+			}
+
+			if err := validateItem(item, depth); err != nil {
+				yield(models.Item{}, err)
+
+				return
+			}
+
+			depths[int64(item.Id)] = depth
+
+			if !yield(item, nil) {
+				return
+			}
+		}
+	}
+}
+
+func validate(lists []models.List, tags []models.Tag) error {
+	for _, l := range lists {
+		if n := utf8.RuneCountInString(l.Name); n == 0 || n > 255 || utf8.RuneCountInString(l.Color) > 16 {
+			return fmt.Errorf("%w: list %d is invalid", ErrInvalidArchive, l.Id)
+		}
+	}
+
+	for _, t := range tags {
+		if n := utf8.RuneCountInString(t.Name); n == 0 || n > 64 {
+			return fmt.Errorf("%w: tag %d is invalid", ErrInvalidArchive, t.Id)
+		}
+	}
+
+	return nil
+}
+
+func validateItem(item models.Item, depth int) error {
+	if n := utf8.RuneCountInString(item.Title); n == 0 || n > 255 {
+		return fmt.Errorf("%w: item %d has an invalid title", ErrInvalidArchive, item.Id)
+	}
+
+	if utf8.RuneCountInString(item.Description) > 255 {
+		return fmt.Errorf("%w: item %d has an invalid description", ErrInvalidArchive, item.Id)
+	}
+
+	if item.Priority < models.PriorityNone || item.Priority > models.PriorityHigh {
+		return fmt.Errorf("%w: item %d has an invalid priority", ErrInvalidArchive, item.Id)
+	}
+
+	if item.RRule != "" {
+		if _, err := rrule.Parse(item.RRule); err != nil {
+			return fmt.Errorf("%w: item %d: %w", ErrInvalidArchive, item.Id, err)
+		}
+	}
+
+	if depth > itemsrv.MaxItemDepth {
+		return fmt.Errorf("%w: item %d is nested too deep", ErrInvalidArchive, item.Id)
+	}
+
+	for _, name := range item.Tags {
+		if n := utf8.RuneCountInString(name); n == 0 || n > 64 {
+			return fmt.Errorf("%w: item %d has an invalid tag", ErrInvalidArchive, item.Id)
+		}
+	}
+
+	return nil
+}
+
+// profileInput takes the editable profile fields, values the account
+// would not accept are left out so the defaults stay.
+func profileInput(p models.Profile) models.UpdateProfileInput {
+	var input models.UpdateProfileInput
+
+	if utf8.RuneCountInString(p.DisplayName) <= 100 {
+		input.DisplayName = &p.DisplayName
+	}
+
+	if p.Timezone != "" && p.Timezone != "Local" {
+		if _, err := time.LoadLocation(p.Timezone); err == nil {
+			input.Timezone = &p.Timezone
+		}
+	}
+
+	if tag, err := language.Parse(p.Locale); err == nil {
+		locale := tag.String()
+		input.Locale = &locale
+	}
+
+	return input
+}
@@ -0,0 +1,255 @@
+package archivesrv_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	archivesrv "github.com/Muaz717/todo-app/internal/app/services/archive"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStore keeps the data of users in memory, imported data is
+// collected the way the storage would save it.
+type fakeStore struct {
+	profiles map[int64]models.Profile
+	lists    map[int64][]models.List
+	tags     map[int64][]models.Tag
+	items    map[int64][]models.Item
+	imported map[int64]models.UpdateProfileInput
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		profiles: make(map[int64]models.Profile),
+		lists:    make(map[int64][]models.List),
+		tags:     make(map[int64][]models.Tag),
+		items:    make(map[int64][]models.Item),
+		imported: make(map[int64]models.UpdateProfileInput),
+	}
+}
+
+func (f *fakeStore) ExportUserData(_ context.Context, userId int64, sink models.ExportSink) error {
+	if err := sink.Profile(f.profiles[userId]); err != nil {
+		return err
+	}
+
+	if err := sink.Lists(f.lists[userId]); err != nil {
+		return err
+	}
+
+	if err := sink.Tags(f.tags[userId]); err != nil {
+		return err
+	}
+
+	for _, item := range f.items[userId] {
+		if err := sink.Item(item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeStore) HasUserData(_ context.Context, userId int64) (bool, error) {
+	return len(f.lists[userId]) > 0 || len(f.tags[userId]) > 0 || len(f.items[userId]) > 0, nil
+}
+
+func (f *fakeStore) ImportUserData(_ context.Context, userId int64, data models.Import) error {
+	var items []models.Item
+
+	for item, err := range data.Items {
+		if err != nil {
+			return err
+		}
+
+		items = append(items, item)
+	}
+
+	f.imported[userId] = data.Profile
+	f.lists[userId] = data.Lists
+	f.tags[userId] = data.Tags
+	f.items[userId] = items
+
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestRoundTrip(t *testing.T) {
+	dueAt := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		profile models.Profile
+		lists   []models.List
+		tags    []models.Tag
+		items   []models.Item
+	}{
+		{
+			name:    "Empty account",
+			profile: models.Profile{Id: 1, Email: "test@mail.ru", Timezone: "UTC", Locale: "en"},
+			lists:   []models.List{},
+			tags:    []models.Tag{},
+		},
+		{
+			name:    "Lists, tags and subtasks",
+			profile: models.Profile{Id: 1, Email: "test@mail.ru", DisplayName: "Test", Timezone: "Europe/Moscow", Locale: "ru"},
+			lists:   []models.List{{Id: 1, Name: "Work", Color: "#ff0000"}, {Id: 2, Name: "Home", Position: 1, Archived: true}},
+			tags:    []models.Tag{{Id: 1, Name: "urgent"}},
+			items: []models.Item{
+				{Id: 1, Title: "Release", ListId: ptr(int64(1)), Tags: []string{"urgent"}, DueAt: &dueAt, Priority: models.PriorityHigh},
+				{Id: 2, Title: "Changelog", ListId: ptr(int64(1)), ParentId: ptr(int64(1))},
+				{Id: 3, Title: "Typos", ListId: ptr(int64(1)), ParentId: ptr(int64(2)), Done: true},
+				{Id: 4, Title: "Plants", Description: "water", RRule: "FREQ=WEEKLY;COUNT=3", DueAt: &dueAt},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newFakeStore()
+			store.profiles[1] = tt.profile
+			store.lists[1] = tt.lists
+			store.tags[1] = tt.tags
+			store.items[1] = tt.items
+
+			archive := archivesrv.New(slogdiscard.NewDiscardLogger(), store, store)
+
+			var buf bytes.Buffer
+			require.NoError(t, archive.Export(context.Background(), 1, &buf))
+
+			err := archive.Import(context.Background(), 2, bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			require.NoError(t, err)
+
+			require.Equal(t, tt.lists, store.lists[2])
+			require.Equal(t, tt.tags, store.tags[2])
+			require.Equal(t, tt.items, store.items[2])
+
+			profile := store.imported[2]
+			require.Equal(t, tt.profile.DisplayName, *profile.DisplayName)
+			require.Equal(t, tt.profile.Timezone, *profile.Timezone)
+			require.Equal(t, tt.profile.Locale, *profile.Locale)
+		})
+	}
+}
+
+// zipFiles builds an archive of the given files, values are encoded as JSON.
+func zipFiles(t *testing.T, files map[string]any) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	zw := zip.NewWriter(&buf)
+
+	for name, v := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+		require.NoError(t, json.NewEncoder(w).Encode(v))
+	}
+
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestImportErrors(t *testing.T) {
+	valid := func() map[string]any {
+		return map[string]any{
+			"manifest.json": models.Manifest{Version: models.ArchiveVersion},
+			"profile.json":  models.Profile{},
+			"lists.json":    []models.List{},
+			"tags.json":     []models.Tag{},
+			"items.json":    []models.Item{{Id: 1, Title: "item"}},
+		}
+	}
+
+	with := func(name string, v any) []byte {
+		files := valid()
+		if v == nil {
+			delete(files, name)
+		} else {
+			files[name] = v
+		}
+
+		return zipFiles(t, files)
+	}
+
+	tests := []struct {
+		name     string
+		archive  []byte
+		existing []models.Item
+		err      error
+	}{
+		{
+			name:    "Not a zip",
+			archive: []byte("plain text"),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:    "Missing file",
+			archive: with("tags.json", nil),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:    "Unsupported version",
+			archive: with("manifest.json", models.Manifest{Version: models.ArchiveVersion + 1}),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:    "Invalid list",
+			archive: with("lists.json", []models.List{{Id: 1}}),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:    "Items not an array",
+			archive: with("items.json", models.Item{Id: 1, Title: "item"}),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:    "Invalid rrule",
+			archive: with("items.json", []models.Item{{Id: 1, Title: "item", RRule: "FREQ=SOMETIMES"}}),
+			err:     archivesrv.ErrInvalidArchive,
+		},
+		{
+			name: "Nested too deep",
+			archive: with("items.json", []models.Item{
+				{Id: 1, Title: "item"},
+				{Id: 2, Title: "item", ParentId: ptr(int64(1))},
+				{Id: 3, Title: "item", ParentId: ptr(int64(2))},
+				{Id: 4, Title: "item", ParentId: ptr(int64(3))},
+			}),
+			err: archivesrv.ErrInvalidArchive,
+		},
+		{
+			name:     "Account not empty",
+			archive:  zipFiles(t, valid()),
+			existing: []models.Item{{Id: 1, Title: "item"}},
+			err:      archivesrv.ErrAccountNotEmpty,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			store := newFakeStore()
+			store.items[1] = tt.existing
+
+			archive := archivesrv.New(slogdiscard.NewDiscardLogger(), store, store)
+
+			err := archive.Import(context.Background(), 1, bytes.NewReader(tt.archive), int64(len(tt.archive)))
+			require.ErrorIs(t, err, tt.err)
+			require.Equal(t, tt.existing, store.items[1])
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/token"
+	"golang.org/x/text/language"
+)
+
+type AccountStore interface {
+	Profile(ctx context.Context, userId int64) (models.Profile, error)
+	UpdateProfile(ctx context.Context, userId int64, input models.UpdateProfileInput) (models.Profile, error)
+	SaveEmailChange(ctx context.Context, userId int64, newEmail string, tokenHash string, expiresAt time.Time) error
+	DeleteUser(ctx context.Context, userId int64) error
+}
+
+var (
+	ErrInvalidTimezone = errors.New("invalid timezone")
+	ErrInvalidLocale   = errors.New("invalid locale")
+)
+
+func (a *Auth) Profile(ctx context.Context, userId int64) (models.Profile, error) {
+	const op = "services.auth.Profile"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	profile, err := a.account.Profile(ctx, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return models.Profile{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get profile", sl.Err(err))
+
+		return models.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+// UpdateProfile changes the given profile fields. Timezones are IANA names
+// such as "Europe/Moscow", locales are BCP 47 tags and are stored canonicalized.
+func (a *Auth) UpdateProfile(
+	ctx context.Context,
+	userId int64,
+	input models.UpdateProfileInput,
+) (models.Profile, error) {
+	const op = "services.auth.UpdateProfile"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	if input.Timezone != nil {
+		// LoadLocation takes "" and "Local" for UTC and the zone of the server
+		if *input.Timezone == "" || *input.Timezone == "Local" {
+			return models.Profile{}, fmt.Errorf("%s: %w", op, ErrInvalidTimezone)
+		}
+
+		if _, err := time.LoadLocation(*input.Timezone); err != nil {
+			return models.Profile{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidTimezone, err)
+		}
+	}
+
+	if input.Locale != nil {
+		tag, err := language.Parse(*input.Locale)
+		if err != nil {
+			return models.Profile{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidLocale, err)
+		}
+
+		locale := tag.String()
+		input.Locale = &locale
+	}
+
+	profile, err := a.account.UpdateProfile(ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return models.Profile{}, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to update profile", sl.Err(err))
+
+		return models.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("profile updated")
+
+	return profile, nil
+}
+
+// ChangePassword sets a new password after checking the current one,
+// every session and API token of the user is revoked.
+func (a *Auth) ChangePassword(ctx context.Context, userId int64, currentPassword string, newPassword string) error {
+	const op = "services.auth.ChangePassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("changing password")
+
+	if _, err := a.reauthenticate(ctx, log, userId, currentPassword); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.policy.Check(newPassword); err != nil {
+		log.Info("password rejected by policy", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.usrSaver.ChangePassword(ctx, userId, []byte(passHash)); err != nil {
+		log.Error("failed to change password", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password changed")
+
+	return nil
+}
+
+// ChangeEmail mails a confirmation link to the new email, the email of
+// the account changes once the link is followed.
+func (a *Auth) ChangeEmail(ctx context.Context, userId int64, password string, newEmail string) error {
+	const op = "services.auth.ChangeEmail"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("changing email")
+
+	if _, err := a.reauthenticate(ctx, log, userId, password); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err := a.usrProvider.User(ctx, newEmail)
+	if err == nil {
+		log.Warn("email is already taken")
+
+		return fmt.Errorf("%s: %w", op, ErrUserExists)
+	}
+	if !errors.Is(err, storage.ErrUserNotFound) {
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.sendEmailChange(ctx, userId, newEmail); err != nil {
+		log.Error("failed to send email change confirmation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email change confirmation sent")
+
+	return nil
+}
+
+// DeleteAccount deletes the user with all items, lists and tags after
+// checking the password. Shared workspaces of the user go to one of their admins,
+// ErrWorkspaceOwned is returned while one of them has none. Issued access
+// tokens stay valid until they expire.
+func (a *Auth) DeleteAccount(ctx context.Context, userId int64, password string) error {
+	const op = "services.auth.DeleteAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("deleting account")
+
+	if _, err := a.reauthenticate(ctx, log, userId, password); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.account.DeleteUser(ctx, userId); err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		if errors.Is(err, storage.ErrWorkspaceOwned) {
+			log.Warn("shared workspace has no admin", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrWorkspaceOwned)
+		}
+
+		log.Error("failed to delete user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("account deleted")
+
+	return nil
+}
+
+// reauthenticate checks the password of a signed in user, failures are
+// counted against the account like failed logins.
+func (a *Auth) reauthenticate(ctx context.Context, log *slog.Logger, userId int64, password string) (models.User, error) {
+	user, err := a.usrProvider.UserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return models.User{}, ErrUserNotFound
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	keys := a.loginKeys(user.Email, "")
+
+	if err := a.checkLockout(ctx, keys); err != nil {
+		log.Warn("account is locked", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	ok, err := a.hasher.Verify(password, string(user.PassHash))
+	if err != nil {
+		log.Error("failed to verify password", sl.Err(err))
+
+		return models.User{}, err
+	}
+
+	if !ok {
+		log.Info("invalid password")
+
+		a.loginFailed(ctx, log, keys)
+
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	return user, nil
+}
+
+func (a *Auth) sendEmailChange(ctx context.Context, userId int64, newEmail string) error {
+	verifyToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(a.cfg.VerificationTTL)
+
+	if err := a.account.SaveEmailChange(ctx, userId, newEmail, token.Hash(verifyToken), expiresAt); err != nil {
+		return err
+	}
+
+	link := a.cfg.AppURL + "/auth/verify?token=" + url.QueryEscape(verifyToken)
+
+	msg := mailer.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email",
+		Body: fmt.Sprintf(
+			"You asked to change the email address of your account to this one.\n\n"+
+				"Follow the link to confirm it, it is valid for %s:\n%s\n\n"+
+				"If you did not ask for it, ignore this email.\n",
+			a.cfg.VerificationTTL, link,
+		),
+	}
+
+	return a.mailer.Send(ctx, msg)
+}
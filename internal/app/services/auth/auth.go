@@ -5,22 +5,48 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"os"
 	"time"
 
+	"github.com/Muaz717/todo-app/internal/app/mailer"
 	"github.com/Muaz717/todo-app/internal/app/storage"
 	"github.com/Muaz717/todo-app/internal/domain/models"
 	"github.com/Muaz717/todo-app/internal/lib/jwt"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
-
-	"golang.org/x/crypto/bcrypt"
+	"github.com/Muaz717/todo-app/internal/lib/token"
 )
 
 type Auth struct {
-	log         *slog.Logger
-	usrSaver    UserSaver
-	usrProvider UserProvider
-	tokenTTL    time.Duration
+	log          *slog.Logger
+	usrSaver     UserSaver
+	usrProvider  UserProvider
+	tokenSaver   TokenSaver
+	tokenRevoker TokenRevoker
+	resetter     PasswordResetter
+	verifier     EmailVerifier
+	throttler    LoginThrottler
+	twoFactor    TwoFactorStore
+	account      AccountStore
+	hasher       PasswordHasher
+	policy       PasswordPolicy
+	signer       jwt.Signer
+	mailer       Mailer
+	cfg          Config
+}
+
+// Config holds lifetimes of issued tokens and the address of the web app
+// links in emails point to.
+type Config struct {
+	TokenTTL         time.Duration
+	RefreshTTL       time.Duration
+	PasswordResetTTL time.Duration
+	VerificationTTL  time.Duration
+	AppURL           string
+	// AllowUnverified lets users log in before they verify their email.
+	AllowUnverified bool
+	Lockout         LockoutPolicy
+	// TOTPIssuer names the app in authenticator apps.
+	TOTPIssuer   string
+	ChallengeTTL time.Duration
 }
 
 type UserSaver interface {
@@ -29,10 +55,56 @@ type UserSaver interface {
 		email string,
 		passHash []byte,
 	) (uid int64, err error)
+	UpdatePasswordHash(ctx context.Context, userId int64, passHash []byte) error
+	// ChangePassword sets the password and revokes every credential of the user.
+	ChangePassword(ctx context.Context, userId int64, passHash []byte) error
+}
+
+// PasswordHasher hashes new passwords and verifies stored hashes,
+// NeedsRehash reports hashes made with outdated parameters.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password string, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
+// PasswordPolicy rejects weak passwords with a *password.PolicyError.
+type PasswordPolicy interface {
+	Check(password string) error
 }
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (models.User, error)
+	UserById(ctx context.Context, userId int64) (models.User, error)
+}
+
+type TokenSaver interface {
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	RotateRefreshToken(
+		ctx context.Context,
+		tokenHash string,
+		nextHash string,
+		expiresAt time.Time,
+	) (models.User, error)
+}
+
+type TokenRevoker interface {
+	RevokeRefreshFamily(ctx context.Context, tokenHash string) error
+	RevokeUserRefreshTokens(ctx context.Context, userId int64) error
+}
+
+type PasswordResetter interface {
+	SavePasswordReset(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error
+	ResetPassword(ctx context.Context, tokenHash string, passHash []byte) (int64, error)
+}
+
+type EmailVerifier interface {
+	SaveEmailVerification(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error
+	VerifyEmail(ctx context.Context, tokenHash string) (int64, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
 }
 
 var (
@@ -40,6 +112,15 @@ var (
 	ErrInvalidAppId       = errors.New("invalid app id")
 	ErrUserExists         = errors.New("user already exists")
 	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidToken       = errors.New("invalid refresh token")
+	ErrTokenReused        = errors.New("refresh token reused")
+	ErrInvalidResetToken  = errors.New("invalid or expired reset token")
+	ErrInvalidVerifyToken = errors.New("invalid or expired verification token")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrTooManyAttempts    = errors.New("too many login attempts")
+	ErrAccountDisabled    = errors.New("account is disabled")
+	ErrResetRequired      = errors.New("password reset is required")
+	ErrWorkspaceOwned     = errors.New("a shared workspace has no admin to take it over")
 )
 
 // New returns a new instance of Auth service
@@ -47,13 +128,35 @@ func New(
 	log *slog.Logger,
 	userSaver UserSaver,
 	userProvider UserProvider,
-	tokenTTL time.Duration,
+	tokenSaver TokenSaver,
+	tokenRevoker TokenRevoker,
+	resetter PasswordResetter,
+	verifier EmailVerifier,
+	throttler LoginThrottler,
+	twoFactor TwoFactorStore,
+	account AccountStore,
+	hasher PasswordHasher,
+	policy PasswordPolicy,
+	signer jwt.Signer,
+	mailer Mailer,
+	cfg Config,
 ) *Auth {
 	return &Auth{
-		log:         log,
-		usrSaver:    userSaver,
-		usrProvider: userProvider,
-		tokenTTL:    tokenTTL,
+		log:          log,
+		usrSaver:     userSaver,
+		usrProvider:  userProvider,
+		tokenSaver:   tokenSaver,
+		tokenRevoker: tokenRevoker,
+		resetter:     resetter,
+		verifier:     verifier,
+		throttler:    throttler,
+		twoFactor:    twoFactor,
+		account:      account,
+		hasher:       hasher,
+		policy:       policy,
+		signer:       signer,
+		mailer:       mailer,
+		cfg:          cfg,
 	}
 }
 
@@ -67,65 +170,331 @@ func (a *Auth) RegisterNewUser(ctx context.Context, email string, password strin
 
 	log.Info("registering user")
 
-	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := a.policy.Check(password); err != nil {
+		log.Info("password rejected by policy", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(password)
 	if err != nil {
 		log.Error("failed to generate password hash", sl.Err(err))
 
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	userId, err := a.usrSaver.SaveUser(ctx, email, passHash)
+	userId, err := a.usrSaver.SaveUser(ctx, email, []byte(passHash))
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
 			log.Warn("user already exists", sl.Err(err))
 
 			return 0, fmt.Errorf("%s: %w", op, ErrUserExists)
 		}
+
+		log.Error("failed to save user", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("user registered", "uid", userId)
 
+	// the account is created anyway, the user can ask for another email
+	if err := a.sendVerification(ctx, models.User{Id: userId, Email: email}); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+	}
+
 	return userId, nil
 }
 
-func (a *Auth) Login(ctx context.Context, email string, password string) (string, error) {
+// Login checks credentials and starts a new session, i.e. a new family
+// of refresh tokens. Users with two-factor authentication get a challenge
+// to answer with VerifyTwoFactor instead. Failed attempts are counted
+// per account and per client ip, a LockedError is returned while
+// either of them is locked out.
+func (a *Auth) Login(ctx context.Context, email string, password string, ip string) (models.LoginResult, error) {
 	const op = "services.auth.Login"
 
 	log := a.log.With(
 		slog.String("op", op),
 		slog.String("email", email),
+		slog.String("ip", ip),
 	)
 
 	log.Info("attempting to login user")
 
+	event := models.LoginEvent{Email: email, IP: ip}
+	keys := a.loginKeys(email, ip)
+
+	if err := a.checkLockout(ctx, keys); err != nil {
+		var lockedErr *LockedError
+		if errors.As(err, &lockedErr) {
+			log.Warn("login is locked", slog.Time("until", lockedErr.Until))
+
+			event.Reason = models.LoginLocked
+			a.saveLoginEvent(ctx, log, event)
+
+			return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to check lockout", sl.Err(err))
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	user, err := a.usrProvider.User(ctx, email)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Warn("user not found", sl.Err(err))
 
-			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+			// unknown emails are throttled the same way, so they can not be told apart
+			a.loginFailed(ctx, log, keys)
+
+			event.Reason = models.LoginInvalidCredentials
+			a.saveLoginEvent(ctx, log, event)
+
+			return models.LoginResult{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
 
 		log.Error("failed to get user", sl.Err(err))
 
-		return "", fmt.Errorf("%s: %w", op, err)
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ok, err := a.hasher.Verify(password, string(user.PassHash))
+	if err != nil {
+		log.Error("failed to verify password", sl.Err(err))
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !ok {
+		log.Info("invalid password")
+
+		a.loginFailed(ctx, log, keys)
+
+		event.Reason = models.LoginInvalidCredentials
+		a.saveLoginEvent(ctx, log, event)
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		log.Info("invalid password", sl.Err(err))
+	a.rehash(ctx, log, user, password)
+
+	// only the account is forgiven, an ip guessing many accounts stays throttled,
+	// with two-factor authentication it is forgiven once the code is right
+	if !user.TwoFactorEnabled {
+		if err := a.throttler.ResetLoginFailures(ctx, keys[0].key); err != nil {
+			log.Error("failed to reset login failures", sl.Err(err))
+		}
+	}
+
+	if user.Disabled {
+		log.Info("account is disabled")
+
+		event.Reason = models.LoginDisabled
+		a.saveLoginEvent(ctx, log, event)
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, ErrAccountDisabled)
+	}
+
+	if user.PasswordResetRequired {
+		log.Info("password reset is required")
+
+		event.Reason = models.LoginResetRequired
+		a.saveLoginEvent(ctx, log, event)
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, ErrResetRequired)
+	}
+
+	if !user.EmailVerified && !a.cfg.AllowUnverified {
+		log.Info("email is not verified")
+
+		event.Reason = models.LoginNotVerified
+		a.saveLoginEvent(ctx, log, event)
+
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, ErrEmailNotVerified)
+	}
+
+	event.Success = true
+	a.saveLoginEvent(ctx, log, event)
+
+	if user.TwoFactorEnabled {
+		challenge, err := a.newChallenge(ctx, user.Id)
+		if err != nil {
+			log.Error("failed to create two-factor challenge", sl.Err(err))
+
+			return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("two-factor challenge issued")
+
+		return challenge, nil
+	}
+
+	pair, err := a.newSession(ctx, user)
+	if err != nil {
+		log.Error("failed to start session", sl.Err(err))
 
-		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+		return models.LoginResult{}, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("user logged successfully")
 
-	secret := os.Getenv("MY_SECRET")
-	token, err := jwt.NewToken(user, a.tokenTTL, secret)
+	return models.LoginResult{Tokens: pair}, nil
+}
+
+// rehash upgrades the stored hash of the password once it was made with
+// an outdated algorithm or parameters, failing to do so does not fail login.
+func (a *Auth) rehash(ctx context.Context, log *slog.Logger, user models.User, password string) {
+	if !a.hasher.NeedsRehash(string(user.PassHash)) {
+		return
+	}
+
+	passHash, err := a.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to rehash password", sl.Err(err))
+
+		return
+	}
+
+	if err := a.usrSaver.UpdatePasswordHash(ctx, user.Id, []byte(passHash)); err != nil {
+		log.Error("failed to save rehashed password", sl.Err(err))
+
+		return
+	}
+
+	log.Info("password rehashed")
+}
+
+// newSession starts a new family of refresh tokens and issues its first pair.
+func (a *Auth) newSession(ctx context.Context, user models.User) (models.TokenPair, error) {
+	familyId, err := token.Generate()
 	if err != nil {
-		log.Info("failed to get token", sl.Err(err))
+		return models.TokenPair{}, err
+	}
 
-		return "", fmt.Errorf("%s: %w", op, err)
+	refreshToken, err := token.Generate()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	err = a.tokenSaver.SaveRefreshToken(ctx, models.RefreshToken{
+		UserId:    user.Id,
+		FamilyId:  familyId,
+		TokenHash: token.Hash(refreshToken),
+		ExpiresAt: time.Now().Add(a.cfg.RefreshTTL),
+	})
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	return a.tokenPair(user, refreshToken)
+}
+
+// Refresh exchanges a refresh token for a new token pair, the presented token
+// can not be used again. Reusing it revokes every token of its family.
+func (a *Auth) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	const op = "services.auth.Refresh"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("refreshing token")
+
+	nextToken, err := token.Generate()
+	if err != nil {
+		log.Error("failed to generate refresh token", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.tokenSaver.RotateRefreshToken(
+		ctx,
+		token.Hash(refreshToken),
+		token.Hash(nextToken),
+		time.Now().Add(a.cfg.RefreshTTL),
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenReused) {
+			log.Warn("refresh token reused, token family revoked", sl.Err(err))
+
+			return models.TokenPair{}, fmt.Errorf("%s: %w", op, ErrTokenReused)
+		}
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("refresh token not found", sl.Err(err))
+
+			return models.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to rotate refresh token", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pair, err := a.tokenPair(user, nextToken)
+	if err != nil {
+		log.Error("failed to get token", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("token refreshed", slog.Int64("user_id", user.Id))
+
+	return pair, nil
+}
+
+// Logout ends the session the refresh token belongs to.
+func (a *Auth) Logout(ctx context.Context, refreshToken string) error {
+	const op = "services.auth.Logout"
+
+	log := a.log.With(slog.String("op", op))
+
+	if err := a.tokenRevoker.RevokeRefreshFamily(ctx, token.Hash(refreshToken)); err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("refresh token not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+
+		log.Error("failed to revoke refresh token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged out")
+
+	return nil
+}
+
+// LogoutAll ends every session of the user. Access tokens already
+// issued stay valid until they expire.
+func (a *Auth) LogoutAll(ctx context.Context, userId int64) error {
+	const op = "services.auth.LogoutAll"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	if err := a.tokenRevoker.RevokeUserRefreshTokens(ctx, userId); err != nil {
+		log.Error("failed to revoke refresh tokens", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged out everywhere")
+
+	return nil
+}
+
+func (a *Auth) tokenPair(user models.User, refreshToken string) (models.TokenPair, error) {
+	accessToken, err := jwt.NewToken(user, a.cfg.TokenTTL, a.signer)
+	if err != nil {
+		return models.TokenPair{}, err
 	}
 
-	return token, nil
+	return models.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    time.Now().Add(a.cfg.TokenTTL),
+	}, nil
 }
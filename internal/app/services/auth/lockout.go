@@ -0,0 +1,129 @@
+package authService
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+// LoginThrottler keeps failed sign-in attempts, it is shared by every replica.
+type LoginThrottler interface {
+	LoginLockedUntil(ctx context.Context, keys []string) (time.Time, error)
+	RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, key string) error
+	SaveLoginEvent(ctx context.Context, event models.LoginEvent) error
+}
+
+// LockoutPolicy limits failed sign-in attempts per account and per client IP.
+// Once a key reaches its threshold of failures in a row every further failure
+// locks it out for twice as long as the previous one, starting at BaseDelay
+// and capped at MaxDelay. Failures are forgotten Window after the last one.
+type LockoutPolicy struct {
+	AccountThreshold int
+	IPThreshold      int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	Window           time.Duration
+}
+
+// LockedError is returned while sign-in is locked out, it matches ErrTooManyAttempts.
+type LockedError struct {
+	Until time.Time
+}
+
+func (e *LockedError) Error() string {
+	return ErrTooManyAttempts.Error()
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// delay returns how long the failure number failures locks a key out for.
+func (p LockoutPolicy) delay(failures int, threshold int) time.Duration {
+	if threshold <= 0 || failures < threshold {
+		return 0
+	}
+
+	delay := p.BaseDelay
+	for i := threshold; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, p.MaxDelay)
+}
+
+type loginKey struct {
+	key       string
+	threshold int
+}
+
+func (a *Auth) loginKeys(email string, ip string) []loginKey {
+	keys := []loginKey{{key: "account:" + strings.ToLower(email), threshold: a.cfg.Lockout.AccountThreshold}}
+
+	if ip != "" {
+		keys = append(keys, loginKey{key: "ip:" + ip, threshold: a.cfg.Lockout.IPThreshold})
+	}
+
+	return keys
+}
+
+// checkLockout returns a LockedError if the account or the IP is locked out.
+func (a *Auth) checkLockout(ctx context.Context, keys []loginKey) error {
+	names := make([]string, 0, len(keys))
+	for _, k := range keys {
+		names = append(names, k.key)
+	}
+
+	until, err := a.throttler.LoginLockedUntil(ctx, names)
+	if err != nil {
+		return err
+	}
+
+	if until.After(time.Now()) {
+		return &LockedError{Until: until}
+	}
+
+	return nil
+}
+
+// loginFailed counts the failure against every key and locks out
+// the keys that reached their threshold.
+func (a *Auth) loginFailed(ctx context.Context, log *slog.Logger, keys []loginKey) {
+	for _, k := range keys {
+		failures, err := a.throttler.RecordLoginFailure(ctx, k.key, a.cfg.Lockout.Window)
+		if err != nil {
+			log.Error("failed to record login failure", sl.Err(err))
+
+			continue
+		}
+
+		delay := a.cfg.Lockout.delay(failures, k.threshold)
+		if delay == 0 {
+			continue
+		}
+
+		if err := a.throttler.LockLogin(ctx, k.key, time.Now().Add(delay)); err != nil {
+			log.Error("failed to lock login", sl.Err(err))
+
+			continue
+		}
+
+		log.Warn("login locked",
+			slog.String("key", k.key),
+			slog.Int("failures", failures),
+			slog.Duration("delay", delay),
+		)
+	}
+}
+
+func (a *Auth) saveLoginEvent(ctx context.Context, log *slog.Logger, event models.LoginEvent) {
+	if err := a.throttler.SaveLoginEvent(ctx, event); err != nil {
+		log.Error("failed to save login event", sl.Err(err))
+	}
+}
@@ -0,0 +1,297 @@
+package authService_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeThrottler keeps failures and lockouts in memory the way the storage does,
+// expire lifts every lockout as if its time had passed.
+type fakeThrottler struct {
+	mu       sync.Mutex
+	failures map[string]int
+	locked   map[string]time.Time
+	events   []models.LoginEvent
+}
+
+func newFakeThrottler() *fakeThrottler {
+	return &fakeThrottler{
+		failures: make(map[string]int),
+		locked:   make(map[string]time.Time),
+	}
+}
+
+func (f *fakeThrottler) LoginLockedUntil(_ context.Context, keys []string) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var until time.Time
+	for _, key := range keys {
+		if f.locked[key].After(until) {
+			until = f.locked[key]
+		}
+	}
+
+	return until, nil
+}
+
+func (f *fakeThrottler) RecordLoginFailure(_ context.Context, key string, _ time.Duration) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failures[key]++
+
+	return f.failures[key], nil
+}
+
+func (f *fakeThrottler) LockLogin(_ context.Context, key string, until time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.locked[key] = until
+
+	return nil
+}
+
+func (f *fakeThrottler) ResetLoginFailures(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.failures, key)
+	delete(f.locked, key)
+
+	return nil
+}
+
+func (f *fakeThrottler) SaveLoginEvent(_ context.Context, event models.LoginEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.events = append(f.events, event)
+
+	return nil
+}
+
+func (f *fakeThrottler) expire() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key := range f.locked {
+		f.locked[key] = time.Now().Add(-time.Second)
+	}
+}
+
+// fakeUsers knows users by email, the stored hash of a password is the password itself.
+type fakeUsers map[string]models.User
+
+func (f fakeUsers) User(_ context.Context, email string) (models.User, error) {
+	user, ok := f[email]
+	if !ok {
+		return models.User{}, storage.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (f fakeUsers) UserById(_ context.Context, userId int64) (models.User, error) {
+	for _, user := range f {
+		if user.Id == userId {
+			return user, nil
+		}
+	}
+
+	return models.User{}, storage.ErrUserNotFound
+}
+
+type fakeHasher struct{}
+
+func (fakeHasher) Hash(password string) (string, error) {
+	return password, nil
+}
+
+func (fakeHasher) Verify(password string, encoded string) (bool, error) {
+	return password == encoded, nil
+}
+
+func (fakeHasher) NeedsRehash(string) bool {
+	return false
+}
+
+type fakeTokens struct {
+	authService.TokenSaver
+}
+
+func (fakeTokens) SaveRefreshToken(context.Context, models.RefreshToken) error {
+	return nil
+}
+
+const password = "correct horse battery staple"
+
+var lockoutUsers = fakeUsers{
+	"alice@mail.ru": {Id: 1, Email: "alice@mail.ru", PassHash: []byte(password), EmailVerified: true},
+	"bob@mail.ru":   {Id: 2, Email: "bob@mail.ru", PassHash: []byte(password), EmailVerified: true},
+}
+
+func newLockoutAuth(throttler *fakeThrottler, policy authService.LockoutPolicy) *authService.Auth {
+	return authService.New(
+		slogdiscard.NewDiscardLogger(),
+		nil,
+		lockoutUsers,
+		fakeTokens{},
+		nil,
+		nil,
+		nil,
+		throttler,
+		nil,
+		nil,
+		fakeHasher{},
+		nil,
+		jwt.NewHMAC("test-secret"),
+		nil,
+		authService.Config{
+			TokenTTL:   time.Minute,
+			RefreshTTL: time.Hour,
+			Lockout:    policy,
+		},
+	)
+}
+
+func TestLoginLockout(t *testing.T) {
+	policy := authService.LockoutPolicy{
+		AccountThreshold: 3,
+		IPThreshold:      5,
+		BaseDelay:        30 * time.Second,
+		MaxDelay:         15 * time.Minute,
+		Window:           time.Hour,
+	}
+
+	type attempt struct {
+		email    string
+		password string
+		ip       string
+		err      error
+	}
+
+	tests := []struct {
+		name     string
+		attempts []attempt
+	}{
+		{
+			name: "Account locked at threshold",
+			attempts: []attempt{
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", "wrong", "10.0.0.2", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", "wrong", "10.0.0.3", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", password, "10.0.0.4", authService.ErrTooManyAttempts},
+				{"bob@mail.ru", password, "10.0.0.4", nil},
+			},
+		},
+		{
+			name: "IP locked across accounts",
+			attempts: []attempt{
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"bob@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"carol@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"dave@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"erin@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"bob@mail.ru", password, "10.0.0.1", authService.ErrTooManyAttempts},
+				{"bob@mail.ru", password, "10.0.0.2", nil},
+			},
+		},
+		{
+			name: "Unknown email counts",
+			attempts: []attempt{
+				{"nobody@mail.ru", password, "10.0.0.1", authService.ErrInvalidCredentials},
+				{"nobody@mail.ru", password, "10.0.0.2", authService.ErrInvalidCredentials},
+				{"nobody@mail.ru", password, "10.0.0.3", authService.ErrInvalidCredentials},
+				{"nobody@mail.ru", password, "10.0.0.4", authService.ErrTooManyAttempts},
+			},
+		},
+		{
+			name: "Success forgives the account",
+			attempts: []attempt{
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", password, "10.0.0.1", nil},
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", password, "10.0.0.1", nil},
+			},
+		},
+		{
+			name: "Success does not forgive the IP",
+			attempts: []attempt{
+				{"bob@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"bob@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"bob@mail.ru", password, "10.0.0.1", nil},
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"carol@mail.ru", "wrong", "10.0.0.1", authService.ErrInvalidCredentials},
+				{"alice@mail.ru", password, "10.0.0.1", authService.ErrTooManyAttempts},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			auth := newLockoutAuth(newFakeThrottler(), policy)
+
+			for i, a := range tt.attempts {
+				_, err := auth.Login(context.Background(), a.email, a.password, a.ip)
+				if a.err == nil {
+					require.NoError(t, err, "attempt %d", i+1)
+					continue
+				}
+
+				require.ErrorIs(t, err, a.err, "attempt %d", i+1)
+			}
+		})
+	}
+}
+
+func TestLoginLockoutDelay(t *testing.T) {
+	policy := authService.LockoutPolicy{
+		AccountThreshold: 2,
+		BaseDelay:        30 * time.Second,
+		MaxDelay:         90 * time.Second,
+		Window:           time.Hour,
+	}
+
+	throttler := newFakeThrottler()
+	auth := newLockoutAuth(throttler, policy)
+
+	ctx := context.Background()
+
+	// every failure past the threshold locks out twice as long, up to the cap
+	delays := []time.Duration{0, 30 * time.Second, 60 * time.Second, 90 * time.Second, 90 * time.Second}
+
+	for i, delay := range delays {
+		_, err := auth.Login(ctx, "alice@mail.ru", "wrong", "")
+		require.ErrorIs(t, err, authService.ErrInvalidCredentials, "failure %d", i+1)
+
+		if delay == 0 {
+			// the right password before the threshold would forgive the failures
+			continue
+		}
+
+		_, err = auth.Login(ctx, "alice@mail.ru", password, "")
+
+		var lockedErr *authService.LockedError
+		require.True(t, errors.As(err, &lockedErr), "failure %d: %v", i+1, err)
+		require.WithinDuration(t, time.Now().Add(delay), lockedErr.Until, 5*time.Second, "failure %d", i+1)
+
+		throttler.expire()
+	}
+}
@@ -0,0 +1,123 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/token"
+)
+
+// ForgotPassword mails a single use password reset link to the user.
+// Unknown emails and failed sends are ignored, so the caller can not tell
+// whether an account exists.
+func (a *Auth) ForgotPassword(ctx context.Context, email string) error {
+	const op = "services.auth.ForgotPassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("password reset requested")
+
+	user, err := a.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return nil
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resetToken, err := token.Generate()
+	if err != nil {
+		log.Error("failed to generate reset token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = a.resetter.SavePasswordReset(ctx, user.Id, token.Hash(resetToken), time.Now().Add(a.cfg.PasswordResetTTL))
+	if err != nil {
+		log.Error("failed to save reset token", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	link := a.cfg.AppURL + "/reset-password?token=" + url.QueryEscape(resetToken)
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Someone asked to reset the password of your account.\n\n"+
+				"Follow the link to choose a new one, it is valid for %s:\n%s\n\n"+
+				"If it was not you, ignore this email.\n",
+			a.cfg.PasswordResetTTL, link,
+		),
+	}
+
+	// a failed send is not reported, otherwise the answer would tell
+	// existing accounts from unknown emails
+	if err := a.mailer.Send(ctx, msg); err != nil {
+		log.Error("failed to send reset email", sl.Err(err))
+
+		return nil
+	}
+
+	log.Info("password reset email sent", slog.Int64("user_id", user.Id))
+
+	return nil
+}
+
+// ResetPassword sets a new password using a reset token, every session
+// of the user is ended.
+func (a *Auth) ResetPassword(ctx context.Context, resetToken string, password string) error {
+	const op = "services.auth.ResetPassword"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("resetting password")
+
+	if err := a.policy.Check(password); err != nil {
+		log.Info("password rejected by policy", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := a.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	userId, err := a.resetter.ResetPassword(ctx, token.Hash(resetToken), []byte(passHash))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("invalid reset token", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidResetToken)
+		}
+
+		log.Error("failed to reset password", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset", slog.Int64("user_id", userId))
+
+	return nil
+}
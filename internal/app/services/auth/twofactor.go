@@ -0,0 +1,390 @@
+package authService
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"image/png"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/token"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+const (
+	totpPeriod = 30
+	totpDigits = otp.DigitsSix
+	// totpSkew is how many steps around the current one are accepted,
+	// it makes up for clock drift of the user's device.
+	totpSkew = 1
+
+	qrCodeSize        = 256
+	recoveryCodeCount = 10
+	// maxChallengeAttempts bounds guessing a code for a single challenge.
+	maxChallengeAttempts = 5
+)
+
+type TwoFactorStore interface {
+	TwoFactor(ctx context.Context, userId int64) (models.TwoFactor, error)
+	SaveTOTPSecret(ctx context.Context, userId int64, secret string) error
+	EnableTwoFactor(ctx context.Context, userId int64, step int64, codeHashes []string) error
+	DisableTwoFactor(ctx context.Context, userId int64) error
+	UseTOTPStep(ctx context.Context, userId int64, step int64) error
+	UseRecoveryCode(ctx context.Context, userId int64, codeHash string) error
+	SaveTwoFactorChallenge(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error
+	AttemptTwoFactorChallenge(ctx context.Context, tokenHash string, maxAttempts int) (models.User, error)
+	CompleteTwoFactorChallenge(ctx context.Context, tokenHash string) error
+}
+
+var (
+	ErrTwoFactorEnabled     = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorDisabled    = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotEnrolled = errors.New("two-factor authentication is not enrolled")
+	ErrInvalidCode          = errors.New("invalid two-factor code")
+	ErrInvalidChallenge     = errors.New("invalid or expired challenge")
+)
+
+// EnrollTwoFactor creates a new TOTP secret for the user after checking their
+// password, it has to be confirmed with a code before it is required on sign-in.
+func (a *Auth) EnrollTwoFactor(ctx context.Context, userId int64, password string) (models.Enrollment, error) {
+	const op = "services.auth.EnrollTwoFactor"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	log.Info("enrolling two-factor authentication")
+
+	user, err := a.reauthenticate(ctx, log, userId, password)
+	if err != nil {
+		return models.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      a.cfg.TOTPIssuer,
+		AccountName: user.Email,
+		Period:      totpPeriod,
+		Digits:      totpDigits,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		log.Error("failed to generate secret", sl.Err(err))
+
+		return models.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.twoFactor.SaveTOTPSecret(ctx, userId, key.Secret()); err != nil {
+		if errors.Is(err, storage.ErrTwoFactorOn) {
+			log.Warn("two-factor authentication is already enabled", sl.Err(err))
+
+			return models.Enrollment{}, fmt.Errorf("%s: %w", op, ErrTwoFactorEnabled)
+		}
+
+		log.Error("failed to save secret", sl.Err(err))
+
+		return models.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	img, err := key.Image(qrCodeSize, qrCodeSize)
+	if err != nil {
+		log.Error("failed to render qr code", sl.Err(err))
+
+		return models.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var qr bytes.Buffer
+	if err := png.Encode(&qr, img); err != nil {
+		log.Error("failed to encode qr code", sl.Err(err))
+
+		return models.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.Enrollment{
+		Secret: key.Secret(),
+		URI:    key.URL(),
+		QRCode: qr.Bytes(),
+	}, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication once the user proves
+// the authenticator app works and returns one-time recovery codes,
+// they are not stored in plain and can not be shown again.
+func (a *Auth) ConfirmTwoFactor(ctx context.Context, userId int64, code string) ([]string, error) {
+	const op = "services.auth.ConfirmTwoFactor"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	tf, err := a.twoFactor.TwoFactor(ctx, userId)
+	if err != nil {
+		log.Error("failed to get two-factor state", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tf.Enabled {
+		return nil, fmt.Errorf("%s: %w", op, ErrTwoFactorEnabled)
+	}
+
+	if tf.Secret == "" {
+		return nil, fmt.Errorf("%s: %w", op, ErrTwoFactorNotEnrolled)
+	}
+
+	step, ok := matchTOTP(tf.Secret, code, time.Now())
+	if !ok {
+		log.Info("invalid code")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidCode)
+	}
+
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			log.Error("failed to generate recovery code", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, token.Hash(normalizeRecoveryCode(code)))
+	}
+
+	if err := a.twoFactor.EnableTwoFactor(ctx, userId, step, hashes); err != nil {
+		if errors.Is(err, storage.ErrTwoFactorOn) {
+			return nil, fmt.Errorf("%s: %w", op, ErrTwoFactorEnabled)
+		}
+
+		log.Error("failed to enable two-factor authentication", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("two-factor authentication enabled")
+
+	return codes, nil
+}
+
+// DisableTwoFactor turns two-factor authentication off,
+// it takes the password of the user along with a TOTP or a recovery code.
+func (a *Auth) DisableTwoFactor(ctx context.Context, userId int64, password string, code string) error {
+	const op = "services.auth.DisableTwoFactor"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userId),
+	)
+
+	if _, err := a.reauthenticate(ctx, log, userId, password); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tf, err := a.twoFactor.TwoFactor(ctx, userId)
+	if err != nil {
+		log.Error("failed to get two-factor state", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !tf.Enabled {
+		return fmt.Errorf("%s: %w", op, ErrTwoFactorDisabled)
+	}
+
+	if err := a.checkCode(ctx, userId, tf, code); err != nil {
+		log.Info("invalid code", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.twoFactor.DisableTwoFactor(ctx, userId); err != nil {
+		log.Error("failed to disable two-factor authentication", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("two-factor authentication disabled")
+
+	return nil
+}
+
+// VerifyTwoFactor answers the challenge issued by Login with a TOTP
+// or a recovery code and starts a new session.
+func (a *Auth) VerifyTwoFactor(ctx context.Context, challengeToken string, code string) (models.TokenPair, error) {
+	const op = "services.auth.VerifyTwoFactor"
+
+	log := a.log.With(slog.String("op", op))
+
+	challengeHash := token.Hash(challengeToken)
+
+	user, err := a.twoFactor.AttemptTwoFactorChallenge(ctx, challengeHash, maxChallengeAttempts)
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("challenge not found", sl.Err(err))
+
+			return models.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to get challenge", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", user.Id))
+
+	tf, err := a.twoFactor.TwoFactor(ctx, user.Id)
+	if err != nil {
+		log.Error("failed to get two-factor state", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// wrong codes count against the account, so new challenges
+	// do not give unlimited guesses
+	keys := a.loginKeys(user.Email, "")
+
+	if err := a.checkCode(ctx, user.Id, tf, code); err != nil {
+		log.Info("invalid code", sl.Err(err))
+
+		if errors.Is(err, ErrInvalidCode) {
+			a.loginFailed(ctx, log, keys)
+		}
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.throttler.ResetLoginFailures(ctx, keys[0].key); err != nil {
+		log.Error("failed to reset login failures", sl.Err(err))
+	}
+
+	if err := a.twoFactor.CompleteTwoFactorChallenge(ctx, challengeHash); err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("challenge already answered", sl.Err(err))
+
+			return models.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to complete challenge", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pair, err := a.newSession(ctx, user)
+	if err != nil {
+		log.Error("failed to start session", sl.Err(err))
+
+		return models.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("user logged successfully")
+
+	return pair, nil
+}
+
+func (a *Auth) newChallenge(ctx context.Context, userId int64) (models.LoginResult, error) {
+	challengeToken, err := token.Generate()
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+
+	expiresAt := time.Now().Add(a.cfg.ChallengeTTL)
+
+	err = a.twoFactor.SaveTwoFactorChallenge(ctx, userId, token.Hash(challengeToken), expiresAt)
+	if err != nil {
+		return models.LoginResult{}, err
+	}
+
+	return models.LoginResult{
+		ChallengeToken:     challengeToken,
+		ChallengeExpiresAt: expiresAt,
+	}, nil
+}
+
+// checkCode accepts a TOTP code that was not used before or an unused
+// recovery code, the code is spent either way.
+func (a *Auth) checkCode(ctx context.Context, userId int64, tf models.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) == totpDigits.Length() {
+		step, ok := matchTOTP(tf.Secret, code, time.Now())
+		if !ok || step <= tf.LastStep {
+			return ErrInvalidCode
+		}
+
+		if err := a.twoFactor.UseTOTPStep(ctx, userId, step); err != nil {
+			if errors.Is(err, storage.ErrCodeReused) {
+				return ErrInvalidCode
+			}
+
+			return err
+		}
+
+		return nil
+	}
+
+	err := a.twoFactor.UseRecoveryCode(ctx, userId, token.Hash(normalizeRecoveryCode(code)))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			return ErrInvalidCode
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// matchTOTP returns the time step the code was generated for.
+func matchTOTP(secret string, code string, now time.Time) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    totpDigits,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	for skew := -totpSkew; skew <= totpSkew; skew++ {
+		at := now.Add(time.Duration(skew*totpPeriod) * time.Second)
+
+		want, err := totp.GenerateCodeCustom(secret, at, opts)
+		if err != nil {
+			return 0, false
+		}
+
+		if subtle.ConstantTimeCompare([]byte(want), []byte(code)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+
+	return 0, false
+}
+
+// newRecoveryCode returns a random code formatted like "abcde-fghij".
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+
+	return code[:5] + "-" + code[5:], nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,119 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/token"
+)
+
+// VerifyEmail marks the email the verification token was sent to verified,
+// a token sent on an email change makes it the email of the account.
+func (a *Auth) VerifyEmail(ctx context.Context, verifyToken string) error {
+	const op = "services.auth.VerifyEmail"
+
+	log := a.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("verifying email")
+
+	userId, err := a.verifier.VerifyEmail(ctx, token.Hash(verifyToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("invalid verification token", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrInvalidVerifyToken)
+		}
+		if errors.Is(err, storage.ErrUserExists) {
+			log.Warn("new email is already taken", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrUserExists)
+		}
+
+		log.Error("failed to verify email", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("email verified", slog.Int64("user_id", userId))
+
+	return nil
+}
+
+// ResendVerification mails a new verification link. Unknown and already
+// verified emails are ignored, so the caller can not tell whether an account exists.
+func (a *Auth) ResendVerification(ctx context.Context, email string) error {
+	const op = "services.auth.ResendVerification"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("verification email requested")
+
+	user, err := a.usrProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Warn("user not found", sl.Err(err))
+
+			return nil
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.EmailVerified {
+		log.Info("email is already verified")
+
+		return nil
+	}
+
+	if err := a.sendVerification(ctx, user); err != nil {
+		log.Error("failed to send verification email", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("verification email sent", slog.Int64("user_id", user.Id))
+
+	return nil
+}
+
+func (a *Auth) sendVerification(ctx context.Context, user models.User) error {
+	verifyToken, err := token.Generate()
+	if err != nil {
+		return err
+	}
+
+	err = a.verifier.SaveEmailVerification(ctx, user.Id, token.Hash(verifyToken), time.Now().Add(a.cfg.VerificationTTL))
+	if err != nil {
+		return err
+	}
+
+	link := a.cfg.AppURL + "/auth/verify?token=" + url.QueryEscape(verifyToken)
+
+	msg := mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Thanks for signing up.\n\n"+
+				"Follow the link to confirm your email address, it is valid for %s:\n%s\n\n"+
+				"If you did not create an account, ignore this email.\n",
+			a.cfg.VerificationTTL, link,
+		),
+	}
+
+	return a.mailer.Send(ctx, msg)
+}
@@ -0,0 +1,236 @@
+package commentsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/mention"
+)
+
+type Comment struct {
+	log       *slog.Logger
+	saver     CommentSaver
+	provider  CommentProvider
+	mentioner Mentioner
+	mailer    Mailer
+}
+
+type CommentSaver interface {
+	SaveComment(ctx context.Context, userId int64, itemId int64, parentId *int64, body string) (int64, error)
+	UpdateComment(ctx context.Context, userId int64, itemId int64, commentId int64, body string) error
+	DeleteComment(ctx context.Context, userId int64, itemId int64, commentId int64) error
+}
+
+type CommentProvider interface {
+	ItemComments(ctx context.Context, userId int64, itemId int64) ([]models.Comment, error)
+}
+
+type Mentioner interface {
+	SaveMentions(ctx context.Context, actorId int64, itemId int64, commentId int64, emails []string) ([]models.Mention, error)
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg mailer.Message) error
+}
+
+var (
+	ErrItemNotFound    = errors.New("item not found")
+	ErrCommentNotFound = errors.New("comment not found")
+)
+
+func New(
+	log *slog.Logger,
+	saver CommentSaver,
+	provider CommentProvider,
+	mentioner Mentioner,
+	mailer Mailer,
+) *Comment {
+	return &Comment{
+		log:       log,
+		saver:     saver,
+		provider:  provider,
+		mentioner: mentioner,
+		mailer:    mailer,
+	}
+}
+
+// Create leaves a comment on the item, or a reply to parentId, and notifies
+// collaborators mentioned in it as @email.
+func (c *Comment) Create(ctx context.Context, userId int64, itemId int64, parentId *int64, body string) (int64, error) {
+	const op = "services.comment.Create"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	commentId, err := c.saver.SaveComment(ctx, userId, itemId, parentId, body)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrCommentNotFound) {
+			log.Warn("parent comment not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrCommentNotFound)
+		}
+
+		log.Error("failed to save comment", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("comment saved", slog.Int64("comment_id", commentId))
+
+	c.notify(ctx, log, userId, itemId, commentId, body)
+
+	return commentId, nil
+}
+
+// Comments returns the comment threads of the item, replies are nested under their parents.
+func (c *Comment) Comments(ctx context.Context, userId int64, itemId int64) ([]models.Comment, error) {
+	const op = "services.comment.Comments"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	comments, err := c.provider.ItemComments(ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get comments", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return buildThreads(comments), nil
+}
+
+// buildThreads nests replies under their parents keeping their order.
+func buildThreads(comments []models.Comment) []models.Comment {
+	replies := make(map[int64][]models.Comment)
+	for _, comment := range comments {
+		if comment.ParentId != nil {
+			replies[*comment.ParentId] = append(replies[*comment.ParentId], comment)
+		}
+	}
+
+	var attach func(comment models.Comment) models.Comment
+	attach = func(comment models.Comment) models.Comment {
+		for _, reply := range replies[comment.Id] {
+			comment.Replies = append(comment.Replies, attach(reply))
+		}
+
+		return comment
+	}
+
+	threads := make([]models.Comment, 0, len(comments))
+	for _, comment := range comments {
+		if comment.ParentId == nil {
+			threads = append(threads, attach(comment))
+		}
+	}
+
+	return threads
+}
+
+// Update changes the body of a comment of the user, collaborators
+// mentioned for the first time are notified.
+func (c *Comment) Update(ctx context.Context, userId int64, itemId int64, commentId int64, body string) error {
+	const op = "services.comment.Update"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Int64("comment_id", commentId),
+	)
+
+	if err := c.saver.UpdateComment(ctx, userId, itemId, commentId, body); err != nil {
+		if errors.Is(err, storage.ErrCommentNotFound) {
+			log.Warn("comment not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrCommentNotFound)
+		}
+
+		log.Error("failed to update comment", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("comment updated")
+
+	c.notify(ctx, log, userId, itemId, commentId, body)
+
+	return nil
+}
+
+// Delete deletes a comment of the user with all replies to it.
+func (c *Comment) Delete(ctx context.Context, userId int64, itemId int64, commentId int64) error {
+	const op = "services.comment.Delete"
+
+	log := c.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Int64("comment_id", commentId),
+	)
+
+	if err := c.saver.DeleteComment(ctx, userId, itemId, commentId); err != nil {
+		if errors.Is(err, storage.ErrCommentNotFound) {
+			log.Warn("comment not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrCommentNotFound)
+		}
+
+		log.Error("failed to delete comment", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("comment deleted")
+
+	return nil
+}
+
+// notify records notifications for users mentioned in the comment and mails them.
+// The comment is saved already, so failures are only logged.
+func (c *Comment) notify(ctx context.Context, log *slog.Logger, userId int64, itemId int64, commentId int64, body string) {
+	emails := mention.Parse(body)
+	if len(emails) == 0 {
+		return
+	}
+
+	mentions, err := c.mentioner.SaveMentions(ctx, userId, itemId, commentId, emails)
+	if err != nil {
+		log.Error("failed to save mentions", sl.Err(err))
+
+		return
+	}
+
+	for _, m := range mentions {
+		msg := mailer.Message{
+			To:      m.Email,
+			Subject: "You were mentioned in a comment",
+			Body:    fmt.Sprintf("You were mentioned in a comment on item %d:\n\n%s\n", itemId, body),
+		}
+
+		if err := c.mailer.Send(ctx, msg); err != nil {
+			log.Error("failed to send mention", slog.Int64("user_id", m.UserId), sl.Err(err))
+		}
+	}
+
+	log.Info("mentions notified", slog.Int("count", len(mentions)))
+}
@@ -2,49 +2,128 @@ package itemsrv
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"time"
 
+	"github.com/Muaz717/todo-app/internal/app/storage"
 	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/cursor"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/rrule"
 )
 
 type Item struct {
 	log *slog.Logger
 	ItemSaver
 	ItemProvider
+	ItemUpdater
+	ItemDeleter
+	ItemTagger
+	TimezoneProvider
 }
 
 type ItemSaver interface {
 	SaveItem(
 		ctx context.Context,
 		userId int64,
-		title string,
-		description string,
+		input models.CreateItemInput,
 	) (int64, error)
 }
 
 type ItemProvider interface {
-	AllItems(ctx context.Context, userId int64) ([]models.Item, error)
+	AllItems(ctx context.Context, userId int64, filter models.ItemFilter) ([]models.Item, error)
+	Item(ctx context.Context, userId int64, itemId int64) (models.Item, error)
+	Subtasks(ctx context.Context, userId int64, itemId int64) ([]models.Item, error)
+	ItemDepth(ctx context.Context, userId int64, itemId int64) (int, error)
+	ItemEvents(ctx context.Context, userId int64, itemId int64) ([]models.ItemEvent, error)
+	TrashedItems(ctx context.Context, userId int64) ([]models.Item, error)
 }
 
+type ItemUpdater interface {
+	UpdateItem(
+		ctx context.Context,
+		userId int64,
+		itemId int64,
+		input models.UpdateItemInput,
+	) (int64, error)
+	SetItemDone(
+		ctx context.Context,
+		userId int64,
+		itemId int64,
+		done bool,
+		cascade bool,
+		version int64,
+	) error
+	CompleteOccurrence(
+		ctx context.Context,
+		userId int64,
+		itemId int64,
+		cascade bool,
+		nextDueAt *time.Time,
+		nextRRule string,
+		version int64,
+	) (int64, error)
+}
+
+type ItemDeleter interface {
+	DeleteItem(ctx context.Context, userId int64, itemId int64, version int64) error
+	RestoreItem(ctx context.Context, userId int64, itemId int64, version int64) error
+	PurgeItems(ctx context.Context, before time.Time) (int64, error)
+}
+
+type ItemTagger interface {
+	AttachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error
+	DetachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error
+}
+
+type TimezoneProvider interface {
+	UserTimezone(ctx context.Context, userId int64) (string, error)
+}
+
+// MaxItemDepth limits nesting of subtasks, top level items have depth 1.
+const MaxItemDepth = 3
+
+var (
+	ErrItemNotFound      = errors.New("item not found")
+	ErrParentNotFound    = errors.New("parent item not found")
+	ErrMaxDepthExceeded  = errors.New("max subtask depth exceeded")
+	ErrListNotFound      = errors.New("list not found")
+	ErrTagNotFound       = errors.New("tag not found")
+	ErrInvalidRRule      = errors.New("invalid recurrence rule")
+	ErrDueAtRequired     = errors.New("recurring item requires due date")
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+	ErrAssigneeNotMember = errors.New("assignee is not a workspace member")
+	ErrWorkspaceMismatch = errors.New("parent, list and workspace of the item differ")
+	ErrVersionMismatch   = errors.New("item has been modified")
+)
+
 func New(
 	log *slog.Logger,
 	itemSaver ItemSaver,
 	itemProvider ItemProvider,
+	itemUpdater ItemUpdater,
+	itemDeleter ItemDeleter,
+	itemTagger ItemTagger,
+	timezoneProvider TimezoneProvider,
 ) *Item {
 	return &Item{
-		log:          log,
-		ItemSaver:    itemSaver,
-		ItemProvider: itemProvider,
+		log:              log,
+		ItemSaver:        itemSaver,
+		ItemProvider:     itemProvider,
+		ItemUpdater:      itemUpdater,
+		ItemDeleter:      itemDeleter,
+		ItemTagger:       itemTagger,
+		TimezoneProvider: timezoneProvider,
 	}
 }
 
 func (i *Item) Create(
 	ctx context.Context,
 	userId int64,
-	title string,
-	description string,
+	input models.CreateItemInput,
 ) (int64, error) {
 	const op = "services.item.Create"
 
@@ -54,8 +133,69 @@ func (i *Item) Create(
 
 	log.Info("Creating item")
 
-	itemId, err := i.ItemSaver.SaveItem(ctx, userId, title, description)
+	if input.RRule != "" {
+		if _, err := rrule.Parse(input.RRule); err != nil {
+			log.Warn("invalid recurrence rule", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidRRule)
+		}
+
+		if input.DueAt == nil {
+			log.Warn("recurring item without due date")
+
+			return 0, fmt.Errorf("%s: %w", op, ErrDueAtRequired)
+		}
+	}
+
+	if input.ParentId != nil {
+		depth, err := i.ItemProvider.ItemDepth(ctx, userId, *input.ParentId)
+		if err != nil {
+			if errors.Is(err, storage.ErrItemNotFound) {
+				log.Warn("parent item not found", sl.Err(err))
+
+				return 0, fmt.Errorf("%s: %w", op, ErrParentNotFound)
+			}
+
+			log.Error("failed to get parent item depth", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if depth >= MaxItemDepth {
+			log.Warn("max subtask depth exceeded", slog.Int("depth", depth))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrMaxDepthExceeded)
+		}
+	}
+
+	itemId, err := i.ItemSaver.SaveItem(ctx, userId, input)
 	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("parent item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrParentNotFound)
+		}
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			log.Warn("workspace not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrWorkspaceNotFound)
+		}
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			log.Warn("assignee is not a workspace member", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrAssigneeNotMember)
+		}
+		if errors.Is(err, storage.ErrWorkspaceMismatch) {
+			log.Warn("parent, list and workspace differ", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrWorkspaceMismatch)
+		}
+
 		log.Error("failed to save item")
 
 		return 0, fmt.Errorf("%s: %w", op, err)
@@ -65,7 +205,9 @@ func (i *Item) Create(
 	return itemId, nil
 }
 
-func (i *Item) AllItems(ctx context.Context, userId int64) ([]models.Item, error) {
+// AllItems returns a page of user items matching the filter, NextCursor
+// of the page is set when there are more items behind it.
+func (i *Item) AllItems(ctx context.Context, userId int64, filter models.ItemFilter) (models.ItemPage, error) {
 	const op = "services.item.AllItems"
 
 	log := i.log.With(
@@ -74,14 +216,561 @@ func (i *Item) AllItems(ctx context.Context, userId int64) ([]models.Item, error
 
 	log.Info("Getting items")
 
-	items, err := i.ItemProvider.AllItems(ctx, userId)
+	limit := filter.Limit
+	if limit > 0 {
+		// one extra row tells whether the next page exists
+		filter.Limit = limit + 1
+	}
+
+	items, err := i.ItemProvider.AllItems(ctx, userId, filter)
 	if err != nil {
 		log.Error("failed to got items", sl.Err(err))
 
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return models.ItemPage{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	page := models.ItemPage{Items: items}
+
+	if limit > 0 && len(items) > limit {
+		page.Items = items[:limit]
+		page.NextCursor = cursor.Encode(itemCursor(filter, page.Items[limit-1]))
 	}
 
 	log.Info("Got items")
 
+	return page, nil
+}
+
+// itemCursor builds the keyset cursor pointing at item for the order of the filter.
+func itemCursor(filter models.ItemFilter, item models.Item) models.Cursor {
+	c := models.Cursor{Id: int64(item.Id), Sort: filter.Sort, Desc: filter.Desc}
+
+	switch filter.Sort {
+	case models.ItemSortDueAt:
+		c.Value = "infinity"
+		if item.DueAt != nil {
+			c.Value = item.DueAt.Format(time.RFC3339Nano)
+		}
+	case models.ItemSortPriority:
+		c.Value = strconv.Itoa(item.Priority)
+	case models.ItemSortTitle:
+		c.Value = item.Title
+	}
+
+	return c
+}
+
+func (i *Item) Item(ctx context.Context, userId int64, itemId int64) (models.Item, error) {
+	const op = "services.item.Item"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	log.Info("Getting item")
+
+	item, err := i.ItemProvider.Item(ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return models.Item{}, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get item", sl.Err(err))
+
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	subtasks, err := i.ItemProvider.Subtasks(ctx, userId, itemId)
+	if err != nil {
+		log.Error("failed to get subtasks", sl.Err(err))
+
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	item = buildTree(item, subtasks)
+
+	log.Info("Got item")
+
+	return item, nil
+}
+
+// buildTree nests descendants under the root item and fills progress
+// of every item that has subtasks. Descendants keep their relative order.
+func buildTree(root models.Item, descendants []models.Item) models.Item {
+	children := make(map[int64][]models.Item)
+	for _, d := range descendants {
+		if d.ParentId != nil {
+			children[*d.ParentId] = append(children[*d.ParentId], d)
+		}
+	}
+
+	var attach func(item models.Item) models.Item
+	attach = func(item models.Item) models.Item {
+		kids := children[int64(item.Id)]
+		if len(kids) == 0 {
+			return item
+		}
+
+		progress := models.Progress{Total: len(kids)}
+
+		item.Subtasks = make([]models.Item, 0, len(kids))
+		for _, kid := range kids {
+			if kid.Done {
+				progress.Done++
+			}
+			item.Subtasks = append(item.Subtasks, attach(kid))
+		}
+		item.Progress = &progress
+
+		return item
+	}
+
+	return attach(root)
+}
+
+// History returns changes made to the item, oldest first.
+func (i *Item) History(ctx context.Context, userId int64, itemId int64) ([]models.ItemEvent, error) {
+	const op = "services.item.History"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	log.Info("Getting item history")
+
+	events, err := i.ItemProvider.ItemEvents(ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get item history", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got item history")
+
+	return events, nil
+}
+
+// Update changes the item and returns its new version. A stale write, based on
+// a version the item has moved past, is rejected with ErrVersionMismatch.
+func (i *Item) Update(
+	ctx context.Context,
+	userId int64,
+	itemId int64,
+	input models.UpdateItemInput,
+) (int64, error) {
+	const op = "services.item.Update"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	log.Info("Updating item")
+
+	if input.RRule != nil && *input.RRule != "" {
+		if _, err := rrule.Parse(*input.RRule); err != nil {
+			log.Warn("invalid recurrence rule", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrInvalidRRule)
+		}
+	}
+
+	// a recurring item has to keep its due date, the rule is anchored to it
+	if input.DueAt == nil && (input.ClearDueAt || input.RRule != nil && *input.RRule != "") {
+		item, err := i.ItemProvider.Item(ctx, userId, itemId)
+		if err != nil {
+			if errors.Is(err, storage.ErrItemNotFound) {
+				log.Warn("item not found", sl.Err(err))
+
+				return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+			}
+
+			log.Error("failed to get item", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		rule := item.RRule
+		if input.RRule != nil {
+			rule = *input.RRule
+		}
+
+		if rule != "" && (input.ClearDueAt || item.DueAt == nil) {
+			log.Warn("recurring item without due date")
+
+			return 0, fmt.Errorf("%s: %w", op, ErrDueAtRequired)
+		}
+	}
+
+	version, err := i.ItemUpdater.UpdateItem(ctx, userId, itemId, input)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			log.Warn("assignee is not a workspace member", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrAssigneeNotMember)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to update item", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item updated", slog.Int64("version", version))
+
+	return version, nil
+}
+
+// Complete marks the item at version done, with cascade its subtasks are completed
+// as well. Completing a recurring item spawns its next occurrence, id of which is
+// returned. Zero version skips the check, stale ones are ErrVersionMismatch.
+func (i *Item) Complete(ctx context.Context, userId int64, itemId int64, cascade bool, version int64) (int64, error) {
+	const op = "services.item.Complete"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Bool("cascade", cascade),
+	)
+
+	item, err := i.ItemProvider.Item(ctx, userId, itemId)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get item", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if item.RRule == "" || item.DueAt == nil {
+		return 0, i.setDone(ctx, op, userId, itemId, true, cascade, version)
+	}
+
+	log.Info("Completing recurring item")
+
+	nextDueAt, nextRRule, err := i.nextOccurrence(ctx, item)
+	if err != nil {
+		log.Error("failed to get next occurrence", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	nextId, err := i.ItemUpdater.CompleteOccurrence(ctx, userId, itemId, cascade, nextDueAt, nextRRule, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to complete recurring item", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("recurring item completed", slog.Int64("next_id", nextId))
+
+	return nextId, nil
+}
+
+// nextOccurrence expands the rule of the item in the timezone of its owner, so the
+// series does not depend on who completes it. Nil due date is returned when the
+// series is over, the returned rule has COUNT decreased by the completed occurrence.
+func (i *Item) nextOccurrence(ctx context.Context, item models.Item) (*time.Time, string, error) {
+	rule, err := rrule.Parse(item.RRule)
+	if err != nil {
+		return nil, "", fmt.Errorf("%w: %w", ErrInvalidRRule, err)
+	}
+
+	timezone, err := i.TimezoneProvider.UserTimezone(ctx, item.UserId)
+	if err != nil {
+		return nil, "", err
+	}
+
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		i.log.Warn("unknown user timezone, falling back to UTC", slog.String("timezone", timezone))
+
+		loc = time.UTC
+	}
+
+	next, ok := rule.Next(*item.DueAt, loc)
+	if !ok {
+		return nil, "", nil
+	}
+
+	if rule.Count > 0 {
+		rule.Count--
+	}
+
+	return &next, rule.String(), nil
+}
+
+// Reopen marks the item at version not done, zero version skips the check.
+func (i *Item) Reopen(ctx context.Context, userId int64, itemId int64, version int64) error {
+	const op = "services.item.Reopen"
+
+	return i.setDone(ctx, op, userId, itemId, false, false, version)
+}
+
+func (i *Item) setDone(
+	ctx context.Context,
+	op string,
+	userId int64,
+	itemId int64,
+	done bool,
+	cascade bool,
+	version int64,
+) error {
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Bool("done", done),
+		slog.Bool("cascade", cascade),
+	)
+
+	log.Info("Changing item completion state")
+
+	err := i.ItemUpdater.SetItemDone(ctx, userId, itemId, done, cascade, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to change item completion state", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item completion state changed")
+
+	return nil
+}
+
+// Delete moves the item at version to the trash, zero version skips the check.
+func (i *Item) Delete(ctx context.Context, userId int64, itemId int64, version int64) error {
+	const op = "services.item.Delete"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	log.Info("Deleting item")
+
+	err := i.ItemDeleter.DeleteItem(ctx, userId, itemId, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to delete item", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item deleted")
+
+	return nil
+}
+
+// Trash returns deleted items the user can restore, recently deleted first.
+func (i *Item) Trash(ctx context.Context, userId int64) ([]models.Item, error) {
+	const op = "services.item.Trash"
+
+	log := i.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Getting trash")
+
+	items, err := i.ItemProvider.TrashedItems(ctx, userId)
+	if err != nil {
+		log.Error("failed to get trash", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got trash")
+
 	return items, nil
 }
+
+// Restore takes the item at version out of the trash along with the subtasks
+// deleted with it, zero version skips the check.
+func (i *Item) Restore(ctx context.Context, userId int64, itemId int64, version int64) error {
+	const op = "services.item.Restore"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	log.Info("Restoring item")
+
+	err := i.ItemDeleter.RestoreItem(ctx, userId, itemId, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to restore item", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item restored")
+
+	return nil
+}
+
+// Purge deletes for good items that were moved to the trash before the time.
+func (i *Item) Purge(ctx context.Context, before time.Time) (int64, error) {
+	const op = "services.item.Purge"
+
+	log := i.log.With(
+		slog.String("op", op),
+	)
+
+	purged, err := i.ItemDeleter.PurgeItems(ctx, before)
+	if err != nil {
+		log.Error("failed to purge trash", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if purged > 0 {
+		log.Info("trash purged", slog.Int64("count", purged))
+	}
+
+	return purged, nil
+}
+
+// AttachTag tags the item at version, zero version skips the check.
+func (i *Item) AttachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	const op = "services.item.AttachTag"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Int64("tag_id", tagId),
+	)
+
+	log.Info("Attaching tag")
+
+	err := i.ItemTagger.AttachTag(ctx, userId, itemId, tagId, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+		if errors.Is(err, storage.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTagNotFound)
+		}
+
+		log.Error("failed to attach tag", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tag attached")
+
+	return nil
+}
+
+// DetachTag removes the tag from the item at version, zero version skips the check.
+func (i *Item) DetachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	const op = "services.item.DetachTag"
+
+	log := i.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+		slog.Int64("tag_id", tagId),
+	)
+
+	log.Info("Detaching tag")
+
+	err := i.ItemTagger.DetachTag(ctx, userId, itemId, tagId, version)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+		if errors.Is(err, storage.ErrVersionMismatch) {
+			log.Warn("item was modified concurrently", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		}
+
+		log.Error("failed to detach tag", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tag detached")
+
+	return nil
+}
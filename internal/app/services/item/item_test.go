@@ -0,0 +1,888 @@
+package itemsrv_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	itemsrv "github.com/Muaz717/todo-app/internal/app/services/item"
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeStorage keeps items in memory in the order they were added, methods
+// the tests do not reach are left to the embedded nil interfaces. Changes
+// check the version of the item the way the storage does and move it on.
+type fakeStorage struct {
+	itemsrv.ItemSaver
+	itemsrv.ItemProvider
+	itemsrv.ItemUpdater
+	itemsrv.ItemDeleter
+	itemsrv.ItemTagger
+	itemsrv.TimezoneProvider
+
+	items    []models.Item
+	saved    []models.CreateItemInput
+	timezone string
+
+	updated   []models.UpdateItemInput
+	doneSet   bool
+	completed bool
+	nextDueAt *time.Time
+	nextRRule string
+
+	// clock moves a minute on with every deletion, so trees deleted
+	// one after another do not share deleted_at
+	clock time.Time
+}
+
+func (f *fakeStorage) SaveItem(_ context.Context, _ int64, input models.CreateItemInput) (int64, error) {
+	f.saved = append(f.saved, input)
+
+	return int64(len(f.items) + len(f.saved)), nil
+}
+
+func (f *fakeStorage) Item(_ context.Context, _ int64, itemId int64) (models.Item, error) {
+	for _, item := range f.items {
+		if int64(item.Id) == itemId && item.DeletedAt == nil {
+			return item, nil
+		}
+	}
+
+	return models.Item{}, storage.ErrItemNotFound
+}
+
+func (f *fakeStorage) Subtasks(_ context.Context, _ int64, itemId int64) ([]models.Item, error) {
+	inTree := map[int64]bool{itemId: true}
+
+	var subtasks []models.Item
+
+	// parents come before their subtasks, so a single pass finds every descendant
+	for _, item := range f.items {
+		if item.ParentId != nil && inTree[*item.ParentId] {
+			inTree[int64(item.Id)] = true
+			subtasks = append(subtasks, item)
+		}
+	}
+
+	return subtasks, nil
+}
+
+func (f *fakeStorage) ItemDepth(ctx context.Context, userId int64, itemId int64) (int, error) {
+	item, err := f.Item(ctx, userId, itemId)
+	if err != nil {
+		return 0, err
+	}
+
+	if item.ParentId == nil {
+		return 1, nil
+	}
+
+	depth, err := f.ItemDepth(ctx, userId, *item.ParentId)
+
+	return depth + 1, err
+}
+
+// bump moves the item on to its next version, zero version skips the check.
+func (f *fakeStorage) bump(itemId int64, version int64) (int64, error) {
+	for i, item := range f.items {
+		if int64(item.Id) != itemId {
+			continue
+		}
+
+		if version != 0 && version != item.Version {
+			return 0, storage.ErrVersionMismatch
+		}
+
+		f.items[i].Version++
+
+		return f.items[i].Version, nil
+	}
+
+	return 0, storage.ErrItemNotFound
+}
+
+func (f *fakeStorage) UpdateItem(_ context.Context, _ int64, itemId int64, input models.UpdateItemInput) (int64, error) {
+	version, err := f.bump(itemId, input.Version)
+	if err != nil {
+		return 0, err
+	}
+
+	f.updated = append(f.updated, input)
+
+	return version, nil
+}
+
+func (f *fakeStorage) SetItemDone(_ context.Context, _ int64, itemId int64, _ bool, _ bool, version int64) error {
+	if _, err := f.bump(itemId, version); err != nil {
+		return err
+	}
+
+	f.doneSet = true
+
+	return nil
+}
+
+func (f *fakeStorage) CompleteOccurrence(
+	_ context.Context,
+	_ int64,
+	itemId int64,
+	_ bool,
+	nextDueAt *time.Time,
+	nextRRule string,
+	version int64,
+) (int64, error) {
+	if _, err := f.bump(itemId, version); err != nil {
+		return 0, err
+	}
+
+	f.completed = true
+	f.nextDueAt = nextDueAt
+	f.nextRRule = nextRRule
+
+	if nextDueAt == nil {
+		return 0, nil
+	}
+
+	return 100, nil
+}
+
+// DeleteItem trashes the item with its subtasks still in place, the whole tree shares deleted_at.
+func (f *fakeStorage) DeleteItem(ctx context.Context, userId int64, itemId int64, version int64) error {
+	if _, err := f.Item(ctx, userId, itemId); err != nil {
+		return err
+	}
+
+	if _, err := f.bump(itemId, version); err != nil {
+		return err
+	}
+
+	f.clock = f.clock.Add(time.Minute)
+	deletedAt := f.clock
+
+	inTree := map[int64]bool{}
+
+	for i, item := range f.items {
+		id := int64(item.Id)
+
+		switch {
+		case id == itemId && item.DeletedAt == nil:
+		case item.ParentId != nil && inTree[*item.ParentId] && item.DeletedAt == nil:
+		default:
+			continue
+		}
+
+		inTree[id] = true
+		f.items[i].DeletedAt = &deletedAt
+	}
+
+	if len(inTree) == 0 {
+		return storage.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// inTrash reports whether the item was deleted on its own rather than along with its parent.
+func (f *fakeStorage) inTrash(item models.Item) bool {
+	if item.DeletedAt == nil {
+		return false
+	}
+
+	for _, parent := range f.items {
+		if item.ParentId != nil && int64(parent.Id) == *item.ParentId && parent.DeletedAt != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (f *fakeStorage) TrashedItems(_ context.Context, _ int64) ([]models.Item, error) {
+	var trashed []models.Item
+
+	for _, item := range f.items {
+		if f.inTrash(item) {
+			trashed = append(trashed, item)
+		}
+	}
+
+	slices.SortStableFunc(trashed, func(a, b models.Item) int {
+		return b.DeletedAt.Compare(*a.DeletedAt)
+	})
+
+	return trashed, nil
+}
+
+// RestoreItem takes the item out of the trash with the subtasks deleted along with it.
+func (f *fakeStorage) RestoreItem(_ context.Context, _ int64, itemId int64, version int64) error {
+	if !slices.ContainsFunc(f.items, func(item models.Item) bool {
+		return int64(item.Id) == itemId && f.inTrash(item)
+	}) {
+		return storage.ErrItemNotFound
+	}
+
+	if _, err := f.bump(itemId, version); err != nil {
+		return err
+	}
+
+	inTree := map[int64]time.Time{}
+
+	for i, item := range f.items {
+		id := int64(item.Id)
+
+		switch {
+		case id == itemId && f.inTrash(item):
+		case item.ParentId != nil && item.DeletedAt != nil && inTree[*item.ParentId].Equal(*item.DeletedAt):
+		default:
+			continue
+		}
+
+		inTree[id] = *item.DeletedAt
+		f.items[i].DeletedAt = nil
+	}
+
+	if len(inTree) == 0 {
+		return storage.ErrItemNotFound
+	}
+
+	return nil
+}
+
+func (f *fakeStorage) PurgeItems(_ context.Context, before time.Time) (int64, error) {
+	kept := f.items[:0]
+
+	for _, item := range f.items {
+		if item.DeletedAt == nil || !item.DeletedAt.Before(before) {
+			kept = append(kept, item)
+		}
+	}
+
+	purged := int64(len(f.items) - len(kept))
+	f.items = kept
+
+	return purged, nil
+}
+
+func (f *fakeStorage) AttachTag(_ context.Context, _ int64, itemId int64, _ int64, version int64) error {
+	_, err := f.bump(itemId, version)
+
+	return err
+}
+
+func (f *fakeStorage) UserTimezone(_ context.Context, _ int64) (string, error) {
+	return f.timezone, nil
+}
+
+func newItemService(f *fakeStorage) *itemsrv.Item {
+	return itemsrv.New(slogdiscard.NewDiscardLogger(), f, f, f, f, f, f)
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestItemTree(t *testing.T) {
+	items := []models.Item{
+		{Id: 1, Title: "root"},
+		{Id: 2, Title: "first", ParentId: ptr[int64](1), Done: true},
+		{Id: 3, Title: "second", ParentId: ptr[int64](1)},
+		{Id: 4, Title: "third", ParentId: ptr[int64](1), Done: true},
+		{Id: 5, Title: "nested done", ParentId: ptr[int64](3), Done: true},
+		{Id: 6, Title: "nested open", ParentId: ptr[int64](3)},
+		{Id: 7, Title: "other root"},
+		{Id: 8, Title: "deepest", ParentId: ptr[int64](6)},
+	}
+
+	tests := []struct {
+		name     string
+		itemId   int64
+		subtasks []int
+		progress *models.Progress
+		err      error
+	}{
+		{
+			name:     "Root",
+			itemId:   1,
+			subtasks: []int{2, 3, 4},
+			progress: &models.Progress{Done: 2, Total: 3},
+		},
+		{
+			name:     "Subtask",
+			itemId:   3,
+			subtasks: []int{5, 6},
+			progress: &models.Progress{Done: 1, Total: 2},
+		},
+		{
+			name:   "Without subtasks",
+			itemId: 7,
+		},
+		{
+			name:   "Not found",
+			itemId: 9,
+			err:    itemsrv.ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			item, err := newItemService(&fakeStorage{items: items}).Item(context.Background(), 1, tt.itemId)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+
+			var subtasks []int
+			for _, s := range item.Subtasks {
+				subtasks = append(subtasks, s.Id)
+			}
+
+			require.Equal(t, tt.subtasks, subtasks)
+			require.Equal(t, tt.progress, item.Progress)
+		})
+	}
+}
+
+func TestItemTreeNesting(t *testing.T) {
+	items := []models.Item{
+		{Id: 1, Title: "root"},
+		{Id: 2, Title: "child", ParentId: ptr[int64](1)},
+		{Id: 3, Title: "grandchild", ParentId: ptr[int64](2), Done: true},
+		{Id: 4, Title: "leaf", ParentId: ptr[int64](1)},
+	}
+
+	item, err := newItemService(&fakeStorage{items: items}).Item(context.Background(), 1, 1)
+	require.NoError(t, err)
+
+	require.Len(t, item.Subtasks, 2)
+
+	child := item.Subtasks[0]
+	require.Equal(t, &models.Progress{Done: 1, Total: 1}, child.Progress)
+	require.Len(t, child.Subtasks, 1)
+	require.Equal(t, 3, child.Subtasks[0].Id)
+	require.Nil(t, child.Subtasks[0].Progress)
+
+	leaf := item.Subtasks[1]
+	require.Nil(t, leaf.Progress)
+	require.Empty(t, leaf.Subtasks)
+}
+
+func TestCreateSubtaskDepth(t *testing.T) {
+	items := []models.Item{
+		{Id: 1, Title: "depth 1"},
+		{Id: 2, Title: "depth 2", ParentId: ptr[int64](1)},
+		{Id: 3, Title: "depth 3", ParentId: ptr[int64](2)},
+	}
+
+	tests := []struct {
+		name     string
+		parentId *int64
+		err      error
+	}{
+		{
+			name: "Top level",
+		},
+		{
+			name:     "Under top level",
+			parentId: ptr[int64](1),
+		},
+		{
+			name:     "At max depth",
+			parentId: ptr[int64](2),
+		},
+		{
+			name:     "Beyond max depth",
+			parentId: ptr[int64](3),
+			err:      itemsrv.ErrMaxDepthExceeded,
+		},
+		{
+			name:     "Parent not found",
+			parentId: ptr[int64](9),
+			err:      itemsrv.ErrParentNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeStorage{items: items}
+
+			_, err := newItemService(f).Create(context.Background(), 1, models.CreateItemInput{
+				Title:    "subtask",
+				ParentId: tt.parentId,
+			})
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.Empty(t, f.saved)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, f.saved, 1)
+		})
+	}
+}
+
+func TestCompleteRecurring(t *testing.T) {
+	dueAt := time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		rrule     string
+		timezone  string
+		nextDueAt *time.Time
+		nextRRule string
+	}{
+		{
+			name:      "Daily",
+			rrule:     "FREQ=DAILY",
+			timezone:  "UTC",
+			nextDueAt: ptr(time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)),
+			nextRRule: "FREQ=DAILY",
+		},
+		{
+			name:      "Count left",
+			rrule:     "FREQ=DAILY;COUNT=3",
+			timezone:  "UTC",
+			nextDueAt: ptr(time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)),
+			nextRRule: "FREQ=DAILY;COUNT=2",
+		},
+		{
+			name:     "Last of count",
+			rrule:    "FREQ=DAILY;COUNT=1",
+			timezone: "UTC",
+		},
+		{
+			name:      "Before until",
+			rrule:     "FREQ=DAILY;UNTIL=20240405T000000Z",
+			timezone:  "UTC",
+			nextDueAt: ptr(time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)),
+			nextRRule: "FREQ=DAILY;UNTIL=20240405T000000Z",
+		},
+		{
+			name:     "Past until",
+			rrule:    "FREQ=DAILY;UNTIL=20240330T235959Z",
+			timezone: "UTC",
+		},
+		{
+			// 09:00 in Berlin stays 09:00 after the switch to summer time
+			name:      "Owner timezone",
+			rrule:     "FREQ=DAILY",
+			timezone:  "Europe/Berlin",
+			nextDueAt: ptr(time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC)),
+			nextRRule: "FREQ=DAILY",
+		},
+		{
+			name:      "Unknown timezone",
+			rrule:     "FREQ=DAILY",
+			timezone:  "Mars/Olympus_Mons",
+			nextDueAt: ptr(time.Date(2024, 3, 31, 8, 0, 0, 0, time.UTC)),
+			nextRRule: "FREQ=DAILY",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeStorage{
+				items:    []models.Item{{Id: 1, Title: "recurring", DueAt: &dueAt, RRule: tt.rrule, UserId: 1}},
+				timezone: tt.timezone,
+			}
+
+			nextId, err := newItemService(f).Complete(context.Background(), 2, 1, false, 0)
+			require.NoError(t, err)
+
+			require.True(t, f.completed)
+			require.False(t, f.doneSet)
+			require.Equal(t, tt.nextRRule, f.nextRRule)
+
+			if tt.nextDueAt == nil {
+				require.Nil(t, f.nextDueAt)
+				require.Zero(t, nextId)
+				return
+			}
+
+			require.NotNil(t, f.nextDueAt)
+			require.True(t, tt.nextDueAt.Equal(*f.nextDueAt), "next due at %s, want %s", f.nextDueAt, tt.nextDueAt)
+			require.Equal(t, int64(100), nextId)
+		})
+	}
+}
+
+func TestCompleteNotRecurring(t *testing.T) {
+	dueAt := time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		item models.Item
+	}{
+		{
+			name: "Without rule",
+			item: models.Item{Id: 1, Title: "once", DueAt: &dueAt},
+		},
+		{
+			name: "Without due date",
+			item: models.Item{Id: 1, Title: "no date", RRule: "FREQ=DAILY"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeStorage{items: []models.Item{tt.item}}
+
+			nextId, err := newItemService(f).Complete(context.Background(), 1, 1, false, 0)
+			require.NoError(t, err)
+
+			require.Zero(t, nextId)
+			require.True(t, f.doneSet)
+			require.False(t, f.completed)
+		})
+	}
+}
+
+func TestUpdateDueDateRule(t *testing.T) {
+	dueAt := time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC)
+
+	items := []models.Item{
+		{Id: 1, Title: "recurring", DueAt: &dueAt, RRule: "FREQ=DAILY", Version: 1},
+		{Id: 2, Title: "dated", DueAt: &dueAt, Version: 1},
+		{Id: 3, Title: "undated", Version: 1},
+	}
+
+	tests := []struct {
+		name   string
+		itemId int64
+		input  models.UpdateItemInput
+		err    error
+	}{
+		{
+			name:   "Clear due date of recurring",
+			itemId: 1,
+			input:  models.UpdateItemInput{ClearDueAt: true},
+			err:    itemsrv.ErrDueAtRequired,
+		},
+		{
+			name:   "Clear due date and rule",
+			itemId: 1,
+			input:  models.UpdateItemInput{ClearDueAt: true, RRule: ptr("")},
+		},
+		{
+			name:   "Move due date of recurring",
+			itemId: 1,
+			input:  models.UpdateItemInput{DueAt: ptr(dueAt.Add(time.Hour))},
+		},
+		{
+			name:   "Clear due date",
+			itemId: 2,
+			input:  models.UpdateItemInput{ClearDueAt: true},
+		},
+		{
+			name:   "Add rule to dated",
+			itemId: 2,
+			input:  models.UpdateItemInput{RRule: ptr("FREQ=WEEKLY")},
+		},
+		{
+			name:   "Add rule and clear due date",
+			itemId: 2,
+			input:  models.UpdateItemInput{RRule: ptr("FREQ=WEEKLY"), ClearDueAt: true},
+			err:    itemsrv.ErrDueAtRequired,
+		},
+		{
+			name:   "Add rule to undated",
+			itemId: 3,
+			input:  models.UpdateItemInput{RRule: ptr("FREQ=WEEKLY")},
+			err:    itemsrv.ErrDueAtRequired,
+		},
+		{
+			name:   "Add rule with due date",
+			itemId: 3,
+			input:  models.UpdateItemInput{RRule: ptr("FREQ=WEEKLY"), DueAt: &dueAt},
+		},
+		{
+			name:   "Invalid rule",
+			itemId: 2,
+			input:  models.UpdateItemInput{RRule: ptr("FREQ=HOURLY")},
+			err:    itemsrv.ErrInvalidRRule,
+		},
+		{
+			name:   "Not found",
+			itemId: 9,
+			input:  models.UpdateItemInput{ClearDueAt: true},
+			err:    itemsrv.ErrItemNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeStorage{items: slices.Clone(items)}
+
+			version, err := newItemService(f).Update(context.Background(), 1, tt.itemId, tt.input)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				require.Empty(t, f.updated)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, int64(2), version)
+			require.Equal(t, []models.UpdateItemInput{tt.input}, f.updated)
+		})
+	}
+}
+
+func TestTrash(t *testing.T) {
+	items := []models.Item{
+		{Id: 1, Title: "root"},
+		{Id: 2, Title: "child", ParentId: ptr[int64](1)},
+		{Id: 3, Title: "grandchild", ParentId: ptr[int64](2)},
+		{Id: 4, Title: "other root"},
+	}
+
+	type step struct {
+		restore bool
+		itemId  int64
+		err     error
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+		trash []int
+		live  []int
+	}{
+		{
+			name:  "Delete takes subtasks along",
+			steps: []step{{itemId: 1}},
+			trash: []int{1},
+			live:  []int{4},
+		},
+		{
+			name:  "Restore brings subtasks back",
+			steps: []step{{itemId: 1}, {restore: true, itemId: 1}},
+			live:  []int{1, 2, 3, 4},
+		},
+		{
+			name:  "Recently deleted first",
+			steps: []step{{itemId: 4}, {itemId: 2}},
+			trash: []int{2, 4},
+			live:  []int{1},
+		},
+		{
+			name:  "Subtask deleted earlier stays in trash",
+			steps: []step{{itemId: 3}, {itemId: 1}, {restore: true, itemId: 1}},
+			trash: []int{3},
+			live:  []int{1, 2, 4},
+		},
+		{
+			name:  "Subtask deleted with its parent",
+			steps: []step{{itemId: 1}, {restore: true, itemId: 2, err: itemsrv.ErrItemNotFound}},
+			trash: []int{1},
+			live:  []int{4},
+		},
+		{
+			name:  "Delete twice",
+			steps: []step{{itemId: 4}, {itemId: 4, err: itemsrv.ErrItemNotFound}},
+			trash: []int{4},
+			live:  []int{1, 2, 3},
+		},
+		{
+			name:  "Restore not deleted",
+			steps: []step{{restore: true, itemId: 4, err: itemsrv.ErrItemNotFound}},
+			live:  []int{1, 2, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			f := &fakeStorage{items: slices.Clone(items)}
+			item := newItemService(f)
+
+			for i, s := range tt.steps {
+				var err error
+				if s.restore {
+					err = item.Restore(context.Background(), 1, s.itemId, 0)
+				} else {
+					err = item.Delete(context.Background(), 1, s.itemId, 0)
+				}
+
+				if s.err != nil {
+					require.ErrorIs(t, err, s.err, "step %d", i+1)
+					continue
+				}
+				require.NoError(t, err, "step %d", i+1)
+			}
+
+			trashed, err := item.Trash(context.Background(), 1)
+			require.NoError(t, err)
+
+			var trash []int
+			for _, it := range trashed {
+				trash = append(trash, it.Id)
+			}
+
+			var live []int
+			for _, it := range f.items {
+				if it.DeletedAt == nil {
+					live = append(live, it.Id)
+				}
+			}
+
+			require.Equal(t, tt.trash, trash)
+			require.Equal(t, tt.live, live)
+		})
+	}
+}
+
+func TestPurge(t *testing.T) {
+	f := &fakeStorage{items: []models.Item{
+		{Id: 1, Title: "root"},
+		{Id: 2, Title: "child", ParentId: ptr[int64](1)},
+		{Id: 3, Title: "other root"},
+		{Id: 4, Title: "kept"},
+	}}
+	item := newItemService(f)
+
+	require.NoError(t, item.Delete(context.Background(), 1, 1, 0))
+	require.NoError(t, item.Delete(context.Background(), 1, 3, 0))
+
+	// the first tree went to the trash a minute before the second one
+	purged, err := item.Purge(context.Background(), f.clock.Add(-time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(2), purged)
+
+	trashed, err := item.Trash(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	require.Equal(t, 3, trashed[0].Id)
+
+	purged, err = item.Purge(context.Background(), f.clock.Add(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), purged)
+
+	require.Len(t, f.items, 1)
+	require.Equal(t, 4, f.items[0].Id)
+}
+
+func TestVersionMismatch(t *testing.T) {
+	dueAt := time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC)
+	deletedAt := time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC)
+
+	items := []models.Item{
+		{Id: 1, Title: "once", Version: 3},
+		{Id: 2, Title: "recurring", DueAt: &dueAt, RRule: "FREQ=DAILY", UserId: 1, Version: 3},
+		{Id: 3, Title: "trashed", DeletedAt: &deletedAt, Version: 3},
+	}
+
+	ops := []struct {
+		name   string
+		itemId int64
+		call   func(item *itemsrv.Item, itemId int64, version int64) error
+	}{
+		{
+			name:   "Update",
+			itemId: 1,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				_, err := item.Update(context.Background(), 1, itemId, models.UpdateItemInput{Title: ptr("renamed"), Version: version})
+				return err
+			},
+		},
+		{
+			name:   "Complete",
+			itemId: 1,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				_, err := item.Complete(context.Background(), 1, itemId, false, version)
+				return err
+			},
+		},
+		{
+			name:   "Complete recurring",
+			itemId: 2,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				_, err := item.Complete(context.Background(), 1, itemId, false, version)
+				return err
+			},
+		},
+		{
+			name:   "Reopen",
+			itemId: 1,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				return item.Reopen(context.Background(), 1, itemId, version)
+			},
+		},
+		{
+			name:   "Delete",
+			itemId: 1,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				return item.Delete(context.Background(), 1, itemId, version)
+			},
+		},
+		{
+			name:   "Restore",
+			itemId: 3,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				return item.Restore(context.Background(), 1, itemId, version)
+			},
+		},
+		{
+			name:   "AttachTag",
+			itemId: 1,
+			call: func(item *itemsrv.Item, itemId int64, version int64) error {
+				return item.AttachTag(context.Background(), 1, itemId, 1, version)
+			},
+		},
+	}
+
+	versions := []struct {
+		name    string
+		version int64
+		err     error
+	}{
+		{name: "current", version: 3},
+		{name: "unchecked", version: 0},
+		{name: "stale", version: 2, err: itemsrv.ErrVersionMismatch},
+		{name: "ahead", version: 4, err: itemsrv.ErrVersionMismatch},
+	}
+
+	for _, op := range ops {
+		for _, v := range versions {
+			t.Run(op.name+" "+v.name, func(t *testing.T) {
+				t.Parallel()
+
+				f := &fakeStorage{items: slices.Clone(items), timezone: "UTC"}
+
+				err := op.call(newItemService(f), op.itemId, v.version)
+
+				i := slices.IndexFunc(f.items, func(item models.Item) bool {
+					return int64(item.Id) == op.itemId
+				})
+				got := f.items[i]
+
+				if v.err != nil {
+					require.ErrorIs(t, err, v.err)
+					require.Equal(t, items[i], got)
+					require.Empty(t, f.updated)
+					require.False(t, f.doneSet)
+					require.False(t, f.completed)
+					return
+				}
+
+				require.NoError(t, err)
+				require.Equal(t, int64(4), got.Version)
+			})
+		}
+	}
+}
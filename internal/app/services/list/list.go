@@ -0,0 +1,242 @@
+package listsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type List struct {
+	log *slog.Logger
+	ListSaver
+	ListProvider
+	ListUpdater
+	ListDeleter
+	ListItemsProvider
+}
+
+type ListSaver interface {
+	SaveList(ctx context.Context, userId int64, input models.CreateListInput) (int64, error)
+}
+
+type ListProvider interface {
+	AllLists(ctx context.Context, userId int64, withArchived bool) ([]models.List, error)
+	List(ctx context.Context, userId int64, listId int64) (models.List, error)
+}
+
+type ListUpdater interface {
+	UpdateList(
+		ctx context.Context,
+		userId int64,
+		listId int64,
+		input models.UpdateListInput,
+	) error
+}
+
+type ListDeleter interface {
+	DeleteList(ctx context.Context, userId int64, listId int64) error
+}
+
+type ListItemsProvider interface {
+	ItemsByList(ctx context.Context, userId int64, listId int64) ([]models.Item, error)
+}
+
+var (
+	ErrListNotFound      = errors.New("list not found")
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+)
+
+func New(
+	log *slog.Logger,
+	listSaver ListSaver,
+	listProvider ListProvider,
+	listUpdater ListUpdater,
+	listDeleter ListDeleter,
+	listItemsProvider ListItemsProvider,
+) *List {
+	return &List{
+		log:               log,
+		ListSaver:         listSaver,
+		ListProvider:      listProvider,
+		ListUpdater:       listUpdater,
+		ListDeleter:       listDeleter,
+		ListItemsProvider: listItemsProvider,
+	}
+}
+
+func (l *List) Create(ctx context.Context, userId int64, input models.CreateListInput) (int64, error) {
+	const op = "services.list.Create"
+
+	log := l.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Creating list")
+
+	listId, err := l.ListSaver.SaveList(ctx, userId, input)
+	if err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			log.Warn("workspace not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrWorkspaceNotFound)
+		}
+
+		log.Error("failed to save list", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("list saved", slog.Int64("id", listId))
+
+	return listId, nil
+}
+
+func (l *List) AllLists(ctx context.Context, userId int64, withArchived bool) ([]models.List, error) {
+	const op = "services.list.AllLists"
+
+	log := l.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Getting lists")
+
+	lists, err := l.ListProvider.AllLists(ctx, userId, withArchived)
+	if err != nil {
+		log.Error("failed to get lists", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got lists")
+
+	return lists, nil
+}
+
+func (l *List) List(ctx context.Context, userId int64, listId int64) (models.List, error) {
+	const op = "services.list.List"
+
+	log := l.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	log.Info("Getting list")
+
+	list, err := l.ListProvider.List(ctx, userId, listId)
+	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return models.List{}, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to get list", sl.Err(err))
+
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got list")
+
+	return list, nil
+}
+
+func (l *List) Update(
+	ctx context.Context,
+	userId int64,
+	listId int64,
+	input models.UpdateListInput,
+) error {
+	const op = "services.list.Update"
+
+	log := l.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	log.Info("Updating list")
+
+	err := l.ListUpdater.UpdateList(ctx, userId, listId, input)
+	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to update list", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("list updated")
+
+	return nil
+}
+
+func (l *List) Delete(ctx context.Context, userId int64, listId int64) error {
+	const op = "services.list.Delete"
+
+	log := l.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	log.Info("Deleting list")
+
+	err := l.ListDeleter.DeleteList(ctx, userId, listId)
+	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to delete list", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("list deleted")
+
+	return nil
+}
+
+// Items returns items of the list, ErrListNotFound is returned for lists of other users.
+func (l *List) Items(ctx context.Context, userId int64, listId int64) ([]models.Item, error) {
+	const op = "services.list.Items"
+
+	log := l.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	log.Info("Getting list items")
+
+	if _, err := l.ListProvider.List(ctx, userId, listId); err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to get list", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := l.ListItemsProvider.ItemsByList(ctx, userId, listId)
+	if err != nil {
+		log.Error("failed to get list items", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got list items")
+
+	return items, nil
+}
@@ -0,0 +1,100 @@
+package notificationsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Notification struct {
+	log      *slog.Logger
+	provider NotificationProvider
+	reader   NotificationReader
+}
+
+type NotificationProvider interface {
+	Notifications(ctx context.Context, userId int64, unread bool) ([]models.Notification, error)
+}
+
+type NotificationReader interface {
+	ReadNotification(ctx context.Context, userId int64, notificationId int64) error
+	ReadAllNotifications(ctx context.Context, userId int64) error
+}
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+)
+
+func New(
+	log *slog.Logger,
+	provider NotificationProvider,
+	reader NotificationReader,
+) *Notification {
+	return &Notification{
+		log:      log,
+		provider: provider,
+		reader:   reader,
+	}
+}
+
+// Notifications returns notifications of the user, with unread set only unread ones.
+func (n *Notification) Notifications(ctx context.Context, userId int64, unread bool) ([]models.Notification, error) {
+	const op = "services.notification.Notifications"
+
+	log := n.log.With(
+		slog.String("op", op),
+	)
+
+	notifications, err := n.provider.Notifications(ctx, userId, unread)
+	if err != nil {
+		log.Error("failed to get notifications", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+func (n *Notification) Read(ctx context.Context, userId int64, notificationId int64) error {
+	const op = "services.notification.Read"
+
+	log := n.log.With(
+		slog.String("op", op),
+		slog.Int64("notification_id", notificationId),
+	)
+
+	if err := n.reader.ReadNotification(ctx, userId, notificationId); err != nil {
+		if errors.Is(err, storage.ErrNotificationNotFound) {
+			log.Warn("notification not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrNotificationNotFound)
+		}
+
+		log.Error("failed to read notification", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (n *Notification) ReadAll(ctx context.Context, userId int64) error {
+	const op = "services.notification.ReadAll"
+
+	log := n.log.With(
+		slog.String("op", op),
+	)
+
+	if err := n.reader.ReadAllNotifications(ctx, userId); err != nil {
+		log.Error("failed to read notifications", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
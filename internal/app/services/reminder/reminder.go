@@ -0,0 +1,76 @@
+package remindersrv
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Reminder struct {
+	log *slog.Logger
+	ReminderProcessor
+	notifier  Notifier
+	batchSize int
+}
+
+type ReminderProcessor interface {
+	ProcessReminders(
+		ctx context.Context,
+		now time.Time,
+		limit int,
+		notify func(ctx context.Context, reminder models.Reminder) error,
+	) (int, error)
+}
+
+// Notifier delivers a reminder to the owner of the item.
+type Notifier interface {
+	Notify(ctx context.Context, reminder models.Reminder) error
+}
+
+func New(
+	log *slog.Logger,
+	reminderProcessor ReminderProcessor,
+	notifier Notifier,
+	batchSize int,
+) *Reminder {
+	return &Reminder{
+		log:               log,
+		ReminderProcessor: reminderProcessor,
+		notifier:          notifier,
+		batchSize:         batchSize,
+	}
+}
+
+// Dispatch delivers a batch of due reminders and returns how many were sent.
+func (r *Reminder) Dispatch(ctx context.Context) (int, error) {
+	const op = "services.reminder.Dispatch"
+
+	log := r.log.With(slog.String("op", op))
+
+	notify := func(ctx context.Context, reminder models.Reminder) error {
+		if err := r.notifier.Notify(ctx, reminder); err != nil {
+			log.Error("failed to send reminder", slog.Int64("item_id", reminder.ItemId), sl.Err(err))
+
+			return err
+		}
+
+		return nil
+	}
+
+	sent, err := r.ReminderProcessor.ProcessReminders(ctx, time.Now(), r.batchSize, notify)
+	if err != nil {
+		log.Error("failed to process reminders", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if sent > 0 {
+		log.Info("reminders sent", slog.Int("count", sent))
+	}
+
+	return sent, nil
+}
@@ -0,0 +1,157 @@
+package remindersrv_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	remindersrv "github.com/Muaz717/todo-app/internal/app/services/reminder"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/handlers/slogdiscard"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProcessor keeps pending reminders in memory and hands them out
+// the way the storage does: due ones first, at most limit per batch,
+// only delivered ones are marked sent.
+type fakeProcessor struct {
+	pending []models.Reminder
+	err     error
+}
+
+func (f *fakeProcessor) ProcessReminders(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	notify func(ctx context.Context, reminder models.Reminder) error,
+) (int, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+
+	sort.SliceStable(f.pending, func(i, j int) bool {
+		return f.pending[i].RemindAt.Before(f.pending[j].RemindAt)
+	})
+
+	var batch, left []models.Reminder
+
+	for _, reminder := range f.pending {
+		if reminder.RemindAt.After(now) || len(batch) == limit {
+			left = append(left, reminder)
+			continue
+		}
+
+		batch = append(batch, reminder)
+	}
+
+	sent := 0
+
+	for _, reminder := range batch {
+		if err := notify(ctx, reminder); err != nil {
+			left = append(left, reminder)
+			continue
+		}
+
+		sent++
+	}
+
+	f.pending = left
+
+	return sent, nil
+}
+
+// fakeNotifier records delivered reminders and fails those of failing items once.
+type fakeNotifier struct {
+	delivered []int64
+	failing   map[int64]bool
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, reminder models.Reminder) error {
+	if f.failing[reminder.ItemId] {
+		delete(f.failing, reminder.ItemId)
+
+		return errors.New("smtp is down")
+	}
+
+	f.delivered = append(f.delivered, reminder.ItemId)
+
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	now := time.Now()
+
+	due := func(itemId int64, ago time.Duration) models.Reminder {
+		return models.Reminder{ItemId: itemId, UserId: 1, Email: "test@mail.ru", Title: "item", RemindAt: now.Add(-ago)}
+	}
+
+	tests := []struct {
+		name      string
+		pending   []models.Reminder
+		failing   map[int64]bool
+		batchSize int
+		// sent holds how many reminders every dispatch in a row sends
+		sent      []int
+		delivered []int64
+	}{
+		{
+			name:      "Due reminders",
+			pending:   []models.Reminder{due(1, time.Minute), due(2, time.Hour), due(3, -time.Hour)},
+			batchSize: 10,
+			sent:      []int{2, 0},
+			delivered: []int64{2, 1},
+		},
+		{
+			name:      "Batch size",
+			pending:   []models.Reminder{due(1, time.Minute), due(2, 2*time.Minute), due(3, 3*time.Minute)},
+			batchSize: 2,
+			sent:      []int{2, 1, 0},
+			delivered: []int64{3, 2, 1},
+		},
+		{
+			name:      "Failed reminder is retried",
+			pending:   []models.Reminder{due(1, time.Minute), due(2, 2*time.Minute)},
+			failing:   map[int64]bool{2: true},
+			batchSize: 10,
+			sent:      []int{1, 1, 0},
+			delivered: []int64{1, 2},
+		},
+		{
+			name:      "Nothing due",
+			pending:   []models.Reminder{due(1, -time.Minute)},
+			batchSize: 10,
+			sent:      []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			processor := &fakeProcessor{pending: tt.pending}
+			notifier := &fakeNotifier{failing: tt.failing}
+
+			reminder := remindersrv.New(slogdiscard.NewDiscardLogger(), processor, notifier, tt.batchSize)
+
+			for i, want := range tt.sent {
+				sent, err := reminder.Dispatch(context.Background())
+				require.NoError(t, err)
+				require.Equal(t, want, sent, "dispatch %d", i+1)
+			}
+
+			require.Equal(t, tt.delivered, notifier.delivered)
+		})
+	}
+}
+
+func TestDispatchError(t *testing.T) {
+	processor := &fakeProcessor{err: errors.New("connection refused")}
+
+	reminder := remindersrv.New(slogdiscard.NewDiscardLogger(), processor, &fakeNotifier{}, 10)
+
+	sent, err := reminder.Dispatch(context.Background())
+	require.Error(t, err)
+	require.Zero(t, sent)
+}
@@ -0,0 +1,294 @@
+package sharesrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Share struct {
+	log          *slog.Logger
+	saver        ShareSaver
+	provider     ShareProvider
+	responder    ShareResponder
+	userProvider UserProvider
+}
+
+type ShareSaver interface {
+	ShareItem(ctx context.Context, ownerId int64, itemId int64, userId int64, role string) (int64, error)
+	ShareList(ctx context.Context, ownerId int64, listId int64, userId int64, role string) (int64, error)
+}
+
+type ShareProvider interface {
+	ItemShares(ctx context.Context, ownerId int64, itemId int64) ([]models.Share, error)
+	ListShares(ctx context.Context, ownerId int64, listId int64) ([]models.Share, error)
+	UserShares(ctx context.Context, userId int64, status string) ([]models.Share, error)
+}
+
+type ShareResponder interface {
+	RespondShare(ctx context.Context, userId int64, shareId int64, accept bool) error
+	DeleteShare(ctx context.Context, userId int64, shareId int64) error
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+}
+
+var (
+	ErrItemNotFound  = errors.New("item not found")
+	ErrListNotFound  = errors.New("list not found")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrShareNotFound = errors.New("share not found")
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrInvalidStatus = errors.New("invalid status")
+	ErrSelfShare     = errors.New("can not share with yourself")
+)
+
+func New(
+	log *slog.Logger,
+	saver ShareSaver,
+	provider ShareProvider,
+	responder ShareResponder,
+	userProvider UserProvider,
+) *Share {
+	return &Share{
+		log:          log,
+		saver:        saver,
+		provider:     provider,
+		responder:    responder,
+		userProvider: userProvider,
+	}
+}
+
+// ShareItem invites the owner of email to the item of ownerId with its subtasks,
+// inviting the same user again changes their role.
+func (s *Share) ShareItem(ctx context.Context, ownerId int64, itemId int64, email string, role string) (int64, error) {
+	const op = "services.share.ShareItem"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	userId, err := s.invitee(ctx, ownerId, email, role)
+	if err != nil {
+		log.Warn("invalid invitation", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shareId, err := s.saver.ShareItem(ctx, ownerId, itemId, userId, role)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to share item", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("item shared", slog.Int64("share_id", shareId), slog.String("role", role))
+
+	return shareId, nil
+}
+
+// ShareList invites the owner of email to the list of ownerId with its items,
+// inviting the same user again changes their role.
+func (s *Share) ShareList(ctx context.Context, ownerId int64, listId int64, email string, role string) (int64, error) {
+	const op = "services.share.ShareList"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	userId, err := s.invitee(ctx, ownerId, email, role)
+	if err != nil {
+		log.Warn("invalid invitation", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shareId, err := s.saver.ShareList(ctx, ownerId, listId, userId, role)
+	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to share list", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("list shared", slog.Int64("share_id", shareId), slog.String("role", role))
+
+	return shareId, nil
+}
+
+// invitee returns id of the user an invitation is sent to, disabled users can not be invited.
+func (s *Share) invitee(ctx context.Context, ownerId int64, email string, role string) (int64, error) {
+	if role != models.ShareViewer && role != models.ShareEditor {
+		return 0, ErrInvalidRole
+	}
+
+	user, err := s.userProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return 0, ErrUserNotFound
+		}
+		return 0, err
+	}
+
+	if user.Disabled {
+		return 0, ErrUserNotFound
+	}
+
+	if user.Id == ownerId {
+		return 0, ErrSelfShare
+	}
+
+	return user.Id, nil
+}
+
+func (s *Share) ItemShares(ctx context.Context, ownerId int64, itemId int64) ([]models.Share, error) {
+	const op = "services.share.ItemShares"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("item_id", itemId),
+	)
+
+	shares, err := s.provider.ItemShares(ctx, ownerId, itemId)
+	if err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Warn("item not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrItemNotFound)
+		}
+
+		log.Error("failed to get item shares", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+func (s *Share) ListShares(ctx context.Context, ownerId int64, listId int64) ([]models.Share, error) {
+	const op = "services.share.ListShares"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("list_id", listId),
+	)
+
+	shares, err := s.provider.ListShares(ctx, ownerId, listId)
+	if err != nil {
+		if errors.Is(err, storage.ErrListNotFound) {
+			log.Warn("list not found", sl.Err(err))
+
+			return nil, fmt.Errorf("%s: %w", op, ErrListNotFound)
+		}
+
+		log.Error("failed to get list shares", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+// Shares returns shares granted to the user in the status, empty status returns them all.
+func (s *Share) Shares(ctx context.Context, userId int64, status string) ([]models.Share, error) {
+	const op = "services.share.Shares"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	switch status {
+	case "", models.SharePending, models.ShareAccepted, models.ShareDeclined:
+	default:
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	shares, err := s.provider.UserShares(ctx, userId, status)
+	if err != nil {
+		log.Error("failed to get shares", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+func (s *Share) Accept(ctx context.Context, userId int64, shareId int64) error {
+	const op = "services.share.Accept"
+
+	return s.respond(ctx, op, userId, shareId, true)
+}
+
+func (s *Share) Decline(ctx context.Context, userId int64, shareId int64) error {
+	const op = "services.share.Decline"
+
+	return s.respond(ctx, op, userId, shareId, false)
+}
+
+func (s *Share) respond(ctx context.Context, op string, userId int64, shareId int64, accept bool) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("share_id", shareId),
+	)
+
+	if err := s.responder.RespondShare(ctx, userId, shareId, accept); err != nil {
+		if errors.Is(err, storage.ErrShareNotFound) {
+			log.Warn("invitation not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrShareNotFound)
+		}
+
+		log.Error("failed to respond to invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation answered", slog.Bool("accepted", accept))
+
+	return nil
+}
+
+// Delete revokes a share the user granted or leaves a share granted to the user.
+func (s *Share) Delete(ctx context.Context, userId int64, shareId int64) error {
+	const op = "services.share.Delete"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("share_id", shareId),
+	)
+
+	if err := s.responder.DeleteShare(ctx, userId, shareId); err != nil {
+		if errors.Is(err, storage.ErrShareNotFound) {
+			log.Warn("share not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrShareNotFound)
+		}
+
+		log.Error("failed to delete share", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("share deleted")
+
+	return nil
+}
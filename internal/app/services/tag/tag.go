@@ -0,0 +1,167 @@
+package tagsrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Tag struct {
+	log *slog.Logger
+	TagSaver
+	TagProvider
+	TagUpdater
+	TagDeleter
+}
+
+type TagSaver interface {
+	SaveTag(ctx context.Context, userId int64, name string) (int64, error)
+}
+
+type TagProvider interface {
+	AllTags(ctx context.Context, userId int64) ([]models.Tag, error)
+}
+
+type TagUpdater interface {
+	UpdateTag(ctx context.Context, userId int64, tagId int64, name string) error
+}
+
+type TagDeleter interface {
+	DeleteTag(ctx context.Context, userId int64, tagId int64) error
+}
+
+var (
+	ErrTagNotFound = errors.New("tag not found")
+	ErrTagExists   = errors.New("tag already exists")
+)
+
+func New(
+	log *slog.Logger,
+	tagSaver TagSaver,
+	tagProvider TagProvider,
+	tagUpdater TagUpdater,
+	tagDeleter TagDeleter,
+) *Tag {
+	return &Tag{
+		log:         log,
+		TagSaver:    tagSaver,
+		TagProvider: tagProvider,
+		TagUpdater:  tagUpdater,
+		TagDeleter:  tagDeleter,
+	}
+}
+
+func (t *Tag) Create(ctx context.Context, userId int64, name string) (int64, error) {
+	const op = "services.tag.Create"
+
+	log := t.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Creating tag")
+
+	tagId, err := t.TagSaver.SaveTag(ctx, userId, strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, storage.ErrTagExists) {
+			log.Warn("tag already exists", sl.Err(err))
+
+			return 0, fmt.Errorf("%s: %w", op, ErrTagExists)
+		}
+
+		log.Error("failed to save tag", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tag saved", slog.Int64("id", tagId))
+
+	return tagId, nil
+}
+
+func (t *Tag) AllTags(ctx context.Context, userId int64) ([]models.Tag, error) {
+	const op = "services.tag.AllTags"
+
+	log := t.log.With(
+		slog.String("op", op),
+	)
+
+	log.Info("Getting tags")
+
+	tags, err := t.TagProvider.AllTags(ctx, userId)
+	if err != nil {
+		log.Error("failed to get tags", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("Got tags")
+
+	return tags, nil
+}
+
+func (t *Tag) Rename(ctx context.Context, userId int64, tagId int64, name string) error {
+	const op = "services.tag.Rename"
+
+	log := t.log.With(
+		slog.String("op", op),
+		slog.Int64("tag_id", tagId),
+	)
+
+	log.Info("Renaming tag")
+
+	err := t.TagUpdater.UpdateTag(ctx, userId, tagId, strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, storage.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTagNotFound)
+		}
+		if errors.Is(err, storage.ErrTagExists) {
+			log.Warn("tag already exists", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTagExists)
+		}
+
+		log.Error("failed to update tag", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tag renamed")
+
+	return nil
+}
+
+func (t *Tag) Delete(ctx context.Context, userId int64, tagId int64) error {
+	const op = "services.tag.Delete"
+
+	log := t.log.With(
+		slog.String("op", op),
+		slog.Int64("tag_id", tagId),
+	)
+
+	log.Info("Deleting tag")
+
+	err := t.TagDeleter.DeleteTag(ctx, userId, tagId)
+	if err != nil {
+		if errors.Is(err, storage.ErrTagNotFound) {
+			log.Warn("tag not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrTagNotFound)
+		}
+
+		log.Error("failed to delete tag", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("tag deleted")
+
+	return nil
+}
@@ -0,0 +1,570 @@
+package workspacesrv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+type Workspace struct {
+	log               *slog.Logger
+	saver             WorkspaceSaver
+	provider          WorkspaceProvider
+	memberManager     MemberManager
+	invitationManager InvitationManager
+	userProvider      UserProvider
+}
+
+type WorkspaceSaver interface {
+	SaveWorkspace(ctx context.Context, ownerId int64, name string) (int64, error)
+	RenameWorkspace(ctx context.Context, workspaceId int64, name string) error
+	DeleteWorkspace(ctx context.Context, workspaceId int64) error
+}
+
+type WorkspaceProvider interface {
+	UserWorkspaces(ctx context.Context, userId int64) ([]models.Workspace, error)
+	Workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error)
+}
+
+type MemberManager interface {
+	WorkspaceMembers(ctx context.Context, workspaceId int64) ([]models.Member, error)
+	MemberRole(ctx context.Context, workspaceId int64, userId int64) (string, error)
+	SetMemberRole(ctx context.Context, workspaceId int64, userId int64, role string) error
+	DeleteMember(ctx context.Context, workspaceId int64, userId int64) error
+}
+
+type InvitationManager interface {
+	SaveInvitation(ctx context.Context, workspaceId int64, invitedBy int64, userId int64, role string) (int64, error)
+	UserInvitations(ctx context.Context, userId int64, status string) ([]models.Invitation, error)
+	WorkspaceInvitations(ctx context.Context, workspaceId int64) ([]models.Invitation, error)
+	RespondInvitation(ctx context.Context, userId int64, invitationId int64, accept bool) error
+	DeleteInvitation(ctx context.Context, workspaceId int64, invitationId int64) error
+}
+
+type UserProvider interface {
+	User(ctx context.Context, email string) (models.User, error)
+}
+
+var (
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrMemberExists       = errors.New("user is already a member")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrForbidden          = errors.New("not allowed in this workspace")
+	ErrPersonalWorkspace  = errors.New("personal workspace can not be shared or deleted")
+	ErrOwnerLeave         = errors.New("owner can not leave the workspace")
+	ErrInvalidRole        = errors.New("invalid role")
+	ErrInvalidStatus      = errors.New("invalid status")
+	ErrWorkspaceNotEmpty  = errors.New("workspace still has items, delete them first")
+)
+
+func New(
+	log *slog.Logger,
+	saver WorkspaceSaver,
+	provider WorkspaceProvider,
+	memberManager MemberManager,
+	invitationManager InvitationManager,
+	userProvider UserProvider,
+) *Workspace {
+	return &Workspace{
+		log:               log,
+		saver:             saver,
+		provider:          provider,
+		memberManager:     memberManager,
+		invitationManager: invitationManager,
+		userProvider:      userProvider,
+	}
+}
+
+// Create creates a workspace owned by the user.
+func (s *Workspace) Create(ctx context.Context, userId int64, name string) (int64, error) {
+	const op = "services.workspace.Create"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	workspaceId, err := s.saver.SaveWorkspace(ctx, userId, name)
+	if err != nil {
+		log.Error("failed to save workspace", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("workspace created", slog.Int64("workspace_id", workspaceId))
+
+	return workspaceId, nil
+}
+
+func (s *Workspace) Workspaces(ctx context.Context, userId int64) ([]models.Workspace, error) {
+	const op = "services.workspace.Workspaces"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	workspaces, err := s.provider.UserWorkspaces(ctx, userId)
+	if err != nil {
+		log.Error("failed to get workspaces", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspaces, nil
+}
+
+func (s *Workspace) Workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error) {
+	const op = "services.workspace.Workspace"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	workspace, err := s.workspace(ctx, userId, workspaceId)
+	if err != nil {
+		log.Warn("failed to get workspace", sl.Err(err))
+
+		return models.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspace, nil
+}
+
+// Rename renames the workspace, owners and admins may do it.
+func (s *Workspace) Rename(ctx context.Context, userId int64, workspaceId int64, name string) error {
+	const op = "services.workspace.Rename"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	if _, err := s.manager(ctx, userId, workspaceId); err != nil {
+		log.Warn("workspace can not be renamed", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.saver.RenameWorkspace(ctx, workspaceId, name); err != nil {
+		log.Error("failed to rename workspace", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("workspace renamed")
+
+	return nil
+}
+
+// Delete deletes the workspace with its lists, only the owner may do it.
+// Workspaces with items outside the trash are not deleted.
+func (s *Workspace) Delete(ctx context.Context, userId int64, workspaceId int64) error {
+	const op = "services.workspace.Delete"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	workspace, err := s.workspace(ctx, userId, workspaceId)
+	if err != nil {
+		log.Warn("failed to get workspace", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if workspace.Role != models.WorkspaceOwner {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	if workspace.Personal {
+		return fmt.Errorf("%s: %w", op, ErrPersonalWorkspace)
+	}
+
+	if err := s.saver.DeleteWorkspace(ctx, workspaceId); err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrWorkspaceNotFound)
+		}
+		if errors.Is(err, storage.ErrWorkspaceNotEmpty) {
+			log.Warn("workspace is not empty", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrWorkspaceNotEmpty)
+		}
+
+		log.Error("failed to delete workspace", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("workspace deleted")
+
+	return nil
+}
+
+// Members returns members of the workspace, every member may see them.
+func (s *Workspace) Members(ctx context.Context, userId int64, workspaceId int64) ([]models.Member, error) {
+	const op = "services.workspace.Members"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	if _, err := s.workspace(ctx, userId, workspaceId); err != nil {
+		log.Warn("failed to get workspace", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := s.memberManager.WorkspaceMembers(ctx, workspaceId)
+	if err != nil {
+		log.Error("failed to get members", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// SetRole changes the role of a member. Owners and admins manage members,
+// only the owner grants or takes the admin role, nobody changes the owner.
+func (s *Workspace) SetRole(ctx context.Context, userId int64, workspaceId int64, memberId int64, role string) error {
+	const op = "services.workspace.SetRole"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+		slog.Int64("member_id", memberId),
+	)
+
+	if !assignable(role) {
+		return fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
+	workspace, err := s.manager(ctx, userId, workspaceId)
+	if err != nil {
+		log.Warn("role can not be changed", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	current, err := s.memberRole(ctx, workspaceId, memberId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if current == models.WorkspaceOwner ||
+		workspace.Role != models.WorkspaceOwner && (role == models.WorkspaceAdmin || current == models.WorkspaceAdmin) {
+		return fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	if err := s.memberManager.SetMemberRole(ctx, workspaceId, memberId, role); err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrMemberNotFound)
+		}
+
+		log.Error("failed to set member role", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("member role changed", slog.String("role", role))
+
+	return nil
+}
+
+// RemoveMember removes a member from the workspace. Everyone but the owner
+// may leave, owners remove anyone else and admins remove members and guests.
+func (s *Workspace) RemoveMember(ctx context.Context, userId int64, workspaceId int64, memberId int64) error {
+	const op = "services.workspace.RemoveMember"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+		slog.Int64("member_id", memberId),
+	)
+
+	workspace, err := s.workspace(ctx, userId, workspaceId)
+	if err != nil {
+		log.Warn("failed to get workspace", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if memberId == userId {
+		if workspace.Role == models.WorkspaceOwner {
+			return fmt.Errorf("%s: %w", op, ErrOwnerLeave)
+		}
+	} else {
+		if !manages(workspace.Role) {
+			return fmt.Errorf("%s: %w", op, ErrForbidden)
+		}
+
+		current, err := s.memberRole(ctx, workspaceId, memberId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if current == models.WorkspaceOwner ||
+			current == models.WorkspaceAdmin && workspace.Role != models.WorkspaceOwner {
+			return fmt.Errorf("%s: %w", op, ErrForbidden)
+		}
+	}
+
+	if err := s.memberManager.DeleteMember(ctx, workspaceId, memberId); err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrMemberNotFound)
+		}
+
+		log.Error("failed to remove member", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("member removed")
+
+	return nil
+}
+
+// Invite invites the owner of email to the workspace with the role, inviting
+// the same user again changes the role. Only the owner invites admins.
+func (s *Workspace) Invite(
+	ctx context.Context,
+	userId int64,
+	workspaceId int64,
+	email string,
+	role string,
+) (int64, error) {
+	const op = "services.workspace.Invite"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	if !assignable(role) {
+		return 0, fmt.Errorf("%s: %w", op, ErrInvalidRole)
+	}
+
+	workspace, err := s.manager(ctx, userId, workspaceId)
+	if err != nil {
+		log.Warn("invitation can not be sent", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if workspace.Personal {
+		return 0, fmt.Errorf("%s: %w", op, ErrPersonalWorkspace)
+	}
+
+	if role == models.WorkspaceAdmin && workspace.Role != models.WorkspaceOwner {
+		return 0, fmt.Errorf("%s: %w", op, ErrForbidden)
+	}
+
+	user, err := s.userProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+
+		log.Error("failed to get user", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// disabled users can not be invited
+	if user.Disabled {
+		return 0, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+	}
+
+	invitationId, err := s.invitationManager.SaveInvitation(ctx, workspaceId, userId, user.Id, role)
+	if err != nil {
+		if errors.Is(err, storage.ErrMemberExists) {
+			return 0, fmt.Errorf("%s: %w", op, ErrMemberExists)
+		}
+
+		log.Error("failed to save invitation", sl.Err(err))
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation sent", slog.Int64("invitation_id", invitationId), slog.String("role", role))
+
+	return invitationId, nil
+}
+
+// Invitations returns invitations of the user in the status, empty status returns them all.
+func (s *Workspace) Invitations(ctx context.Context, userId int64, status string) ([]models.Invitation, error) {
+	const op = "services.workspace.Invitations"
+
+	log := s.log.With(
+		slog.String("op", op),
+	)
+
+	switch status {
+	case "", models.InvitationPending, models.InvitationAccepted, models.InvitationDeclined:
+	default:
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	invitations, err := s.invitationManager.UserInvitations(ctx, userId, status)
+	if err != nil {
+		log.Error("failed to get invitations", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// WorkspaceInvitations returns invitations to the workspace nobody accepted yet,
+// owners and admins may see them.
+func (s *Workspace) WorkspaceInvitations(
+	ctx context.Context,
+	userId int64,
+	workspaceId int64,
+) ([]models.Invitation, error) {
+	const op = "services.workspace.WorkspaceInvitations"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+	)
+
+	if _, err := s.manager(ctx, userId, workspaceId); err != nil {
+		log.Warn("invitations can not be seen", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := s.invitationManager.WorkspaceInvitations(ctx, workspaceId)
+	if err != nil {
+		log.Error("failed to get invitations", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// RevokeInvitation deletes an invitation nobody accepted yet.
+func (s *Workspace) RevokeInvitation(ctx context.Context, userId int64, workspaceId int64, invitationId int64) error {
+	const op = "services.workspace.RevokeInvitation"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("workspace_id", workspaceId),
+		slog.Int64("invitation_id", invitationId),
+	)
+
+	if _, err := s.manager(ctx, userId, workspaceId); err != nil {
+		log.Warn("invitation can not be revoked", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.invitationManager.DeleteInvitation(ctx, workspaceId, invitationId); err != nil {
+		if errors.Is(err, storage.ErrInvitationNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		log.Error("failed to delete invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation revoked")
+
+	return nil
+}
+
+func (s *Workspace) Accept(ctx context.Context, userId int64, invitationId int64) error {
+	const op = "services.workspace.Accept"
+
+	return s.respond(ctx, op, userId, invitationId, true)
+}
+
+func (s *Workspace) Decline(ctx context.Context, userId int64, invitationId int64) error {
+	const op = "services.workspace.Decline"
+
+	return s.respond(ctx, op, userId, invitationId, false)
+}
+
+func (s *Workspace) respond(ctx context.Context, op string, userId int64, invitationId int64, accept bool) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("invitation_id", invitationId),
+	)
+
+	if err := s.invitationManager.RespondInvitation(ctx, userId, invitationId, accept); err != nil {
+		if errors.Is(err, storage.ErrInvitationNotFound) {
+			log.Warn("invitation not found", sl.Err(err))
+
+			return fmt.Errorf("%s: %w", op, ErrInvitationNotFound)
+		}
+
+		log.Error("failed to respond to invitation", sl.Err(err))
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("invitation answered", slog.Bool("accepted", accept))
+
+	return nil
+}
+
+// workspace returns the workspace as seen by the user, ErrWorkspaceNotFound
+// unless the user is a member.
+func (s *Workspace) workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error) {
+	workspace, err := s.provider.Workspace(ctx, userId, workspaceId)
+	if err != nil {
+		if errors.Is(err, storage.ErrWorkspaceNotFound) {
+			return models.Workspace{}, ErrWorkspaceNotFound
+		}
+		return models.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+// manager is workspace for operations reserved to owners and admins.
+func (s *Workspace) manager(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error) {
+	workspace, err := s.workspace(ctx, userId, workspaceId)
+	if err != nil {
+		return models.Workspace{}, err
+	}
+
+	if !manages(workspace.Role) {
+		return models.Workspace{}, ErrForbidden
+	}
+
+	return workspace, nil
+}
+
+func (s *Workspace) memberRole(ctx context.Context, workspaceId int64, userId int64) (string, error) {
+	role, err := s.memberManager.MemberRole(ctx, workspaceId, userId)
+	if err != nil {
+		if errors.Is(err, storage.ErrMemberNotFound) {
+			return "", ErrMemberNotFound
+		}
+		return "", err
+	}
+
+	return role, nil
+}
+
+func manages(role string) bool {
+	return role == models.WorkspaceOwner || role == models.WorkspaceAdmin
+}
+
+// assignable tells whether members can be given the role, there is one owner per workspace.
+func assignable(role string) bool {
+	return role == models.WorkspaceAdmin || role == models.WorkspaceMember || role == models.WorkspaceGuest
+}
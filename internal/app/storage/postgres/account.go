@@ -0,0 +1,201 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) Profile(ctx context.Context, userId int64) (models.Profile, error) {
+	const op = "postgres.Profile"
+
+	query := `SELECT id, email, display_name, timezone, locale, email_verified, totp_enabled
+		FROM users WHERE id = $1`
+
+	profile, err := scanProfile(s.db.QueryRow(ctx, query, userId))
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.Profile{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return models.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+func (s *Storage) UpdateProfile(
+	ctx context.Context,
+	userId int64,
+	input models.UpdateProfileInput,
+) (models.Profile, error) {
+	const op = "postgres.UpdateProfile"
+
+	query := `UPDATE users
+		SET display_name = COALESCE($1, display_name),
+			timezone = COALESCE($2, timezone),
+			locale = COALESCE($3, locale)
+		WHERE id = $4
+		RETURNING id, email, display_name, timezone, locale, email_verified, totp_enabled`
+
+	profile, err := scanProfile(s.db.QueryRow(
+		ctx,
+		query,
+		input.DisplayName,
+		input.Timezone,
+		input.Locale,
+		userId,
+	))
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.Profile{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return models.Profile{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return profile, nil
+}
+
+// SaveEmailChange saves a verification token which, once spent,
+// replaces the email of the user with newEmail.
+func (s *Storage) SaveEmailChange(
+	ctx context.Context,
+	userId int64,
+	newEmail string,
+	tokenHash string,
+	expiresAt time.Time,
+) error {
+	const op = "postgres.SaveEmailChange"
+
+	query := `INSERT INTO email_verifications(user_id, token_hash, expires_at, new_email) VALUES($1, $2, $3, $4)`
+
+	if _, err := s.db.Exec(ctx, query, userId, tokenHash, expiresAt, newEmail); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteUser deletes the user, everything the user owns is deleted by cascade.
+// Workspaces the user shares with others go to their longest standing admin,
+// storage.ErrWorkspaceOwned is returned when one of them has no admin. Lists and
+// items the user created in workspaces of others stay there and are handed over
+// to the owners of those workspaces.
+func (s *Storage) DeleteUser(ctx context.Context, userId int64) error {
+	const op = "postgres.DeleteUser"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := handOverWorkspaces(ctx, tx, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, table := range []string{"lists", "items"} {
+		query := `UPDATE ` + table + ` SET user_id = w.owner_id
+			FROM workspaces w
+			WHERE w.id = ` + table + `.workspace_id AND ` + table + `.user_id = $1 AND w.owner_id <> $1`
+
+		if _, err := tx.Exec(ctx, query, userId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM users WHERE id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// handOverWorkspaces makes the longest standing admin of every workspace the user
+// owns and shares with others its owner.
+func handOverWorkspaces(ctx context.Context, tx pgx5.Tx, userId int64) error {
+	query := `SELECT w.id,
+			(SELECT wm.user_id FROM workspace_members wm
+				WHERE wm.workspace_id = w.id AND wm.role = 'admin'
+				ORDER BY wm.created_at, wm.user_id
+				LIMIT 1)
+		FROM workspaces w
+		WHERE w.owner_id = $1 AND NOT w.personal
+			AND EXISTS (SELECT 1 FROM workspace_members wm WHERE wm.workspace_id = w.id AND wm.user_id <> $1)
+		FOR UPDATE OF w`
+
+	rows, err := tx.Query(ctx, query, userId)
+	if err != nil {
+		return err
+	}
+
+	type handOver struct {
+		workspaceId int64
+		adminId     *int64
+	}
+
+	var workspaces []handOver
+
+	for rows.Next() {
+		var h handOver
+
+		if err := rows.Scan(&h.workspaceId, &h.adminId); err != nil {
+			rows.Close()
+			return err
+		}
+
+		workspaces = append(workspaces, h)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, h := range workspaces {
+		if h.adminId == nil {
+			return storage.ErrWorkspaceOwned
+		}
+
+		if _, err := tx.Exec(ctx, `UPDATE workspaces SET owner_id = $2 WHERE id = $1`, h.workspaceId, *h.adminId); err != nil {
+			return err
+		}
+
+		query := `UPDATE workspace_members SET role = 'owner' WHERE workspace_id = $1 AND user_id = $2`
+
+		if _, err := tx.Exec(ctx, query, h.workspaceId, *h.adminId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func scanProfile(row pgx5.Row) (models.Profile, error) {
+	var p models.Profile
+
+	err := row.Scan(
+		&p.Id,
+		&p.Email,
+		&p.DisplayName,
+		&p.Timezone,
+		&p.Locale,
+		&p.EmailVerified,
+		&p.TwoFactorEnabled,
+	)
+
+	return p, err
+}
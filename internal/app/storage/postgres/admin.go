@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+const userSummaryColumns = `id, email, display_name, role, email_verified, totp_enabled, disabled_at`
+
+func scanUserSummary(row pgx5.Row, dest ...any) (models.UserSummary, error) {
+	var u models.UserSummary
+
+	err := row.Scan(append([]any{
+		&u.Id,
+		&u.Email,
+		&u.DisplayName,
+		&u.Role,
+		&u.EmailVerified,
+		&u.TwoFactorEnabled,
+		&u.DisabledAt,
+	}, dest...)...)
+
+	return u, err
+}
+
+func (s *Storage) Users(ctx context.Context, filter models.UserFilter) ([]models.UserSummary, error) {
+	const op = "postgres.Users"
+
+	args := []any{filter.AfterId}
+	arg := func(v any) string {
+		args = append(args, v)
+
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	where := []string{"id > $1"}
+
+	if filter.Query != "" {
+		pattern := arg("%" + likeEscaper.Replace(filter.Query) + "%")
+		where = append(where, "(email ILIKE "+pattern+" OR display_name ILIKE "+pattern+")")
+	}
+	if filter.Role != "" {
+		where = append(where, "role = "+arg(filter.Role))
+	}
+	if filter.Disabled != nil {
+		where = append(where, "(disabled_at IS NOT NULL) = "+arg(*filter.Disabled))
+	}
+
+	query := `SELECT ` + userSummaryColumns + ` FROM users
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY id`
+
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + arg(filter.Limit)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	users := []models.UserSummary{}
+
+	for rows.Next() {
+		u, err := scanUserSummary(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		users = append(users, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+func (s *Storage) UserDetails(ctx context.Context, userId int64) (models.UserDetails, error) {
+	const op = "postgres.UserDetails"
+
+	query := `SELECT ` + userSummaryColumns + `,
+			(SELECT count(*) FROM items WHERE user_id = users.id AND deleted_at IS NULL),
+			(SELECT count(*) FROM items WHERE user_id = users.id AND deleted_at IS NULL AND NOT done),
+			(SELECT count(*) FROM items WHERE user_id = users.id AND deleted_at IS NULL AND NOT done AND due_at < now()),
+			(SELECT count(*) FROM lists WHERE user_id = users.id),
+			(SELECT count(*) FROM tags WHERE user_id = users.id)
+		FROM users WHERE id = $1`
+
+	var d models.UserDetails
+
+	summary, err := scanUserSummary(
+		s.db.QueryRow(ctx, query, userId),
+		&d.Items.Total,
+		&d.Items.Open,
+		&d.Items.Overdue,
+		&d.Lists,
+		&d.Tags,
+	)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.UserDetails{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return models.UserDetails{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	d.UserSummary = summary
+	d.Items.Done = d.Items.Total - d.Items.Open
+
+	return d, nil
+}
+
+// UserRole returns the role of an enabled user, disabled users are not found.
+func (s *Storage) UserRole(ctx context.Context, userId int64) (string, error) {
+	const op = "postgres.UserRole"
+
+	query := `SELECT role FROM users WHERE id = $1 AND disabled_at IS NULL`
+
+	var role string
+
+	if err := s.db.QueryRow(ctx, query, userId).Scan(&role); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+// UserActive reports whether the user may use sessions, disabled users and
+// users required to reset the password may not.
+func (s *Storage) UserActive(ctx context.Context, userId int64) (bool, error) {
+	const op = "postgres.UserActive"
+
+	query := `SELECT disabled_at IS NULL AND NOT password_reset_required FROM users WHERE id = $1`
+
+	var active bool
+
+	if err := s.db.QueryRow(ctx, query, userId).Scan(&active); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return false, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return active, nil
+}
+
+// SetUserDisabled disables or enables the user. Disabling ends every session
+// and pending sign-in of the user, api tokens are refused while it lasts.
+func (s *Storage) SetUserDisabled(ctx context.Context, userId int64, disabled bool) error {
+	const op = "postgres.SetUserDisabled"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users
+		SET disabled_at = CASE WHEN $1 THEN COALESCE(disabled_at, now()) END
+		WHERE id = $2`
+
+	tag, err := tx.Exec(ctx, query, disabled, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if disabled {
+		query = `UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+
+		if _, err := tx.Exec(ctx, query, userId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		query = `UPDATE two_factor_challenges SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`
+
+		if _, err := tx.Exec(ctx, query, userId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RequirePasswordReset refuses sign-in of the user until the password is reset,
+// every refresh and API token of the user is revoked.
+func (s *Storage) RequirePasswordReset(ctx context.Context, userId int64) error {
+	const op = "postgres.RequirePasswordReset"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET password_reset_required = true WHERE id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if err := revokeCredentials(ctx, tx, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SetUserRole(ctx context.Context, userId int64, role string) error {
+	const op = "postgres.SetUserRole"
+
+	tag, err := s.db.Exec(ctx, `UPDATE users SET role = $1 WHERE id = $2`, role, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// GrantAdmin makes the user with email an admin.
+func (s *Storage) GrantAdmin(ctx context.Context, email string) error {
+	const op = "postgres.GrantAdmin"
+
+	tag, err := s.db.Exec(ctx, `UPDATE users SET role = $1 WHERE email = $2`, models.RoleAdmin, email)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
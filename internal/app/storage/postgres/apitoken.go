@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+const apiTokenColumns = `id, user_id, name, prefix, scopes, expires_at, last_used_at, created_at`
+
+func (s *Storage) SaveAPIToken(
+	ctx context.Context,
+	userId int64,
+	input models.CreateAPITokenInput,
+	prefix string,
+	tokenHash string,
+) (models.APIToken, error) {
+	const op = "postgres.SaveAPIToken"
+
+	query := `INSERT INTO api_tokens(user_id, name, prefix, token_hash, scopes, expires_at)
+		VALUES($1, $2, $3, $4, $5, $6)
+		RETURNING ` + apiTokenColumns
+
+	rows, err := s.db.Query(ctx, query, userId, input.Name, prefix, tokenHash, input.Scopes, input.ExpiresAt)
+	if err != nil {
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := pgx5.CollectExactlyOneRow(rows, pgx5.RowToStructByName[models.APIToken])
+	if err != nil {
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return token, nil
+}
+
+// AllAPITokens returns tokens of the user that were not revoked, expired ones included.
+func (s *Storage) AllAPITokens(ctx context.Context, userId int64) ([]models.APIToken, error) {
+	const op = "postgres.AllAPITokens"
+
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens
+		WHERE user_id = $1 AND revoked_at IS NULL
+		ORDER BY id`
+
+	rows, err := s.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.APIToken])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// UseAPIToken returns the token with tokenHash and records it was used.
+// Revoked tokens and tokens of disabled users are not found, expired ones fail
+// with storage.ErrTokenExpired.
+func (s *Storage) UseAPIToken(ctx context.Context, tokenHash string) (models.APIToken, error) {
+	const op = "postgres.UseAPIToken"
+
+	query := `UPDATE api_tokens
+		SET last_used_at = CASE WHEN expires_at <= now() THEN last_used_at ELSE now() END
+		WHERE token_hash = $1 AND revoked_at IS NULL
+			AND user_id IN (SELECT id FROM users WHERE disabled_at IS NULL)
+		RETURNING ` + apiTokenColumns
+
+	rows, err := s.db.Query(ctx, query, tokenHash)
+	if err != nil {
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	token, err := pgx5.CollectExactlyOneRow(rows, pgx5.RowToStructByName[models.APIToken])
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.APIToken{}, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if token.ExpiresAt != nil && !token.ExpiresAt.After(time.Now()) {
+		return models.APIToken{}, fmt.Errorf("%s: %w", op, storage.ErrTokenExpired)
+	}
+
+	return token, nil
+}
+
+func (s *Storage) RevokeAPIToken(ctx context.Context, userId int64, tokenId int64) error {
+	const op = "postgres.RevokeAPIToken"
+
+	query := `UPDATE api_tokens SET revoked_at = now()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	tag, err := s.db.Exec(ctx, query, tokenId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+	}
+
+	return nil
+}
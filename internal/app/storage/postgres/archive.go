@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// ExportUserData reads the profile, tags and personal workspace of the user into
+// sink from a single snapshot, items are streamed from the database rather than
+// loaded at once. References to lists and items left out of the export are dropped.
+func (s *Storage) ExportUserData(ctx context.Context, userId int64, sink models.ExportSink) error {
+	const op = "postgres.ExportUserData"
+
+	tx, err := s.db.BeginTx(ctx, pgx5.TxOptions{
+		IsoLevel:   pgx5.RepeatableRead,
+		AccessMode: pgx5.ReadOnly,
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT id, email, display_name, timezone, locale, email_verified, totp_enabled
+		FROM users WHERE id = $1`
+
+	profile, err := scanProfile(tx.QueryRow(ctx, query, userId))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := sink.Profile(profile); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// only lists and items of the personal workspace are exported, none of them
+	// is shared with the user and they only refer to each other
+	rows, err := tx.Query(ctx, `SELECT `+listColumns+`, '' AS shared_as FROM lists
+		WHERE workspace_id = `+personalWorkspace("$1")+` ORDER BY position, id`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	lists, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.List])
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := sink.Lists(lists); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err = tx.Query(ctx, `SELECT id, name FROM tags WHERE user_id = $1 ORDER BY id`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	tags, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Tag])
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := sink.Tags(tags); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err = tx.Query(ctx, `SELECT `+itemColumns+`, '' AS shared_as FROM items
+		WHERE workspace_id = `+personalWorkspace("$1")+` AND deleted_at IS NULL ORDER BY id`, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	listIds := make(map[int64]bool, len(lists))
+	for _, l := range lists {
+		listIds[l.Id] = true
+	}
+
+	// parents come before their subtasks, a subtask restored from the trash
+	// without its parent is exported as a top level item
+	itemIds := make(map[int64]bool)
+
+	for rows.Next() {
+		item, err := pgx5.RowToStructByName[models.Item](rows)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if item.ListId != nil && !listIds[*item.ListId] {
+			item.ListId = nil
+		}
+		if item.ParentId != nil && !itemIds[*item.ParentId] {
+			item.ParentId = nil
+		}
+		itemIds[int64(item.Id)] = true
+
+		if err := sink.Item(item); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// HasUserData reports whether the user owns any item, list or tag.
+func (s *Storage) HasUserData(ctx context.Context, userId int64) (bool, error) {
+	const op = "postgres.HasUserData"
+
+	query := `SELECT EXISTS(SELECT 1 FROM items WHERE user_id = $1)
+		OR EXISTS(SELECT 1 FROM lists WHERE user_id = $1)
+		OR EXISTS(SELECT 1 FROM tags WHERE user_id = $1)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, userId).Scan(&exists); err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return exists, nil
+}
+
+// ImportUserData restores data into the account of the user in a single
+// transaction, ids of the archive are remapped to new ones. Parents and series
+// roots must come before the items referring to them, unknown references are
+// storage.ErrListNotFound and storage.ErrItemNotFound. Reminders already due
+// are marked sent, so importing does not fire them all at once.
+func (s *Storage) ImportUserData(ctx context.Context, userId int64, data models.Import) error {
+	const op = "postgres.ImportUserData"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users
+		SET display_name = COALESCE($1, display_name),
+			timezone = COALESCE($2, timezone),
+			locale = COALESCE($3, locale)
+		WHERE id = $4`
+
+	p := data.Profile
+
+	if _, err := tx.Exec(ctx, query, p.DisplayName, p.Timezone, p.Locale, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	listIds := make(map[int64]int64, len(data.Lists))
+
+	// imported lists and items go to the personal workspace
+	query = `INSERT INTO lists(name, color, position, archived, user_id, workspace_id)
+		VALUES($1, $2, $3, $4, $5, ` + personalWorkspace("$5") + `) RETURNING id`
+
+	for _, l := range data.Lists {
+		var listId int64
+
+		if err := tx.QueryRow(ctx, query, l.Name, l.Color, l.Position, l.Archived, userId).Scan(&listId); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		listIds[l.Id] = listId
+	}
+
+	// tags are matched by name the way items refer to them
+	tagIds := make(map[string]int64, len(data.Tags))
+
+	saveTag := func(name string) (int64, error) {
+		if tagId, ok := tagIds[strings.ToLower(name)]; ok {
+			return tagId, nil
+		}
+
+		query := `INSERT INTO tags(name, user_id) VALUES($1, $2)
+			ON CONFLICT (user_id, lower(name)) DO UPDATE SET name = tags.name
+			RETURNING id`
+
+		var tagId int64
+
+		if err := tx.QueryRow(ctx, query, name, userId).Scan(&tagId); err != nil {
+			return 0, err
+		}
+
+		tagIds[strings.ToLower(name)] = tagId
+
+		return tagId, nil
+	}
+
+	for _, t := range data.Tags {
+		if _, err := saveTag(t.Name); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	itemIds := make(map[int64]int64)
+	// series whose root was deleted before the export are rooted at their first item
+	seriesIds := make(map[int64]int64)
+
+	query = `INSERT INTO items(title, description, done, completed_at, due_at, remind_at, reminded_at,
+			priority, list_id, parent_id, position, rrule, series_id, user_id, workspace_id)
+		VALUES($1, $2, $3, $4, $5, $6, CASE WHEN $6::timestamptz <= now() THEN now() END,
+			$7, $8, $9, $10, $11, $12, $13, ` + personalWorkspace("$13") + `)
+		RETURNING id`
+
+	for item, err := range data.Items {
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		var listId, parentId, seriesId *int64
+
+		if item.ListId != nil {
+			id, ok := listIds[*item.ListId]
+			if !ok {
+				return fmt.Errorf("%s: item %d: %w", op, item.Id, storage.ErrListNotFound)
+			}
+			listId = &id
+		}
+
+		if item.ParentId != nil {
+			id, ok := itemIds[*item.ParentId]
+			if !ok {
+				return fmt.Errorf("%s: item %d: %w", op, item.Id, storage.ErrItemNotFound)
+			}
+			parentId = &id
+		}
+
+		if item.SeriesId != nil {
+			if id, ok := itemIds[*item.SeriesId]; ok {
+				seriesId = &id
+			} else if id, ok := seriesIds[*item.SeriesId]; ok {
+				seriesId = &id
+			}
+		}
+
+		var itemId int64
+
+		err := tx.QueryRow(
+			ctx,
+			query,
+			item.Title,
+			item.Description,
+			item.Done,
+			item.CompletedAt,
+			item.DueAt,
+			item.RemindAt,
+			item.Priority,
+			listId,
+			parentId,
+			item.Position,
+			item.RRule,
+			seriesId,
+			userId,
+		).Scan(&itemId)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		itemIds[int64(item.Id)] = itemId
+
+		if item.SeriesId != nil && seriesId == nil {
+			seriesIds[*item.SeriesId] = itemId
+
+			if _, err := tx.Exec(ctx, `UPDATE items SET series_id = id WHERE id = $1`, itemId); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		for _, name := range item.Tags {
+			tagId, err := saveTag(name)
+			if err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			query := `INSERT INTO item_tags(item_id, tag_id) VALUES($1, $2) ON CONFLICT DO NOTHING`
+
+			if _, err := tx.Exec(ctx, query, itemId, tagId); err != nil {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -8,13 +8,21 @@ import (
 	"github.com/Muaz717/todo-app/internal/app/storage"
 	"github.com/Muaz717/todo-app/internal/domain/models"
 
-	"github.com/jackc/pgx"
+	pgx5 "github.com/jackc/pgx/v5"
 )
 
 func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (int64, error) {
 	const op = "postgres.SaveUser"
 
-	query := `INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING id`
+	// every user starts with a personal workspace of their own
+	query := `WITH u AS (
+			INSERT INTO users(email, pass_hash) VALUES($1, $2) RETURNING id
+		), w AS (
+			INSERT INTO workspaces(name, owner_id, personal) SELECT 'Personal', id, true FROM u RETURNING id, owner_id
+		), m AS (
+			INSERT INTO workspace_members(workspace_id, user_id, role) SELECT id, owner_id, 'owner' FROM w
+		)
+		SELECT id FROM u`
 
 	row := s.db.QueryRow(ctx, query, email, passHash)
 
@@ -22,8 +30,8 @@ func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (
 
 	err := row.Scan(&userId)
 	if err != nil {
-		if pgErr, ok := err.(*pgx.PgError); ok {
-			return 0, fmt.Errorf("%s: SQL Error: %s, Detail: %s, Where: %s", op, pgErr.Message, pgErr.Detail, pgErr.Where)
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
 		}
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
@@ -34,15 +42,25 @@ func (s *Storage) SaveUser(ctx context.Context, email string, passHash []byte) (
 func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 	const op = "postgres.User"
 
-	query := `SELECT id, email, pass_hash FROM users WHERE email=$1`
+	query := `SELECT id, email, pass_hash, email_verified, totp_enabled, role, disabled_at IS NOT NULL,
+		password_reset_required FROM users WHERE email=$1`
 
 	row := s.db.QueryRow(ctx, query, email)
 
 	var user models.User
 
-	err := row.Scan(&user.Id, &user.Email, &user.PassHash)
+	err := row.Scan(
+		&user.Id,
+		&user.Email,
+		&user.PassHash,
+		&user.EmailVerified,
+		&user.TwoFactorEnabled,
+		&user.Role,
+		&user.Disabled,
+		&user.PasswordResetRequired,
+	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
+		if errors.Is(err, pgx5.ErrNoRows) {
 			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 		}
 		return models.User{}, fmt.Errorf("%s: %w", op, err)
@@ -50,3 +68,49 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 
 	return user, nil
 }
+
+func (s *Storage) UserById(ctx context.Context, userId int64) (models.User, error) {
+	const op = "postgres.UserById"
+
+	query := `SELECT id, email, pass_hash, email_verified, totp_enabled, role, disabled_at IS NOT NULL,
+		password_reset_required FROM users WHERE id = $1`
+
+	var user models.User
+
+	err := s.db.QueryRow(ctx, query, userId).Scan(
+		&user.Id,
+		&user.Email,
+		&user.PassHash,
+		&user.EmailVerified,
+		&user.TwoFactorEnabled,
+		&user.Role,
+		&user.Disabled,
+		&user.PasswordResetRequired,
+	)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+func (s *Storage) UserTimezone(ctx context.Context, userId int64) (string, error) {
+	const op = "postgres.UserTimezone"
+
+	query := `SELECT timezone FROM users WHERE id = $1`
+
+	var timezone string
+
+	err := s.db.QueryRow(ctx, query, userId).Scan(&timezone)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return timezone, nil
+}
@@ -0,0 +1,150 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+const commentColumns = `c.id, c.item_id, c.parent_id, c.user_id, u.email AS author_email,
+	c.body, c.created_at, c.updated_at`
+
+// SaveComment leaves a comment on the item, anyone who can see the item may
+// comment on it. A reply must belong to the same item as its parent,
+// otherwise the parent is storage.ErrCommentNotFound.
+func (s *Storage) SaveComment(
+	ctx context.Context,
+	userId int64,
+	itemId int64,
+	parentId *int64,
+	body string,
+) (int64, error) {
+	const op = "postgres.SaveComment"
+
+	if err := s.checkItemAccess(ctx, userId, itemId, false); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO comments(item_id, parent_id, user_id, body)
+		SELECT $1, $2, $3, $4
+		WHERE $2::bigint IS NULL OR EXISTS (SELECT 1 FROM comments WHERE id = $2 AND item_id = $1)
+		RETURNING id`
+
+	var commentId int64
+
+	if err := s.db.QueryRow(ctx, query, itemId, parentId, userId, body).Scan(&commentId); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrCommentNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return commentId, nil
+}
+
+// ItemComments returns comments on the item the user can see, oldest first.
+func (s *Storage) ItemComments(ctx context.Context, userId int64, itemId int64) ([]models.Comment, error) {
+	const op = "postgres.ItemComments"
+
+	if err := s.checkItemAccess(ctx, userId, itemId, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT ` + commentColumns + `
+		FROM comments c JOIN users u ON u.id = c.user_id
+		WHERE c.item_id = $1
+		ORDER BY c.id`
+
+	rows, err := s.db.Query(ctx, query, itemId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	comments, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Comment])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return comments, nil
+}
+
+// UpdateComment changes the body of a comment the user wrote on an item they still see.
+func (s *Storage) UpdateComment(ctx context.Context, userId int64, itemId int64, commentId int64, body string) error {
+	const op = "postgres.UpdateComment"
+
+	query := `UPDATE comments SET body = $1, updated_at = now()
+		WHERE id = $2 AND item_id = $3 AND user_id = $4
+			AND EXISTS (SELECT 1 FROM items WHERE id = $3 AND ` + canViewItem("$4") + `)`
+
+	tag, err := s.db.Exec(ctx, query, body, commentId, itemId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCommentNotFound)
+	}
+
+	return nil
+}
+
+// DeleteComment deletes a comment the user wrote together with replies to it.
+func (s *Storage) DeleteComment(ctx context.Context, userId int64, itemId int64, commentId int64) error {
+	const op = "postgres.DeleteComment"
+
+	query := `DELETE FROM comments
+		WHERE id = $1 AND item_id = $2 AND user_id = $3
+			AND EXISTS (SELECT 1 FROM items WHERE id = $2 AND ` + canViewItem("$3") + `)`
+
+	tag, err := s.db.Exec(ctx, query, commentId, itemId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCommentNotFound)
+	}
+
+	return nil
+}
+
+// SaveMentions notifies users with the emails mentioned by the actor in the comment.
+// Only active users who can see the item are notified, the actor never is and
+// users already notified about the comment are skipped. It returns the users
+// notified by this call.
+func (s *Storage) SaveMentions(
+	ctx context.Context,
+	actorId int64,
+	itemId int64,
+	commentId int64,
+	emails []string,
+) ([]models.Mention, error) {
+	const op = "postgres.SaveMentions"
+
+	query := `WITH n AS (
+			INSERT INTO notifications(user_id, actor_id, kind, item_id, comment_id)
+			SELECT u.id, $2, 'mention', items.id, $3
+			FROM users u JOIN items ON items.id = $1
+			WHERE lower(u.email) = ANY($4) AND u.id <> $2 AND u.disabled_at IS NULL
+				AND ` + canViewItem("u.id") + `
+			ON CONFLICT (comment_id, user_id) WHERE comment_id IS NOT NULL DO NOTHING
+			RETURNING user_id
+		)
+		SELECT n.user_id, u.email FROM n JOIN users u ON u.id = n.user_id`
+
+	rows, err := s.db.Query(ctx, query, itemId, actorId, commentId, emails)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	mentions, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Mention])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mentions, nil
+}
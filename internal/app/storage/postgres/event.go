@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// ItemEvents returns history of the item the user can see, oldest events first.
+func (s *Storage) ItemEvents(ctx context.Context, userId int64, itemId int64) ([]models.ItemEvent, error) {
+	const op = "postgres.ItemEvents"
+
+	if err := s.checkItemAccess(ctx, userId, itemId, false); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT e.id, e.item_id, e.user_id AS actor_id, u.email AS actor_email, e.kind, e.field,
+			e.old_value, e.new_value, e.created_at
+		FROM item_events e LEFT JOIN users u ON u.id = e.user_id
+		WHERE e.item_id = $1
+		ORDER BY e.id`
+
+	rows, err := s.db.Query(ctx, query, itemId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	events, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.ItemEvent])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return events, nil
+}
+
+// saveItemEvent appends an event made by the user to the history of the item,
+// it runs in the transaction of the change so both are saved or neither is.
+func saveItemEvent(
+	ctx context.Context,
+	tx pgx5.Tx,
+	itemId int64,
+	userId int64,
+	kind string,
+	field string,
+	oldValue *string,
+	newValue *string,
+) error {
+	query := `INSERT INTO item_events(item_id, user_id, kind, field, old_value, new_value)
+		VALUES($1, $2, $3, $4, $5, $6)`
+
+	_, err := tx.Exec(ctx, query, itemId, userId, kind, field, oldValue, newValue)
+
+	return err
+}
+
+// itemStateColumns are columns of an item whose changes are recorded field by field,
+// followed by the version of the item.
+const itemStateColumns = `title, description, due_at, remind_at, priority, list_id, position, rrule, assignee_id,
+	version`
+
+type itemState struct {
+	title       string
+	description string
+	dueAt       *time.Time
+	remindAt    *time.Time
+	priority    int
+	listId      *int64
+	position    int
+	rrule       string
+	assigneeId  *int64
+	version     int64
+}
+
+func scanItemState(row pgx5.Row) (itemState, error) {
+	var st itemState
+
+	err := row.Scan(
+		&st.title,
+		&st.description,
+		&st.dueAt,
+		&st.remindAt,
+		&st.priority,
+		&st.listId,
+		&st.position,
+		&st.rrule,
+		&st.assigneeId,
+		&st.version,
+	)
+
+	return st, err
+}
+
+// saveItemChanges records an updated event for every field that differs between the states.
+func saveItemChanges(ctx context.Context, tx pgx5.Tx, itemId int64, userId int64, old, cur itemState) error {
+	changes := []struct {
+		field    string
+		old, cur *string
+	}{
+		{"title", textValue(old.title), textValue(cur.title)},
+		{"description", textValue(old.description), textValue(cur.description)},
+		{"due_at", timeValue(old.dueAt), timeValue(cur.dueAt)},
+		{"remind_at", timeValue(old.remindAt), timeValue(cur.remindAt)},
+		{"priority", textValue(strconv.Itoa(old.priority)), textValue(strconv.Itoa(cur.priority))},
+		{"list_id", idValue(old.listId), idValue(cur.listId)},
+		{"position", textValue(strconv.Itoa(old.position)), textValue(strconv.Itoa(cur.position))},
+		{"rrule", textValue(old.rrule), textValue(cur.rrule)},
+		{"assignee_id", idValue(old.assigneeId), idValue(cur.assigneeId)},
+	}
+
+	for _, c := range changes {
+		if sameValue(c.old, c.cur) {
+			continue
+		}
+
+		if err := saveItemEvent(ctx, tx, itemId, userId, models.ItemEventUpdated, c.field, c.old, c.cur); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func textValue(s string) *string {
+	return &s
+}
+
+func timeValue(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+
+	return textValue(t.UTC().Format(time.RFC3339))
+}
+
+func idValue(id *int64) *string {
+	if id == nil {
+		return nil
+	}
+
+	return textValue(strconv.FormatInt(*id, 10))
+}
+
+func sameValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
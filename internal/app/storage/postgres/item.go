@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/Muaz717/todo-app/internal/app/storage"
 	"github.com/Muaz717/todo-app/internal/domain/models"
@@ -11,20 +13,68 @@ import (
 	pgx5 "github.com/jackc/pgx/v5"
 )
 
+const itemColumns = `id, title, description, done, completed_at, due_at, remind_at, priority, list_id,
+	(SELECT COALESCE(array_agg(t.name ORDER BY t.name), '{}')
+		FROM item_tags it JOIN tags t ON t.id = it.tag_id
+		WHERE it.item_id = items.id) AS tags,
+	parent_id, position, rrule, series_id, workspace_id, assignee_id,
+	(SELECT count(*) FROM comments c WHERE c.item_id = items.id) AS comment_count, deleted_at, version, user_id`
+
 func (s *Storage) SaveItem(
 	ctx context.Context,
 	userId int64,
-	title string,
-	description string,
+	input models.CreateItemInput,
 ) (int64, error) {
 	const op = "postgres.SaveItem"
 
-	query := `INSERT INTO items(title, description, user_id) VALUES($1, $2, $3) RETURNING id`
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
 
-	row := s.db.QueryRow(ctx, query, title, description, userId)
+	workspaceId, err := itemWorkspace(ctx, tx, userId, input)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if input.AssigneeId != nil {
+		if err := lockWorkspaceMember(ctx, tx, workspaceId, *input.AssigneeId); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// subtasks inherit the list of their parent and go last among siblings
+	query := `INSERT INTO items(title, description, due_at, priority, list_id, parent_id, position, rrule, remind_at, user_id,
+			workspace_id, assignee_id)
+		VALUES(
+			$1, $2, $3, $4,
+			COALESCE($5, (SELECT list_id FROM items WHERE id = $6)),
+			$6,
+			CASE WHEN $6::bigint IS NULL THEN 0
+				ELSE (SELECT COALESCE(MAX(position) + 1, 0) FROM items WHERE parent_id = $6)
+			END,
+			$7, $8, $9, $10, $11
+		) RETURNING id`
+
+	row := tx.QueryRow(
+		ctx,
+		query,
+		input.Title,
+		input.Description,
+		input.DueAt,
+		input.Priority,
+		input.ListId,
+		input.ParentId,
+		input.RRule,
+		input.RemindAt,
+		userId,
+		workspaceId,
+		input.AssigneeId,
+	)
 
 	var itemId int64
-	err := row.Scan(&itemId)
+	err = row.Scan(&itemId)
 	if err != nil {
 		if pgErr, ok := err.(*pgx.PgError); ok {
 			return 0, fmt.Errorf("%s: SQL Error: %s, Detail: %s, Where: %s", op, pgErr.Message, pgErr.Detail, pgErr.Where)
@@ -32,21 +82,934 @@ func (s *Storage) SaveItem(
 		return 0, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return itemId, err
+	if err := saveItemEvent(ctx, tx, itemId, userId, models.ItemEventCreated, "", nil, nil); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return itemId, nil
+}
+
+// itemWorkspace returns the workspace a new item goes to: the one of its parent
+// or list and otherwise the one asked for or the personal workspace of the user.
+// The parent, the list and the workspace asked for must not disagree. The list,
+// the parent and the membership of the user stay locked until tx ends.
+func itemWorkspace(ctx context.Context, tx pgx5.Tx, userId int64, input models.CreateItemInput) (int64, error) {
+	var workspaces []int64
+
+	if input.ListId != nil {
+		id, err := lockListEditor(ctx, tx, userId, *input.ListId)
+		if err != nil {
+			return 0, err
+		}
+		workspaces = append(workspaces, id)
+	}
+
+	if input.ParentId != nil {
+		query := `SELECT workspace_id FROM items WHERE id = $1 AND ` + canEditItem("$2") + ` FOR SHARE`
+
+		var id int64
+
+		if err := tx.QueryRow(ctx, query, *input.ParentId, userId).Scan(&id); err != nil {
+			if errors.Is(err, pgx5.ErrNoRows) {
+				return 0, storage.ErrItemNotFound
+			}
+			return 0, err
+		}
+		workspaces = append(workspaces, id)
+	}
+
+	if input.WorkspaceId != nil {
+		if len(workspaces) == 0 {
+			if err := lockWorkspaceEditor(ctx, tx, userId, *input.WorkspaceId); err != nil {
+				return 0, err
+			}
+		}
+		workspaces = append(workspaces, *input.WorkspaceId)
+	}
+
+	if len(workspaces) == 0 {
+		var personalId int64
+
+		if err := tx.QueryRow(ctx, `SELECT `+personalWorkspace("$1"), userId).Scan(&personalId); err != nil {
+			return 0, err
+		}
+
+		return personalId, nil
+	}
+
+	for _, id := range workspaces[1:] {
+		if id != workspaces[0] {
+			return 0, storage.ErrWorkspaceMismatch
+		}
+	}
+
+	return workspaces[0], nil
+}
+
+// itemSort describes how items are ordered by a sort key, cast is the sql type
+// the cursor value is converted to, empty for sorting by id only.
+type itemSort struct {
+	expr string
+	cast string
+}
+
+var itemSorts = map[string]itemSort{
+	models.ItemSortCreated:  {expr: "id"},
+	models.ItemSortDueAt:    {expr: "COALESCE(due_at, 'infinity'::timestamptz)", cast: "timestamptz"},
+	models.ItemSortPriority: {expr: "priority", cast: "smallint"},
+	models.ItemSortTitle:    {expr: "title", cast: "text"},
 }
 
-func (s *Storage) AllItems(ctx context.Context, userId int64) ([]models.Item, error) {
+func (s *Storage) AllItems(ctx context.Context, userId int64, filter models.ItemFilter) ([]models.Item, error) {
 	const op = "postgres.AllItems"
 
-	query := `SELECT id, title, description FROM items WHERE user_id = $1`
+	sort, ok := itemSorts[filter.Sort]
+	if !ok {
+		sort = itemSorts[models.ItemSortCreated]
+	}
 
-	rows, err := s.db.Query(ctx, query, userId)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	args := []any{userId}
+	arg := func(v any) string {
+		args = append(args, v)
+
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// subtasks shared on their own are listed along with top level items
+	where := []string{
+		canViewItem("$1"),
+		`(parent_id IS NULL OR NOT ` + isMember("items.workspace_id", "$1") + ` AND EXISTS (SELECT 1 FROM shares s
+			WHERE s.item_id = items.id AND s.user_id = $1 AND s.status = 'accepted'))`,
+	}
+
+	if filter.Done != nil {
+		where = append(where, "done = "+arg(*filter.Done))
+	}
+	if filter.DueFrom != nil {
+		where = append(where, "due_at >= "+arg(*filter.DueFrom))
+	}
+	if filter.DueTo != nil {
+		where = append(where, "due_at < "+arg(*filter.DueTo))
+	}
+	if filter.Priority != nil {
+		where = append(where, "priority = "+arg(*filter.Priority))
+	}
+	if filter.Tag != "" {
+		where = append(where, `EXISTS (SELECT 1 FROM item_tags it JOIN tags t ON t.id = it.tag_id
+			WHERE it.item_id = items.id AND lower(t.name) = lower(`+arg(filter.Tag)+`))`)
+	}
+	if filter.AssigneeId != nil {
+		where = append(where, "assignee_id = "+arg(*filter.AssigneeId))
+	}
+	if filter.WorkspaceId != nil {
+		where = append(where, "workspace_id = "+arg(*filter.WorkspaceId))
+	}
+	if filter.Query != "" {
+		pattern := arg("%" + likeEscaper.Replace(filter.Query) + "%")
+		where = append(where, "(title ILIKE "+pattern+" OR description ILIKE "+pattern+")")
+	}
+
+	cmp, dir := ">", "ASC"
+	if filter.Desc {
+		cmp, dir = "<", "DESC"
+	}
+
+	if filter.After != nil {
+		if sort.cast == "" {
+			where = append(where, "id "+cmp+" "+arg(filter.After.Id))
+		} else {
+			where = append(where, fmt.Sprintf(
+				"(%s, id) %s (%s::%s, %s)",
+				sort.expr, cmp, arg(filter.After.Value), sort.cast, arg(filter.After.Id),
+			))
 		}
+	}
+
+	query := `SELECT ` + itemColumns + `, ` + itemSharedAs("$1") + ` FROM items
+		WHERE ` + strings.Join(where, " AND ")
+
+	if sort.cast == "" {
+		query += ` ORDER BY id ` + dir
+	} else {
+		query += ` ORDER BY ` + sort.expr + ` ` + dir + `, id ` + dir
+	}
+
+	if filter.Limit > 0 {
+		query += ` LIMIT ` + arg(filter.Limit)
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Item])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (s *Storage) ItemsByList(ctx context.Context, userId int64, listId int64) ([]models.Item, error) {
+	const op = "postgres.ItemsByList"
+
+	query := `SELECT ` + itemColumns + `, ` + itemSharedAs("$1") + ` FROM items
+		WHERE list_id = $2 AND parent_id IS NULL AND ` + canViewItem("$1") + `
+		ORDER BY position, id`
+
+	rows, err := s.db.Query(ctx, query, userId, listId)
+	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Item])
 }
+
+func (s *Storage) Item(ctx context.Context, userId int64, itemId int64) (models.Item, error) {
+	const op = "postgres.Item"
+
+	query := `SELECT ` + itemColumns + `, ` + itemSharedAs("$2") + ` FROM items
+		WHERE id = $1 AND ` + canViewItem("$2")
+
+	rows, err := s.db.Query(ctx, query, itemId, userId)
+	if err != nil {
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	item, err := pgx5.CollectExactlyOneRow(rows, pgx5.RowToStructByName[models.Item])
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.Item{}, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+		return models.Item{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return item, nil
+}
+
+// Subtasks returns all descendants of the item ordered by position, subtasks
+// are seen by everyone who can see the item. Subtasks in the trash are left out.
+func (s *Storage) Subtasks(ctx context.Context, userId int64, itemId int64) ([]models.Item, error) {
+	const op = "postgres.Subtasks"
+
+	query := `WITH RECURSIVE tree(id) AS (
+			SELECT id FROM items WHERE parent_id = $1 AND deleted_at IS NULL
+				AND EXISTS (SELECT 1 FROM items WHERE id = $1 AND ` + canViewItem("$2") + `)
+			UNION ALL
+			SELECT i.id FROM items i JOIN tree t ON i.parent_id = t.id WHERE i.deleted_at IS NULL
+		)
+		SELECT ` + itemColumns + `, ` + itemSharedAs("$2") + ` FROM items
+		WHERE id IN (SELECT id FROM tree)
+		ORDER BY position, id`
+
+	rows, err := s.db.Query(ctx, query, itemId, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Item])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// ItemDepth returns how deep the item is nested, top level items have depth 1.
+func (s *Storage) ItemDepth(ctx context.Context, userId int64, itemId int64) (int, error) {
+	const op = "postgres.ItemDepth"
+
+	query := `WITH RECURSIVE up(id, parent_id, depth) AS (
+			SELECT id, parent_id, 1 FROM items WHERE id = $1 AND ` + canViewItem("$2") + `
+			UNION ALL
+			SELECT i.id, i.parent_id, up.depth + 1 FROM items i JOIN up ON i.id = up.parent_id
+		)
+		SELECT MAX(depth) FROM up`
+
+	var depth *int
+
+	if err := s.db.QueryRow(ctx, query, itemId, userId).Scan(&depth); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if depth == nil {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	return *depth, nil
+}
+
+// UpdateItem changes the item and returns its new version, storage.ErrVersionMismatch
+// is returned when the version of the input is set and the item has moved past it.
+// Subtasks follow the item into the list it is moved to.
+func (s *Storage) UpdateItem(
+	ctx context.Context,
+	userId int64,
+	itemId int64,
+	input models.UpdateItemInput,
+) (int64, error) {
+	const op = "postgres.UpdateItem"
+
+	if input.ListId != nil && !input.ClearList {
+		if err := s.checkItemList(ctx, userId, itemId, *input.ListId); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if input.AssigneeId != nil && !input.ClearAssignee {
+		if err := s.checkItemAssignee(ctx, itemId, *input.AssigneeId); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT ` + itemStateColumns + ` FROM items WHERE id = $1 AND ` + canEditItem("$2") + ` FOR UPDATE`
+
+	old, err := scanItemState(tx.QueryRow(ctx, query, itemId, userId))
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if input.Version != 0 && input.Version != old.version {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrVersionMismatch)
+	}
+
+	query = `UPDATE items
+		SET title = COALESCE($1, title),
+			description = COALESCE($2, description),
+			due_at = CASE WHEN $3 THEN NULL ELSE COALESCE($4, due_at) END,
+			priority = COALESCE($5, priority),
+			list_id = CASE WHEN $15 THEN NULL ELSE COALESCE($6, list_id) END,
+			position = COALESCE($7, position),
+			rrule = COALESCE($8, rrule),
+			remind_at = CASE WHEN $9 THEN NULL ELSE COALESCE($10, remind_at) END,
+			reminded_at = CASE WHEN $9 OR $10::timestamptz IS NOT NULL THEN NULL ELSE reminded_at END,
+			assignee_id = CASE WHEN $13 THEN NULL ELSE COALESCE($14, assignee_id) END,
+			version = version + 1
+		WHERE id = $11 AND ` + canEditItem("$12") + `
+		RETURNING ` + itemStateColumns
+
+	row := tx.QueryRow(
+		ctx,
+		query,
+		input.Title,
+		input.Description,
+		input.ClearDueAt,
+		input.DueAt,
+		input.Priority,
+		input.ListId,
+		input.Position,
+		input.RRule,
+		input.ClearRemindAt,
+		input.RemindAt,
+		itemId,
+		userId,
+		input.ClearAssignee,
+		input.AssigneeId,
+		input.ClearList,
+	)
+
+	cur, err := scanItemState(row)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveItemChanges(ctx, tx, itemId, userId, old, cur); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !sameValue(idValue(old.listId), idValue(cur.listId)) {
+		if err := moveSubtasks(ctx, tx, userId, itemId, cur.listId); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cur.version, nil
+}
+
+// moveSubtasks puts subtasks of the item at any depth into the list the item was
+// moved to, nil takes them out of any list, and records the change of every subtask.
+func moveSubtasks(ctx context.Context, tx pgx5.Tx, userId int64, itemId int64, listId *int64) error {
+	query := `WITH RECURSIVE tree(id, list_id) AS (
+			SELECT id, list_id FROM items WHERE parent_id = $1
+			UNION ALL
+			SELECT i.id, i.list_id FROM items i JOIN tree t ON i.parent_id = t.id
+		)
+		UPDATE items i
+		SET list_id = $2, version = i.version + 1
+		FROM tree t
+		WHERE i.id = t.id AND i.list_id IS DISTINCT FROM $2
+		RETURNING i.id, t.list_id`
+
+	rows, err := tx.Query(ctx, query, itemId, listId)
+	if err != nil {
+		return err
+	}
+
+	type moved struct {
+		id     int64
+		listId *int64
+	}
+
+	var subtasks []moved
+
+	for rows.Next() {
+		var m moved
+
+		if err := rows.Scan(&m.id, &m.listId); err != nil {
+			rows.Close()
+			return err
+		}
+
+		subtasks = append(subtasks, m)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, m := range subtasks {
+		err := saveItemEvent(ctx, tx, m.id, userId, models.ItemEventUpdated, "list_id", idValue(m.listId), idValue(listId))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setItemDoneQuery changes completion state of the item $2 the user $3 can edit
+// to $1, with $4 set its subtasks at any depth are changed too. It returns ids
+// of the matched items along with whether their state actually changed.
+var setItemDoneQuery = `WITH RECURSIVE tree(id) AS (
+		SELECT id FROM items WHERE id = $2 AND ` + canEditItem("$3") + `
+		UNION ALL
+		SELECT i.id FROM items i JOIN tree t ON i.parent_id = t.id WHERE $4 AND i.deleted_at IS NULL
+	),
+	changed AS (
+		SELECT id FROM items WHERE id IN (SELECT id FROM tree) AND done <> $1
+	)
+	UPDATE items
+	SET completed_at = CASE
+			WHEN NOT $1 THEN NULL
+			WHEN done THEN completed_at
+			ELSE now()
+		END,
+		done = $1,
+		version = version + CASE WHEN id IN (SELECT id FROM changed) THEN 1 ELSE 0 END
+	WHERE id IN (SELECT id FROM tree)
+	RETURNING id, id IN (SELECT id FROM changed)`
+
+// setItemDone runs setItemDoneQuery in tx and records completion of every item
+// that changed state, it returns how many items were matched.
+func setItemDone(
+	ctx context.Context,
+	tx pgx5.Tx,
+	userId int64,
+	itemId int64,
+	done bool,
+	cascade bool,
+) (int, error) {
+	rows, err := tx.Query(ctx, setItemDoneQuery, done, itemId, userId, cascade)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		id      int64
+		changed bool
+		matched int
+		ids     []int64
+	)
+
+	_, err = pgx5.ForEachRow(rows, []any{&id, &changed}, func() error {
+		matched++
+		if changed {
+			ids = append(ids, id)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	kind := models.ItemEventCompleted
+	if !done {
+		kind = models.ItemEventReopened
+	}
+
+	for _, id := range ids {
+		if err := saveItemEvent(ctx, tx, id, userId, kind, "", nil, nil); err != nil {
+			return 0, err
+		}
+	}
+
+	return matched, nil
+}
+
+// SetItemDone changes completion state of the item at version, zero skips
+// the check. With cascade set its subtasks at any depth are changed too.
+func (s *Storage) SetItemDone(
+	ctx context.Context,
+	userId int64,
+	itemId int64,
+	done bool,
+	cascade bool,
+	version int64,
+) error {
+	const op = "postgres.SetItemDone"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockItemVersion(ctx, tx, userId, itemId, canEditItem("$2"), version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	matched, err := setItemDone(ctx, tx, userId, itemId, done, cascade)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if matched == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CompleteOccurrence completes a recurring item and, unless it was done already,
+// spawns the next occurrence of its series due at nextDueAt with the rule nextRRule.
+// The completed item hands its rule over to the new one. Nil nextDueAt ends the series.
+// It returns id of the spawned item or zero when nothing was spawned. The item
+// must be at version, zero skips the check.
+func (s *Storage) CompleteOccurrence(
+	ctx context.Context,
+	userId int64,
+	itemId int64,
+	cascade bool,
+	nextDueAt *time.Time,
+	nextRRule string,
+	version int64,
+) (int64, error) {
+	const op = "postgres.CompleteOccurrence"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		done    bool
+		rule    string
+		current int64
+	)
+
+	// the row lock makes concurrent completions spawn a single occurrence
+	query := `SELECT done, rrule, version FROM items WHERE id = $1 AND ` + canEditItem("$2") + ` FOR UPDATE`
+
+	err = tx.QueryRow(ctx, query, itemId, userId).Scan(&done, &rule, &current)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if version != 0 && version != current {
+		return 0, fmt.Errorf("%s: %w", op, storage.ErrVersionMismatch)
+	}
+
+	if _, err := setItemDone(ctx, tx, userId, itemId, true, cascade); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if done || nextDueAt == nil {
+		if err := tx.Commit(ctx); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return 0, nil
+	}
+
+	// the reminder keeps its distance to the due date, the occurrence
+	// belongs to the creator of the series whoever completed it
+	query = `INSERT INTO items(title, description, due_at, priority, list_id, parent_id, position, rrule, series_id, remind_at, user_id,
+			workspace_id, assignee_id)
+		SELECT title, description, $2, priority, list_id, parent_id, position, $3, COALESCE(series_id, id),
+			$2::timestamptz - (due_at - remind_at), user_id, workspace_id, assignee_id
+		FROM items WHERE id = $1
+		RETURNING id`
+
+	var nextId int64
+
+	if err := tx.QueryRow(ctx, query, itemId, nextDueAt, nextRRule).Scan(&nextId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `INSERT INTO item_tags(item_id, tag_id) SELECT $1, tag_id FROM item_tags WHERE item_id = $2`
+
+	if _, err := tx.Exec(ctx, query, nextId, itemId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `UPDATE items SET rrule = '', series_id = COALESCE(series_id, id), version = version + 1
+		WHERE id = $1`
+
+	if _, err := tx.Exec(ctx, query, itemId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveItemEvent(ctx, tx, nextId, userId, models.ItemEventCreated, "", nil, nil); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveItemEvent(ctx, tx, itemId, userId, models.ItemEventUpdated, "rrule", &rule, textValue("")); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nextId, nil
+}
+
+// DeleteItem moves the item at version along with its subtasks to the trash,
+// subtasks trashed before stay there on their own. Zero version skips the check.
+func (s *Storage) DeleteItem(ctx context.Context, userId int64, itemId int64, version int64) error {
+	const op = "postgres.DeleteItem"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockItemVersion(ctx, tx, userId, itemId, canEditItem("$2"), version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// now() is fixed within the transaction, the whole tree shares deleted_at
+	query := `WITH RECURSIVE tree(id) AS (
+			SELECT id FROM items WHERE id = $1 AND ` + canEditItem("$2") + `
+			UNION ALL
+			SELECT i.id FROM items i JOIN tree t ON i.parent_id = t.id WHERE i.deleted_at IS NULL
+		)
+		UPDATE items SET deleted_at = now(), version = version + 1 WHERE id IN (SELECT id FROM tree)`
+
+	tag, err := tx.Exec(ctx, query, itemId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	if err := saveItemEvent(ctx, tx, itemId, userId, models.ItemEventDeleted, "", nil, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// inTrash matches rows of items deleted on their own rather than along with their parent.
+const inTrash = `items.deleted_at IS NOT NULL AND NOT EXISTS (SELECT 1 FROM items p
+	WHERE p.id = items.parent_id AND p.deleted_at IS NOT NULL)`
+
+// TrashedItems returns items in the trash the user can restore, recently deleted first.
+func (s *Storage) TrashedItems(ctx context.Context, userId int64) ([]models.Item, error) {
+	const op = "postgres.TrashedItems"
+
+	query := `SELECT ` + itemColumns + `, ` + itemSharedAs("$1") + ` FROM items
+		WHERE ` + inTrash + ` AND ` + itemEditor("$1") + `
+		ORDER BY deleted_at DESC, id DESC`
+
+	rows, err := s.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	items, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Item])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return items, nil
+}
+
+// RestoreItem takes the item at version out of the trash along with the subtasks
+// deleted with it, zero version skips the check.
+func (s *Storage) RestoreItem(ctx context.Context, userId int64, itemId int64, version int64) error {
+	const op = "postgres.RestoreItem"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockItemVersion(ctx, tx, userId, itemId, inTrash+` AND `+itemEditor("$2"), version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `WITH RECURSIVE tree(id, deleted_at) AS (
+			SELECT id, deleted_at FROM items WHERE id = $1 AND ` + inTrash + ` AND ` + itemEditor("$2") + `
+			UNION ALL
+			SELECT i.id, i.deleted_at FROM items i JOIN tree t ON i.parent_id = t.id AND i.deleted_at = t.deleted_at
+		)
+		UPDATE items SET deleted_at = NULL, version = version + 1 WHERE id IN (SELECT id FROM tree)`
+
+	tag, err := tx.Exec(ctx, query, itemId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+	}
+
+	if err := saveItemEvent(ctx, tx, itemId, userId, models.ItemEventRestored, "", nil, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PurgeItems deletes for good items moved to the trash before the time together
+// with their subtasks, the history of the items is kept. It returns how many were deleted.
+func (s *Storage) PurgeItems(ctx context.Context, before time.Time) (int64, error) {
+	const op = "postgres.PurgeItems"
+
+	tag, err := s.db.Exec(ctx, `DELETE FROM items WHERE deleted_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// AttachTag tags the item at version with the tag of the user, zero version skips the check.
+func (s *Storage) AttachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	const op = "postgres.AttachTag"
+
+	if err := s.checkItemAccess(ctx, userId, itemId, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkTagOwner(ctx, userId, tagId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `INSERT INTO item_tags(item_id, tag_id) VALUES($1, $2) ON CONFLICT DO NOTHING
+		RETURNING (SELECT name FROM tags WHERE id = tag_id)`
+
+	return s.tagItem(ctx, op, userId, itemId, tagId, version, query, models.ItemEventTagAdded)
+}
+
+// DetachTag removes the tag from the item at version, zero version skips the check.
+func (s *Storage) DetachTag(ctx context.Context, userId int64, itemId int64, tagId int64, version int64) error {
+	const op = "postgres.DetachTag"
+
+	if err := s.checkItemAccess(ctx, userId, itemId, true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `DELETE FROM item_tags WHERE item_id = $1 AND tag_id = $2
+		RETURNING (SELECT name FROM tags WHERE id = tag_id)`
+
+	return s.tagItem(ctx, op, userId, itemId, tagId, version, query, models.ItemEventTagRemoved)
+}
+
+// tagItem runs the query attaching or detaching the tag and returning its name,
+// the event of the kind is recorded unless the item was left as it was.
+func (s *Storage) tagItem(
+	ctx context.Context,
+	op string,
+	userId int64,
+	itemId int64,
+	tagId int64,
+	version int64,
+	query string,
+	kind string,
+) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockItemVersion(ctx, tx, userId, itemId, canEditItem("$2"), version); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var name string
+
+	err = tx.QueryRow(ctx, query, itemId, tagId).Scan(&name)
+	if errors.Is(err, pgx5.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	oldValue, newValue := &name, (*string)(nil)
+	if kind == models.ItemEventTagAdded {
+		oldValue, newValue = nil, &name
+	}
+
+	if err := saveItemEvent(ctx, tx, itemId, userId, kind, "tags", oldValue, newValue); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// tags are a part of the item, changing them makes a new version
+	if _, err := tx.Exec(ctx, `UPDATE items SET version = version + 1 WHERE id = $1`, itemId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// lockItemVersion locks the item matching access for the user as $2 until the end
+// of tx. It returns storage.ErrItemNotFound when there is no such item and
+// storage.ErrVersionMismatch unless the item is at version, zero skips the check.
+func lockItemVersion(ctx context.Context, tx pgx5.Tx, userId int64, itemId int64, access string, version int64) error {
+	query := `SELECT version FROM items WHERE id = $1 AND ` + access + ` FOR UPDATE`
+
+	var current int64
+
+	if err := tx.QueryRow(ctx, query, itemId, userId).Scan(&current); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return storage.ErrItemNotFound
+		}
+		return err
+	}
+
+	if version != 0 && version != current {
+		return storage.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// checkWorkspaceItem returns storage.ErrItemNotFound unless the item belongs
+// to a workspace the user can edit, shares of the item do not count.
+func (s *Storage) checkWorkspaceItem(ctx context.Context, userId int64, itemId int64) error {
+	query := `SELECT EXISTS(SELECT 1 FROM items WHERE id = $1 AND deleted_at IS NULL
+		AND ` + isEditor("items.workspace_id", "$2") + `)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, itemId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// checkItemAccess returns storage.ErrItemNotFound unless the user can see
+// the item or, with edit set, change it.
+func (s *Storage) checkItemAccess(ctx context.Context, userId int64, itemId int64, edit bool) error {
+	access := canViewItem("$2")
+	if edit {
+		access = canEditItem("$2")
+	}
+
+	query := `SELECT EXISTS(SELECT 1 FROM items WHERE id = $1 AND ` + access + `)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, itemId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrItemNotFound
+	}
+
+	return nil
+}
+
+// checkItemList returns storage.ErrListNotFound unless the user can move the item
+// to the list, items only move between lists of their workspace the user can edit.
+func (s *Storage) checkItemList(ctx context.Context, userId int64, itemId int64, listId int64) error {
+	query := `SELECT EXISTS(SELECT 1 FROM lists JOIN items ON items.workspace_id = lists.workspace_id
+		WHERE lists.id = $1 AND items.id = $2 AND ` + canEditList("$3") + `)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, listId, itemId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrListNotFound
+	}
+
+	return nil
+}
+
+// checkItemAssignee returns storage.ErrMemberNotFound unless the user is
+// a member of the workspace of the item, missing items are left to the caller.
+func (s *Storage) checkItemAssignee(ctx context.Context, itemId int64, assigneeId int64) error {
+	query := `SELECT NOT EXISTS(SELECT 1 FROM items WHERE id = $1)
+		OR EXISTS(SELECT 1 FROM items WHERE id = $1 AND ` + isMember("items.workspace_id", "$2") + `)`
+
+	var ok bool
+
+	if err := s.db.QueryRow(ctx, query, itemId, assigneeId).Scan(&ok); err != nil {
+		return err
+	}
+
+	if !ok {
+		return storage.ErrMemberNotFound
+	}
+
+	return nil
+}
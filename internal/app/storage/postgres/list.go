@@ -0,0 +1,245 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+const listColumns = `id, name, color, position, archived, workspace_id`
+
+func (s *Storage) SaveList(ctx context.Context, userId int64, input models.CreateListInput) (int64, error) {
+	const op = "postgres.SaveList"
+
+	if input.WorkspaceId != nil {
+		if err := s.checkWorkspaceEditor(ctx, userId, *input.WorkspaceId); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// lists are positioned within their workspace
+	query := `WITH w(id) AS (SELECT COALESCE($5, ` + personalWorkspace("$4") + `))
+		INSERT INTO lists(name, color, position, user_id, workspace_id)
+		SELECT $1, $2, COALESCE($3, (SELECT COALESCE(MAX(position) + 1, 0) FROM lists WHERE workspace_id = w.id)), $4, w.id
+		FROM w
+		RETURNING id`
+
+	var listId int64
+
+	err := s.db.QueryRow(ctx, query, input.Name, input.Color, input.Position, userId, input.WorkspaceId).Scan(&listId)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listId, nil
+}
+
+// AllLists returns lists of workspaces of the user and lists shared with them
+// grouped by workspace.
+func (s *Storage) AllLists(ctx context.Context, userId int64, withArchived bool) ([]models.List, error) {
+	const op = "postgres.AllLists"
+
+	query := `SELECT ` + listColumns + `, ` + listSharedAs("$1") + ` FROM lists
+		WHERE ` + canViewList("$1") + ` AND ($2 OR NOT archived)
+		ORDER BY workspace_id, position, id`
+
+	rows, err := s.db.Query(ctx, query, userId, withArchived)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	lists, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.List])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return lists, nil
+}
+
+func (s *Storage) List(ctx context.Context, userId int64, listId int64) (models.List, error) {
+	const op = "postgres.List"
+
+	query := `SELECT ` + listColumns + `, ` + listSharedAs("$2") + ` FROM lists
+		WHERE id = $1 AND ` + canViewList("$2")
+
+	rows, err := s.db.Query(ctx, query, listId, userId)
+	if err != nil {
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	list, err := pgx5.CollectExactlyOneRow(rows, pgx5.RowToStructByName[models.List])
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.List{}, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return models.List{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return list, nil
+}
+
+func (s *Storage) UpdateList(
+	ctx context.Context,
+	userId int64,
+	listId int64,
+	input models.UpdateListInput,
+) error {
+	const op = "postgres.UpdateList"
+
+	query := `UPDATE lists
+		SET name = COALESCE($1, name),
+			color = COALESCE($2, color),
+			position = COALESCE($3, position),
+			archived = COALESCE($4, archived)
+		WHERE id = $5 AND ` + canEditList("$6")
+
+	tag, err := s.db.Exec(
+		ctx,
+		query,
+		input.Name,
+		input.Color,
+		input.Position,
+		input.Archived,
+		listId,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+	}
+
+	return nil
+}
+
+// DeleteList deletes the list, its items are moved to the trash out of any list
+// rather than deleted along with it.
+func (s *Storage) DeleteList(ctx context.Context, userId int64, listId int64) error {
+	const op = "postgres.DeleteList"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the list is locked, so no item is added to it while it is being deleted
+	query := `SELECT id FROM lists WHERE id = $1 AND ` + isEditor("lists.workspace_id", "$2") + ` FOR UPDATE`
+
+	var locked int64
+
+	if err := tx.QueryRow(ctx, query, listId, userId).Scan(&locked); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// now() is fixed within the transaction, items of the list share deleted_at
+	// with their subtasks, so each tree is restored as a whole
+	query = `WITH RECURSIVE tree(id) AS (
+			SELECT id FROM items WHERE list_id = $1 AND deleted_at IS NULL
+			UNION
+			SELECT i.id FROM items i JOIN tree t ON i.parent_id = t.id WHERE i.deleted_at IS NULL
+		)
+		UPDATE items
+		SET deleted_at = now(),
+			list_id = CASE WHEN list_id = $1 THEN NULL ELSE list_id END,
+			version = version + 1
+		WHERE id IN (SELECT id FROM tree)
+		RETURNING id, parent_id`
+
+	rows, err := tx.Query(ctx, query, listId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	type trashed struct {
+		id       int64
+		parentId *int64
+	}
+
+	items, err := pgx5.CollectRows(rows, func(row pgx5.CollectableRow) (trashed, error) {
+		var t trashed
+		err := row.Scan(&t.id, &t.parentId)
+
+		return t, err
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ids := make(map[int64]bool, len(items))
+	for _, t := range items {
+		ids[t.id] = true
+	}
+
+	// only roots of the trashed trees are deleted on their own
+	for _, t := range items {
+		if t.parentId != nil && ids[*t.parentId] {
+			continue
+		}
+
+		if err := saveItemEvent(ctx, tx, t.id, userId, models.ItemEventDeleted, "", nil, nil); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	// items trashed before are detached as well, so they stay in the trash
+	query = `UPDATE items SET list_id = NULL, version = version + 1 WHERE list_id = $1`
+
+	if _, err := tx.Exec(ctx, query, listId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM lists WHERE id = $1`, listId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkWorkspaceList returns storage.ErrListNotFound unless the list belongs
+// to a workspace the user can edit, shares of the list do not count.
+func (s *Storage) checkWorkspaceList(ctx context.Context, userId int64, listId int64) error {
+	query := `SELECT EXISTS(SELECT 1 FROM lists WHERE id = $1 AND ` + isEditor("lists.workspace_id", "$2") + `)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, listId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrListNotFound
+	}
+
+	return nil
+}
+
+// lockListEditor returns the workspace of the list the user can add items to and keeps
+// the list locked until tx ends, storage.ErrListNotFound is returned for other lists.
+func lockListEditor(ctx context.Context, tx pgx5.Tx, userId int64, listId int64) (int64, error) {
+	query := `SELECT workspace_id FROM lists WHERE id = $1 AND ` + canEditList("$2") + ` FOR SHARE`
+
+	var workspaceId int64
+
+	if err := tx.QueryRow(ctx, query, listId, userId).Scan(&workspaceId); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, storage.ErrListNotFound
+		}
+		return 0, err
+	}
+
+	return workspaceId, nil
+}
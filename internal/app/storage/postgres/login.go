@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+// LoginLockedUntil returns the latest lockout of the keys, the zero time
+// when none of them is locked.
+func (s *Storage) LoginLockedUntil(ctx context.Context, keys []string) (time.Time, error) {
+	const op = "postgres.LoginLockedUntil"
+
+	query := `SELECT max(locked_until) FROM login_attempts WHERE key = ANY($1) AND locked_until > now()`
+
+	var until *time.Time
+
+	if err := s.db.QueryRow(ctx, query, keys).Scan(&until); err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if until == nil {
+		return time.Time{}, nil
+	}
+
+	return *until, nil
+}
+
+// RecordLoginFailure counts a failed attempt for the key and returns the number
+// of failures in a row, the count starts over when the previous failure
+// is older than window.
+func (s *Storage) RecordLoginFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	const op = "postgres.RecordLoginFailure"
+
+	query := `INSERT INTO login_attempts(key, failures, last_failure_at) VALUES($1, 1, now())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < now() - $2::interval THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			last_failure_at = now()
+		RETURNING failures`
+
+	var failures int
+
+	if err := s.db.QueryRow(ctx, query, key, window).Scan(&failures); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return failures, nil
+}
+
+// LockLogin locks the key out until the given time, a longer lockout
+// set concurrently by another replica is kept.
+func (s *Storage) LockLogin(ctx context.Context, key string, until time.Time) error {
+	const op = "postgres.LockLogin"
+
+	query := `UPDATE login_attempts SET locked_until = GREATEST(locked_until, $2) WHERE key = $1`
+
+	if _, err := s.db.Exec(ctx, query, key, until); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) ResetLoginFailures(ctx context.Context, key string) error {
+	const op = "postgres.ResetLoginFailures"
+
+	if _, err := s.db.Exec(ctx, `DELETE FROM login_attempts WHERE key = $1`, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveLoginEvent(ctx context.Context, event models.LoginEvent) error {
+	const op = "postgres.SaveLoginEvent"
+
+	query := `INSERT INTO login_events(email, ip, success, reason) VALUES($1, $2, $3, $4)`
+
+	if _, err := s.db.Exec(ctx, query, event.Email, event.IP, event.Success, event.Reason); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// Notifications returns notifications of the user, newest first.
+func (s *Storage) Notifications(ctx context.Context, userId int64, unread bool) ([]models.Notification, error) {
+	const op = "postgres.Notifications"
+
+	query := `SELECT n.id, n.kind, n.actor_id, a.email AS actor_email, n.item_id, i.title AS item_title,
+			n.comment_id, n.created_at, n.read_at
+		FROM notifications n
+			JOIN users a ON a.id = n.actor_id
+			JOIN items i ON i.id = n.item_id
+		WHERE n.user_id = $1 AND (NOT $2 OR n.read_at IS NULL)
+		ORDER BY n.id DESC`
+
+	rows, err := s.db.Query(ctx, query, userId, unread)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	notifications, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Notification])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+// ReadNotification marks the notification of the user read.
+func (s *Storage) ReadNotification(ctx context.Context, userId int64, notificationId int64) error {
+	const op = "postgres.ReadNotification"
+
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, now()) WHERE id = $1 AND user_id = $2`
+
+	tag, err := s.db.Exec(ctx, query, notificationId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrNotificationNotFound)
+	}
+
+	return nil
+}
+
+// ReadAllNotifications marks every unread notification of the user read.
+func (s *Storage) ReadAllNotifications(ctx context.Context, userId int64) error {
+	const op = "postgres.ReadAllNotifications"
+
+	query := `UPDATE notifications SET read_at = now() WHERE user_id = $1 AND read_at IS NULL`
+
+	if _, err := s.db.Exec(ctx, query, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) SavePasswordReset(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error {
+	const op = "postgres.SavePasswordReset"
+
+	query := `INSERT INTO password_resets(user_id, token_hash, expires_at) VALUES($1, $2, $3)`
+
+	if _, err := s.db.Exec(ctx, query, userId, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ResetPassword spends the reset token with tokenHash and sets the new password
+// of its user, lifting a forced reset. Every other pending reset, session and
+// API token of the user are revoked. Used and expired tokens are not found.
+func (s *Storage) ResetPassword(ctx context.Context, tokenHash string, passHash []byte) (int64, error) {
+	const op = "postgres.ResetPassword"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE password_resets SET used_at = now()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id`
+
+	var userId int64
+
+	if err := tx.QueryRow(ctx, query, tokenHash).Scan(&userId); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE users SET pass_hash = $1, password_reset_required = false WHERE id = $2`, passHash, userId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `UPDATE password_resets SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`
+
+	if _, err := tx.Exec(ctx, query, userId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := revokeCredentials(ctx, tx, userId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userId, nil
+}
+
+func (s *Storage) UpdatePasswordHash(ctx context.Context, userId int64, passHash []byte) error {
+	const op = "postgres.UpdatePasswordHash"
+
+	tag, err := s.db.Exec(ctx, `UPDATE users SET pass_hash = $1 WHERE id = $2`, passHash, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// ChangePassword sets the new password of the user and revokes every session,
+// API token and pending two-factor challenge of the user.
+func (s *Storage) ChangePassword(ctx context.Context, userId int64, passHash []byte) error {
+	const op = "postgres.ChangePassword"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE users SET pass_hash = $1 WHERE id = $2`, passHash, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if err := revokeCredentials(ctx, tx, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// revokeCredentials revokes refresh tokens, API tokens and pending two-factor
+// challenges of the user, so nothing issued before a password change keeps working.
+func revokeCredentials(ctx context.Context, tx pgx5.Tx, userId int64) error {
+	queries := []string{
+		`UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`,
+		`UPDATE api_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`,
+		`UPDATE two_factor_challenges SET used_at = now() WHERE user_id = $1 AND used_at IS NULL`,
+	}
+
+	for _, query := range queries {
+		if _, err := tx.Exec(ctx, query, userId); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
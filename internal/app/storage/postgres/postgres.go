@@ -2,9 +2,11 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/Muaz717/todo-app/internal/config"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -36,3 +38,10 @@ func New(ctx context.Context, cfg config.DB) (*Storage, error) {
 
 	return &Storage{db: db}, nil
 }
+
+// isUniqueViolation reports whether err is caused by a unique constraint.
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// ProcessReminders locks up to limit pending reminders due by now and passes
+// them to notify one by one. Delivered reminders are marked sent, failed ones
+// stay pending and are retried later. Rows locked by other replicas are skipped,
+// so every reminder is delivered by a single worker.
+// It returns the number of delivered reminders.
+func (s *Storage) ProcessReminders(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	notify func(ctx context.Context, reminder models.Reminder) error,
+) (int, error) {
+	const op = "postgres.ProcessReminders"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// reminders of assigned items go to the assignee
+	query := `SELECT i.id AS item_id, u.id AS user_id, u.email, i.title, i.due_at, i.remind_at
+		FROM items i JOIN users u ON u.id = COALESCE(i.assignee_id, i.user_id)
+		WHERE i.remind_at <= $1 AND i.reminded_at IS NULL AND NOT i.done AND i.deleted_at IS NULL
+		ORDER BY i.remind_at
+		LIMIT $2
+		FOR UPDATE OF i SKIP LOCKED`
+
+	rows, err := tx.Query(ctx, query, now, limit)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reminders, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Reminder])
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sent := make([]int64, 0, len(reminders))
+
+	for _, reminder := range reminders {
+		if err := notify(ctx, reminder); err != nil {
+			continue
+		}
+
+		sent = append(sent, reminder.ItemId)
+	}
+
+	if len(sent) > 0 {
+		query = `UPDATE items SET reminded_at = $1 WHERE id = ANY($2)`
+
+		if _, err := tx.Exec(ctx, query, now, sent); err != nil {
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return len(sent), nil
+}
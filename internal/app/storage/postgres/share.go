@@ -0,0 +1,264 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// itemShares selects roles of the accepted shares giving the user
+// placeholder access to the row of items in the outer query: shares
+// of the item itself, of any item above it and of its list.
+func itemShares(user string) string {
+	return `SELECT s.role FROM shares s
+		WHERE s.user_id = ` + user + ` AND s.status = 'accepted'
+			AND (s.list_id = items.list_id OR s.item_id IN (
+				WITH RECURSIVE up(id, parent_id) AS (
+					SELECT items.id, items.parent_id
+					UNION ALL
+					SELECT p.id, p.parent_id FROM items p JOIN up ON p.id = up.parent_id
+				)
+				SELECT id FROM up
+			))`
+}
+
+// canViewItem matches rows of items in workspaces of the user
+// and items the user has an accepted share of, items in the trash are left out.
+func canViewItem(user string) string {
+	return `(items.deleted_at IS NULL AND (` + isMember("items.workspace_id", user) + ` OR EXISTS (` + itemShares(user) + `)))`
+}
+
+// canEditItem matches rows of items the user is an editor of, items in the trash are left out.
+func canEditItem(user string) string {
+	return `(items.deleted_at IS NULL AND ` + itemEditor(user) + `)`
+}
+
+// itemEditor matches rows of items in workspaces the user is not a guest of
+// and items shared with the user as an editor, whether in the trash or not.
+func itemEditor(user string) string {
+	return `(` + isEditor("items.workspace_id", user) + ` OR EXISTS (` + itemShares(user) + ` AND s.role = 'editor'))`
+}
+
+// itemSharedAs selects the role the user has on a row of items shared with them, empty
+// for items of their workspaces. 'editor' sorts before 'viewer', so MIN picks the wider one.
+func itemSharedAs(user string) string {
+	return `CASE WHEN ` + isMember("items.workspace_id", user) + ` THEN ''
+		ELSE (SELECT COALESCE(MIN(r.role), '') FROM (` + itemShares(user) + `) r) END AS shared_as`
+}
+
+// listShares selects roles of the accepted shares of the row of lists in the outer query.
+func listShares(user string) string {
+	return `SELECT s.role FROM shares s
+		WHERE s.user_id = ` + user + ` AND s.status = 'accepted' AND s.list_id = lists.id`
+}
+
+func canViewList(user string) string {
+	return `(` + isMember("lists.workspace_id", user) + ` OR EXISTS (` + listShares(user) + `))`
+}
+
+func canEditList(user string) string {
+	return `(` + isEditor("lists.workspace_id", user) + ` OR EXISTS (` + listShares(user) + ` AND s.role = 'editor'))`
+}
+
+func listSharedAs(user string) string {
+	return `CASE WHEN ` + isMember("lists.workspace_id", user) + ` THEN ''
+		ELSE COALESCE((` + listShares(user) + `), '') END AS shared_as`
+}
+
+const shareColumns = `s.id, s.item_id, s.list_id, COALESCE(i.title, l.name) AS title,
+	s.owner_id, o.email AS owner_email, s.user_id, u.email AS user_email,
+	s.role, s.status, s.created_at, s.responded_at`
+
+const shareJoins = `FROM shares s
+	JOIN users o ON o.id = s.owner_id
+	JOIN users u ON u.id = s.user_id
+	LEFT JOIN items i ON i.id = s.item_id
+	LEFT JOIN lists l ON l.id = s.list_id`
+
+// shareUpsert ends an invitation query, inviting the user again changes the role
+// and turns a declined invitation back into a pending one.
+const shareUpsert = `DO UPDATE SET role = EXCLUDED.role,
+		status = CASE WHEN shares.status = 'declined' THEN 'pending' ELSE shares.status END,
+		responded_at = CASE WHEN shares.status = 'declined' THEN NULL ELSE shares.responded_at END
+	RETURNING id`
+
+// ShareItem invites the user to the item with the role on behalf of the owner,
+// it returns storage.ErrItemNotFound unless the owner can edit the item in its workspace.
+func (s *Storage) ShareItem(ctx context.Context, ownerId int64, itemId int64, userId int64, role string) (int64, error) {
+	const op = "postgres.ShareItem"
+
+	query := `INSERT INTO shares(item_id, owner_id, user_id, role)
+		SELECT id, $2, $3, $4 FROM items WHERE id = $1 AND deleted_at IS NULL AND ` + isEditor("items.workspace_id", "$2") + `
+		ON CONFLICT (item_id, user_id) WHERE item_id IS NOT NULL ` + shareUpsert
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	var shareId int64
+
+	err = tx.QueryRow(ctx, query, itemId, ownerId, userId, role).Scan(&shareId)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrItemNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var email string
+
+	if err := tx.QueryRow(ctx, `SELECT email FROM users WHERE id = $1`, userId).Scan(&email); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := saveItemEvent(ctx, tx, itemId, ownerId, models.ItemEventShared, email, nil, &role); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shareId, nil
+}
+
+// ShareList invites the user to the list with the role on behalf of the owner,
+// it returns storage.ErrListNotFound unless the owner can edit the list in its workspace.
+func (s *Storage) ShareList(ctx context.Context, ownerId int64, listId int64, userId int64, role string) (int64, error) {
+	const op = "postgres.ShareList"
+
+	query := `INSERT INTO shares(list_id, owner_id, user_id, role)
+		SELECT id, $2, $3, $4 FROM lists WHERE id = $1 AND ` + isEditor("lists.workspace_id", "$2") + `
+		ON CONFLICT (list_id, user_id) WHERE list_id IS NOT NULL ` + shareUpsert
+
+	var shareId int64
+
+	err := s.db.QueryRow(ctx, query, listId, ownerId, userId, role).Scan(&shareId)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrListNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shareId, nil
+}
+
+// ItemShares returns everyone the item was shared with, only editors
+// of its workspace may see them.
+func (s *Storage) ItemShares(ctx context.Context, ownerId int64, itemId int64) ([]models.Share, error) {
+	const op = "postgres.ItemShares"
+
+	if err := s.checkWorkspaceItem(ctx, ownerId, itemId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT ` + shareColumns + ` ` + shareJoins + `
+		WHERE s.item_id = $1
+		ORDER BY s.id`
+
+	rows, err := s.db.Query(ctx, query, itemId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shares, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Share])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+// ListShares returns everyone the list was shared with, only editors
+// of its workspace may see them.
+func (s *Storage) ListShares(ctx context.Context, ownerId int64, listId int64) ([]models.Share, error) {
+	const op = "postgres.ListShares"
+
+	if err := s.checkWorkspaceList(ctx, ownerId, listId); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query := `SELECT ` + shareColumns + ` ` + shareJoins + `
+		WHERE s.list_id = $1
+		ORDER BY s.id`
+
+	rows, err := s.db.Query(ctx, query, listId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shares, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Share])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+// UserShares returns shares granted to the user, empty status returns them all.
+func (s *Storage) UserShares(ctx context.Context, userId int64, status string) ([]models.Share, error) {
+	const op = "postgres.UserShares"
+
+	query := `SELECT ` + shareColumns + ` ` + shareJoins + `
+		WHERE s.user_id = $1 AND ($2 = '' OR s.status = $2)
+		ORDER BY s.id DESC`
+
+	rows, err := s.db.Query(ctx, query, userId, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	shares, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Share])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return shares, nil
+}
+
+// RespondShare accepts or declines a pending invitation of the user.
+func (s *Storage) RespondShare(ctx context.Context, userId int64, shareId int64, accept bool) error {
+	const op = "postgres.RespondShare"
+
+	query := `UPDATE shares
+		SET status = CASE WHEN $3 THEN 'accepted' ELSE 'declined' END,
+			responded_at = now()
+		WHERE id = $1 AND user_id = $2 AND status = 'pending'`
+
+	tag, err := s.db.Exec(ctx, query, shareId, userId, accept)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrShareNotFound)
+	}
+
+	return nil
+}
+
+// DeleteShare revokes the share when the user is its owner
+// and leaves it when the user is the one it was granted to.
+func (s *Storage) DeleteShare(ctx context.Context, userId int64, shareId int64) error {
+	const op = "postgres.DeleteShare"
+
+	query := `DELETE FROM shares WHERE id = $1 AND (owner_id = $2 OR user_id = $2)`
+
+	tag, err := s.db.Exec(ctx, query, shareId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrShareNotFound)
+	}
+
+	return nil
+}
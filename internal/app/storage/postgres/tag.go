@@ -0,0 +1,100 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) SaveTag(ctx context.Context, userId int64, name string) (int64, error) {
+	const op = "postgres.SaveTag"
+
+	query := `INSERT INTO tags(name, user_id) VALUES($1, $2) RETURNING id`
+
+	var tagId int64
+
+	err := s.db.QueryRow(ctx, query, name, userId).Scan(&tagId)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrTagExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tagId, nil
+}
+
+func (s *Storage) AllTags(ctx context.Context, userId int64) ([]models.Tag, error) {
+	const op = "postgres.AllTags"
+
+	query := `SELECT id, name FROM tags WHERE user_id = $1 ORDER BY lower(name)`
+
+	rows, err := s.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tags, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Tag])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tags, nil
+}
+
+func (s *Storage) UpdateTag(ctx context.Context, userId int64, tagId int64, name string) error {
+	const op = "postgres.UpdateTag"
+
+	query := `UPDATE tags SET name = $1 WHERE id = $2 AND user_id = $3`
+
+	tag, err := s.db.Exec(ctx, query, name, tagId, userId)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return fmt.Errorf("%s: %w", op, storage.ErrTagExists)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTagNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteTag(ctx context.Context, userId int64, tagId int64) error {
+	const op = "postgres.DeleteTag"
+
+	query := `DELETE FROM tags WHERE id = $1 AND user_id = $2`
+
+	tag, err := s.db.Exec(ctx, query, tagId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTagNotFound)
+	}
+
+	return nil
+}
+
+// checkTagOwner returns storage.ErrTagNotFound unless the tag belongs to the user.
+func (s *Storage) checkTagOwner(ctx context.Context, userId int64, tagId int64) error {
+	query := `SELECT EXISTS(SELECT 1 FROM tags WHERE id = $1 AND user_id = $2)`
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, tagId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrTagNotFound
+	}
+
+	return nil
+}
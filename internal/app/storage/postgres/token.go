@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	const op = "postgres.SaveRefreshToken"
+
+	query := `INSERT INTO refresh_tokens(user_id, family_id, token_hash, expires_at) VALUES($1, $2, $3, $4)`
+
+	if _, err := s.db.Exec(ctx, query, token.UserId, token.FamilyId, token.TokenHash, token.ExpiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RotateRefreshToken exchanges the token with tokenHash for a new one of the same
+// family hashed as nextHash and returns the owner. Presenting a token that was
+// already rotated or revoked revokes its whole family and fails with storage.ErrTokenReused.
+func (s *Storage) RotateRefreshToken(
+	ctx context.Context,
+	tokenHash string,
+	nextHash string,
+	expiresAt time.Time,
+) (models.User, error) {
+	const op = "postgres.RotateRefreshToken"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `SELECT rt.id, rt.family_id, rt.expires_at, rt.used_at IS NOT NULL OR rt.revoked_at IS NOT NULL,
+			u.id, u.email, u.email_verified
+		FROM refresh_tokens rt JOIN users u ON u.id = rt.user_id
+		WHERE rt.token_hash = $1
+		FOR UPDATE OF rt`
+
+	var (
+		tokenId    int64
+		familyId   string
+		validUntil time.Time
+		spent      bool
+		user       models.User
+	)
+
+	err = tx.QueryRow(ctx, query, tokenHash).Scan(&tokenId, &familyId, &validUntil, &spent, &user.Id, &user.Email, &user.EmailVerified)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if spent {
+		query = `UPDATE refresh_tokens SET revoked_at = now() WHERE family_id = $1 AND revoked_at IS NULL`
+
+		if _, err := tx.Exec(ctx, query, familyId); err != nil {
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := tx.Commit(ctx); err != nil {
+			return models.User{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrTokenReused)
+	}
+
+	if !validUntil.After(time.Now()) {
+		return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = now() WHERE id = $1`, tokenId); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `INSERT INTO refresh_tokens(user_id, family_id, token_hash, expires_at) VALUES($1, $2, $3, $4)`
+
+	if _, err := tx.Exec(ctx, query, user.Id, familyId, nextHash, expiresAt); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// RevokeRefreshFamily revokes the token with tokenHash together with its family.
+func (s *Storage) RevokeRefreshFamily(ctx context.Context, tokenHash string) error {
+	const op = "postgres.RevokeRefreshFamily"
+
+	query := `UPDATE refresh_tokens SET revoked_at = now()
+		WHERE revoked_at IS NULL
+			AND family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)`
+
+	tag, err := s.db.Exec(ctx, query, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+	}
+
+	return nil
+}
+
+// RevokeUserRefreshTokens revokes every refresh token of the user.
+func (s *Storage) RevokeUserRefreshTokens(ctx context.Context, userId int64) error {
+	const op = "postgres.RevokeUserRefreshTokens"
+
+	query := `UPDATE refresh_tokens SET revoked_at = now() WHERE user_id = $1 AND revoked_at IS NULL`
+
+	if _, err := s.db.Exec(ctx, query, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
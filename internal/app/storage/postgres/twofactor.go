@@ -0,0 +1,211 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) TwoFactor(ctx context.Context, userId int64) (models.TwoFactor, error) {
+	const op = "postgres.TwoFactor"
+
+	query := `SELECT totp_secret, totp_enabled, totp_last_step FROM users WHERE id = $1`
+
+	var tf models.TwoFactor
+
+	err := s.db.QueryRow(ctx, query, userId).Scan(&tf.Secret, &tf.Enabled, &tf.LastStep)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.TwoFactor{}, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+		return models.TwoFactor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tf, nil
+}
+
+// SaveTOTPSecret stores a secret waiting for confirmation,
+// it replaces a previous unconfirmed one.
+func (s *Storage) SaveTOTPSecret(ctx context.Context, userId int64, secret string) error {
+	const op = "postgres.SaveTOTPSecret"
+
+	query := `UPDATE users SET totp_secret = $2 WHERE id = $1 AND NOT totp_enabled`
+
+	tag, err := s.db.Exec(ctx, query, userId, secret)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTwoFactorOn)
+	}
+
+	return nil
+}
+
+// EnableTwoFactor turns the stored secret on and replaces the recovery codes.
+func (s *Storage) EnableTwoFactor(ctx context.Context, userId int64, step int64, codeHashes []string) error {
+	const op = "postgres.EnableTwoFactor"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users SET totp_enabled = true, totp_last_step = $2
+		WHERE id = $1 AND NOT totp_enabled AND totp_secret <> ''`
+
+	tag, err := tx.Exec(ctx, query, userId, step)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTwoFactorOn)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `INSERT INTO recovery_codes(user_id, code_hash) SELECT $1, unnest($2::text[])`
+
+	if _, err := tx.Exec(ctx, query, userId, codeHashes); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DisableTwoFactor(ctx context.Context, userId int64) error {
+	const op = "postgres.DisableTwoFactor"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE users SET totp_secret = '', totp_enabled = false, totp_last_step = 0 WHERE id = $1`
+
+	if _, err := tx.Exec(ctx, query, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM recovery_codes WHERE user_id = $1`, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records the time step of an accepted code,
+// a step that is not newer than the last one is reused.
+func (s *Storage) UseTOTPStep(ctx context.Context, userId int64, step int64) error {
+	const op = "postgres.UseTOTPStep"
+
+	query := `UPDATE users SET totp_last_step = $2 WHERE id = $1 AND totp_last_step < $2`
+
+	tag, err := s.db.Exec(ctx, query, userId, step)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCodeReused)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode spends the unused recovery code with codeHash.
+func (s *Storage) UseRecoveryCode(ctx context.Context, userId int64, codeHash string) error {
+	const op = "postgres.UseRecoveryCode"
+
+	query := `UPDATE recovery_codes SET used_at = now()
+		WHERE id = (
+			SELECT id FROM recovery_codes
+			WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+			LIMIT 1
+		)`
+
+	tag, err := s.db.Exec(ctx, query, userId, codeHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveTwoFactorChallenge(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error {
+	const op = "postgres.SaveTwoFactorChallenge"
+
+	query := `INSERT INTO two_factor_challenges(user_id, token_hash, expires_at) VALUES($1, $2, $3)`
+
+	if _, err := s.db.Exec(ctx, query, userId, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AttemptTwoFactorChallenge counts an attempt to answer the challenge with
+// tokenHash and returns the user it was issued to. Used and expired challenges
+// and the ones answered wrong maxAttempts times are not found.
+func (s *Storage) AttemptTwoFactorChallenge(ctx context.Context, tokenHash string, maxAttempts int) (models.User, error) {
+	const op = "postgres.AttemptTwoFactorChallenge"
+
+	query := `UPDATE two_factor_challenges c SET attempts = c.attempts + 1
+		FROM users u
+		WHERE c.token_hash = $1 AND u.id = c.user_id
+			AND c.used_at IS NULL AND c.expires_at > now() AND c.attempts < $2
+		RETURNING u.id, u.email, u.email_verified`
+
+	var user models.User
+
+	err := s.db.QueryRow(ctx, query, tokenHash, maxAttempts).Scan(&user.Id, &user.Email, &user.EmailVerified)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.User{}, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+		return models.User{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// CompleteTwoFactorChallenge spends the challenge so it can not be answered again.
+func (s *Storage) CompleteTwoFactorChallenge(ctx context.Context, tokenHash string) error {
+	const op = "postgres.CompleteTwoFactorChallenge"
+
+	query := `UPDATE two_factor_challenges SET used_at = now() WHERE token_hash = $1 AND used_at IS NULL`
+
+	tag, err := s.db.Exec(ctx, query, tokenHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) SaveEmailVerification(ctx context.Context, userId int64, tokenHash string, expiresAt time.Time) error {
+	const op = "postgres.SaveEmailVerification"
+
+	query := `INSERT INTO email_verifications(user_id, token_hash, expires_at) VALUES($1, $2, $3)`
+
+	if _, err := s.db.Exec(ctx, query, userId, tokenHash, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail spends the verification token with tokenHash and marks the email
+// of its user verified, a token of an email change sets the new email first.
+// Other pending tokens of the same kind are spent too. Used and expired tokens
+// are not found, an email taken in the meantime is ErrUserExists.
+func (s *Storage) VerifyEmail(ctx context.Context, tokenHash string) (int64, error) {
+	const op = "postgres.VerifyEmail"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE email_verifications SET used_at = now()
+		WHERE token_hash = $1 AND used_at IS NULL AND expires_at > now()
+		RETURNING user_id, new_email`
+
+	var (
+		userId   int64
+		newEmail *string
+	)
+
+	if err := tx.QueryRow(ctx, query, tokenHash).Scan(&userId, &newEmail); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrTokenNotFound)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `UPDATE users SET email = COALESCE($1, email), email_verified = true WHERE id = $2`
+
+	if _, err := tx.Exec(ctx, query, newEmail, userId); err != nil {
+		if isUniqueViolation(err) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrUserExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	query = `UPDATE email_verifications SET used_at = now()
+		WHERE user_id = $1 AND used_at IS NULL AND (new_email IS NULL) = ($2::text IS NULL)`
+
+	if _, err := tx.Exec(ctx, query, userId, newEmail); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userId, nil
+}
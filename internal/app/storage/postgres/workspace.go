@@ -0,0 +1,449 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/app/storage"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	pgx5 "github.com/jackc/pgx/v5"
+)
+
+// isMember matches when the user is a member of the workspace,
+// both are sql expressions, e.g. a column and a placeholder.
+func isMember(workspace string, user string) string {
+	return `EXISTS (SELECT 1 FROM workspace_members wm
+		WHERE wm.workspace_id = ` + workspace + ` AND wm.user_id = ` + user + `)`
+}
+
+// isEditor matches when the user is a member of the workspace allowed
+// to change its lists and items, that is anyone but a guest.
+func isEditor(workspace string, user string) string {
+	return `EXISTS (SELECT 1 FROM workspace_members wm
+		WHERE wm.workspace_id = ` + workspace + ` AND wm.user_id = ` + user + ` AND wm.role <> 'guest')`
+}
+
+// personalWorkspace selects id of the personal workspace of the user.
+func personalWorkspace(user string) string {
+	return `(SELECT id FROM workspaces WHERE owner_id = ` + user + ` AND personal)`
+}
+
+// saveWorkspaceQuery creates a workspace named $1 owned by $2 with $3 telling
+// whether it is personal, the owner becomes its first member.
+const saveWorkspaceQuery = `WITH w AS (
+		INSERT INTO workspaces(name, owner_id, personal) VALUES($1, $2, $3) RETURNING id, owner_id
+	), m AS (
+		INSERT INTO workspace_members(workspace_id, user_id, role) SELECT id, owner_id, 'owner' FROM w
+	)
+	SELECT id FROM w`
+
+const workspaceColumns = `w.id, w.name, w.owner_id, w.personal, wm.role, w.created_at`
+
+func (s *Storage) SaveWorkspace(ctx context.Context, ownerId int64, name string) (int64, error) {
+	const op = "postgres.SaveWorkspace"
+
+	var workspaceId int64
+
+	if err := s.db.QueryRow(ctx, saveWorkspaceQuery, name, ownerId, false).Scan(&workspaceId); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspaceId, nil
+}
+
+// UserWorkspaces returns workspaces the user is a member of, the personal one first.
+func (s *Storage) UserWorkspaces(ctx context.Context, userId int64) ([]models.Workspace, error) {
+	const op = "postgres.UserWorkspaces"
+
+	query := `SELECT ` + workspaceColumns + `
+		FROM workspaces w JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE wm.user_id = $1
+		ORDER BY NOT w.personal, w.id`
+
+	rows, err := s.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	workspaces, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Workspace])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspaces, nil
+}
+
+// Workspace returns the workspace with the role of the user in it,
+// workspaces the user is not a member of are storage.ErrWorkspaceNotFound.
+func (s *Storage) Workspace(ctx context.Context, userId int64, workspaceId int64) (models.Workspace, error) {
+	const op = "postgres.Workspace"
+
+	query := `SELECT ` + workspaceColumns + `
+		FROM workspaces w JOIN workspace_members wm ON wm.workspace_id = w.id
+		WHERE w.id = $1 AND wm.user_id = $2`
+
+	rows, err := s.db.Query(ctx, query, workspaceId, userId)
+	if err != nil {
+		return models.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	workspace, err := pgx5.CollectExactlyOneRow(rows, pgx5.RowToStructByName[models.Workspace])
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return models.Workspace{}, fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotFound)
+		}
+		return models.Workspace{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return workspace, nil
+}
+
+func (s *Storage) RenameWorkspace(ctx context.Context, workspaceId int64, name string) error {
+	const op = "postgres.RenameWorkspace"
+
+	tag, err := s.db.Exec(ctx, `UPDATE workspaces SET name = $1 WHERE id = $2`, name, workspaceId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotFound)
+	}
+
+	return nil
+}
+
+// DeleteWorkspace deletes the workspace with its lists and items in the trash,
+// personal workspaces are left alone and storage.ErrWorkspaceNotFound. Workspaces
+// with items outside the trash are storage.ErrWorkspaceNotEmpty.
+func (s *Storage) DeleteWorkspace(ctx context.Context, workspaceId int64) error {
+	const op = "postgres.DeleteWorkspace"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	// the workspace is locked, so no item is added to it while it is being deleted
+	query := `SELECT EXISTS(SELECT 1 FROM items WHERE workspace_id = w.id AND deleted_at IS NULL)
+		FROM workspaces w WHERE w.id = $1 AND NOT w.personal FOR UPDATE`
+
+	var hasItems bool
+
+	if err := tx.QueryRow(ctx, query, workspaceId).Scan(&hasItems); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if hasItems {
+		return fmt.Errorf("%s: %w", op, storage.ErrWorkspaceNotEmpty)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM workspaces WHERE id = $1`, workspaceId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) WorkspaceMembers(ctx context.Context, workspaceId int64) ([]models.Member, error) {
+	const op = "postgres.WorkspaceMembers"
+
+	query := `SELECT wm.user_id, u.email, u.display_name, wm.role, wm.created_at AS joined_at
+		FROM workspace_members wm JOIN users u ON u.id = wm.user_id
+		WHERE wm.workspace_id = $1
+		ORDER BY wm.created_at, wm.user_id`
+
+	rows, err := s.db.Query(ctx, query, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	members, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Member])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return members, nil
+}
+
+// MemberRole returns the role of the user in the workspace,
+// storage.ErrMemberNotFound when the user is not a member.
+func (s *Storage) MemberRole(ctx context.Context, workspaceId int64, userId int64) (string, error) {
+	const op = "postgres.MemberRole"
+
+	query := `SELECT role FROM workspace_members WHERE workspace_id = $1 AND user_id = $2`
+
+	var role string
+
+	if err := s.db.QueryRow(ctx, query, workspaceId, userId).Scan(&role); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return "", fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	return role, nil
+}
+
+// SetMemberRole changes the role of a member, the owner keeps their role.
+func (s *Storage) SetMemberRole(ctx context.Context, workspaceId int64, userId int64, role string) error {
+	const op = "postgres.SetMemberRole"
+
+	query := `UPDATE workspace_members SET role = $1
+		WHERE workspace_id = $2 AND user_id = $3 AND role <> 'owner'`
+
+	tag, err := s.db.Exec(ctx, query, role, workspaceId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	return nil
+}
+
+// DeleteMember removes a member other than the owner from the workspace,
+// items of the workspace assigned to them are unassigned.
+func (s *Storage) DeleteMember(ctx context.Context, workspaceId int64, userId int64) error {
+	const op = "postgres.DeleteMember"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2 AND role <> 'owner'`
+
+	tag, err := tx.Exec(ctx, query, workspaceId, userId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMemberNotFound)
+	}
+
+	query = `UPDATE items SET assignee_id = NULL, version = version + 1
+		WHERE workspace_id = $1 AND assignee_id = $2`
+
+	if _, err := tx.Exec(ctx, query, workspaceId, userId); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+const invitationColumns = `i.id, i.workspace_id, w.name AS workspace_name, i.user_id, u.email AS user_email,
+	i.invited_by, i.role, i.status, i.created_at, i.responded_at`
+
+const invitationJoins = `FROM workspace_invitations i
+	JOIN workspaces w ON w.id = i.workspace_id
+	JOIN users u ON u.id = i.user_id`
+
+// SaveInvitation invites the user to the workspace with the role, inviting
+// the user again changes the role and turns a declined invitation back into
+// a pending one. Members can not be invited, they are storage.ErrMemberExists.
+func (s *Storage) SaveInvitation(
+	ctx context.Context,
+	workspaceId int64,
+	invitedBy int64,
+	userId int64,
+	role string,
+) (int64, error) {
+	const op = "postgres.SaveInvitation"
+
+	query := `INSERT INTO workspace_invitations(workspace_id, invited_by, user_id, role)
+		SELECT $1, $2, $3, $4
+		WHERE NOT ` + isMember("$1", "$3") + `
+		ON CONFLICT (workspace_id, user_id) DO UPDATE SET role = EXCLUDED.role,
+			invited_by = EXCLUDED.invited_by,
+			status = 'pending',
+			created_at = now(),
+			responded_at = NULL
+		RETURNING id`
+
+	var invitationId int64
+
+	err := s.db.QueryRow(ctx, query, workspaceId, invitedBy, userId, role).Scan(&invitationId)
+	if err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return 0, fmt.Errorf("%s: %w", op, storage.ErrMemberExists)
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitationId, nil
+}
+
+// UserInvitations returns invitations of the user, empty status returns them all.
+func (s *Storage) UserInvitations(ctx context.Context, userId int64, status string) ([]models.Invitation, error) {
+	const op = "postgres.UserInvitations"
+
+	query := `SELECT ` + invitationColumns + ` ` + invitationJoins + `
+		WHERE i.user_id = $1 AND ($2 = '' OR i.status = $2)
+		ORDER BY i.id DESC`
+
+	rows, err := s.db.Query(ctx, query, userId, status)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Invitation])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// WorkspaceInvitations returns invitations to the workspace nobody accepted yet.
+func (s *Storage) WorkspaceInvitations(ctx context.Context, workspaceId int64) ([]models.Invitation, error) {
+	const op = "postgres.WorkspaceInvitations"
+
+	query := `SELECT ` + invitationColumns + ` ` + invitationJoins + `
+		WHERE i.workspace_id = $1 AND i.status <> 'accepted'
+		ORDER BY i.id DESC`
+
+	rows, err := s.db.Query(ctx, query, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invitations, err := pgx5.CollectRows(rows, pgx5.RowToStructByName[models.Invitation])
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return invitations, nil
+}
+
+// RespondInvitation accepts or declines a pending invitation of the user,
+// accepting it makes the user a member with the role of the invitation.
+func (s *Storage) RespondInvitation(ctx context.Context, userId int64, invitationId int64, accept bool) error {
+	const op = "postgres.RespondInvitation"
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `UPDATE workspace_invitations
+		SET status = CASE WHEN $3 THEN 'accepted' ELSE 'declined' END,
+			responded_at = now()
+		WHERE id = $1 AND user_id = $2 AND status = 'pending'
+		RETURNING workspace_id, role`
+
+	var (
+		workspaceId int64
+		role        string
+	)
+
+	if err := tx.QueryRow(ctx, query, invitationId, userId, accept).Scan(&workspaceId, &role); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrInvitationNotFound)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if accept {
+		query = `INSERT INTO workspace_members(workspace_id, user_id, role) VALUES($1, $2, $3)
+			ON CONFLICT DO NOTHING`
+
+		if _, err := tx.Exec(ctx, query, workspaceId, userId, role); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteInvitation(ctx context.Context, workspaceId int64, invitationId int64) error {
+	const op = "postgres.DeleteInvitation"
+
+	query := `DELETE FROM workspace_invitations WHERE id = $1 AND workspace_id = $2 AND status <> 'accepted'`
+
+	tag, err := s.db.Exec(ctx, query, invitationId, workspaceId)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrInvitationNotFound)
+	}
+
+	return nil
+}
+
+// checkWorkspaceEditor returns storage.ErrWorkspaceNotFound unless the user
+// can add lists and items to the workspace.
+func (s *Storage) checkWorkspaceEditor(ctx context.Context, userId int64, workspaceId int64) error {
+	query := `SELECT ` + isEditor("$1", "$2")
+
+	var exists bool
+
+	if err := s.db.QueryRow(ctx, query, workspaceId, userId).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrWorkspaceNotFound
+	}
+
+	return nil
+}
+
+// lockWorkspaceEditor returns storage.ErrWorkspaceNotFound unless the user can add
+// lists and items to the workspace, the membership stays locked until tx ends.
+func lockWorkspaceEditor(ctx context.Context, tx pgx5.Tx, userId int64, workspaceId int64) error {
+	query := `SELECT 1 FROM workspace_members
+		WHERE workspace_id = $1 AND user_id = $2 AND role <> 'guest' FOR SHARE`
+
+	var one int
+
+	if err := tx.QueryRow(ctx, query, workspaceId, userId).Scan(&one); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return storage.ErrWorkspaceNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// lockWorkspaceMember returns storage.ErrMemberNotFound unless the user is a member
+// of the workspace, the membership stays locked until tx ends.
+func lockWorkspaceMember(ctx context.Context, tx pgx5.Tx, workspaceId int64, userId int64) error {
+	query := `SELECT 1 FROM workspace_members WHERE workspace_id = $1 AND user_id = $2 FOR SHARE`
+
+	var one int
+
+	if err := tx.QueryRow(ctx, query, workspaceId, userId).Scan(&one); err != nil {
+		if errors.Is(err, pgx5.ErrNoRows) {
+			return storage.ErrMemberNotFound
+		}
+		return err
+	}
+
+	return nil
+}
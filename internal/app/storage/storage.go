@@ -3,7 +3,30 @@ package storage
 import "errors"
 
 var (
-	ErrUserExists   = errors.New("user already exists")
-	ErrUserNotFound = errors.New("user not found")
-	ErrAppNotFound  = errors.New("app not found")
+	ErrUserExists    = errors.New("user already exists")
+	ErrUserNotFound  = errors.New("user not found")
+	ErrAppNotFound   = errors.New("app not found")
+	ErrItemNotFound  = errors.New("item not found")
+	ErrListNotFound  = errors.New("list not found")
+	ErrTagNotFound   = errors.New("tag not found")
+	ErrTagExists     = errors.New("tag already exists")
+	ErrTokenNotFound = errors.New("token not found")
+	ErrTokenReused   = errors.New("token reused")
+	ErrTokenExpired  = errors.New("token expired")
+	ErrCodeReused    = errors.New("code reused")
+	ErrTwoFactorOn   = errors.New("two-factor authentication is enabled")
+	ErrShareNotFound = errors.New("share not found")
+
+	ErrWorkspaceNotFound  = errors.New("workspace not found")
+	ErrMemberNotFound     = errors.New("member not found")
+	ErrMemberExists       = errors.New("member already exists")
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrWorkspaceMismatch  = errors.New("workspace mismatch")
+	ErrWorkspaceNotEmpty  = errors.New("workspace is not empty")
+	ErrWorkspaceOwned     = errors.New("workspace has no admin to take it over")
+
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrNotificationNotFound = errors.New("notification not found")
+
+	ErrVersionMismatch = errors.New("version mismatch")
 )
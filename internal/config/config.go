@@ -1,6 +1,7 @@
 package config
 
 import (
+	"errors"
 	"log"
 	"os"
 	"time"
@@ -10,16 +11,42 @@ import (
 )
 
 type Config struct {
-	Env        string        `yaml:"env" env-default:"local"`
-	TokenTTL   time.Duration `yaml:"token_ttl" env-required:"true"`
-	HTTPServer `yaml:"http_server"`
-	DB         `yaml:"db"`
+	Env              string        `yaml:"env" env-default:"local"`
+	TokenTTL         time.Duration `yaml:"token_ttl" env-required:"true"`
+	RefreshTTL       time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl" env-default:"1h"`
+	AppURL           string        `yaml:"app_url" env-default:"http://localhost:8080"`
+	VerificationTTL  time.Duration `yaml:"verification_ttl" env-default:"24h"`
+	// UnverifiedAccess is what users may do until they verify their email:
+	// full, read or none, the latter does not let them log in.
+	UnverifiedAccess string `yaml:"unverified_access" env-default:"full"`
+	HTTPServer       `yaml:"http_server"`
+	DB               `yaml:"db"`
+	JWT              `yaml:"jwt"`
+	Scheduler        `yaml:"scheduler"`
+	SMTP             `yaml:"smtp"`
+	Lockout          `yaml:"lockout"`
+	TwoFactor        `yaml:"two_factor"`
+	Password         `yaml:"password"`
+	Archive          `yaml:"archive"`
+	Trash            `yaml:"trash"`
+	// AdminEmails are granted the admin role on start, the first admins come from here.
+	AdminEmails []string `yaml:"admin_emails"`
 }
 
+const (
+	AccessFull = "full"
+	AccessRead = "read"
+	AccessNone = "none"
+)
+
 type HTTPServer struct {
 	Address     string        `yaml:"address" env-default:"localhost:8080"`
 	Timeout     time.Duration `yaml:"timeout" env-default:"4s"`
 	IdleTimeout time.Duration `yaml:"idle_timeout" env-default:"60s"`
+	// TrustProxy takes the client address from the X-Real-IP and X-Forwarded-For
+	// headers, set it only behind a reverse proxy that overwrites them.
+	TrustProxy bool `yaml:"trust_proxy" env-default:"false"`
 }
 
 type DB struct {
@@ -30,6 +57,83 @@ type DB struct {
 	DBPassword string `yaml:"dbpassword" env-required:"true" env:"DB_PASSWORD"`
 }
 
+type JWT struct {
+	// Algorithm is HS256, RS256 or EdDSA, HS256 tokens are signed with the MY_SECRET env.
+	Algorithm string `yaml:"algorithm" env-default:"HS256"`
+	// KeysDir keeps the RS256 and EdDSA private keys, it may be shared by replicas.
+	KeysDir          string        `yaml:"keys_dir" env-default:"./keys"`
+	RotationInterval time.Duration `yaml:"rotation_interval" env-default:"720h"`
+	// GracePeriod is how long a replaced key still verifies tokens,
+	// it is never shorter than token_ttl.
+	GracePeriod time.Duration `yaml:"grace_period" env-default:"24h"`
+}
+
+type Scheduler struct {
+	Interval  time.Duration `yaml:"interval" env-default:"30s"`
+	BatchSize int           `yaml:"batch_size" env-default:"100"`
+	// Notifier selects how reminders are delivered: log, webhook or smtp.
+	Notifier   string `yaml:"notifier" env-default:"log"`
+	WebhookURL string `yaml:"webhook_url"`
+}
+
+// Lockout limits failed sign-in attempts. Once an account or a client IP
+// reaches its threshold of failures in a row, every further failure locks it
+// out for twice as long as the previous one, starting at BaseDelay.
+type Lockout struct {
+	AccountThreshold int           `yaml:"account_threshold" env-default:"5"`
+	IPThreshold      int           `yaml:"ip_threshold" env-default:"20"`
+	BaseDelay        time.Duration `yaml:"base_delay" env-default:"30s"`
+	MaxDelay         time.Duration `yaml:"max_delay" env-default:"15m"`
+	// Window is how long failures are remembered after the last one.
+	Window time.Duration `yaml:"window" env-default:"1h"`
+}
+
+type TwoFactor struct {
+	// Issuer names the app in authenticator apps.
+	Issuer string `yaml:"issuer" env-default:"todo-app"`
+	// ChallengeTTL is how long a sign-in waits for the second factor.
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+}
+
+// Password configures how passwords are hashed and which ones are accepted
+// at sign-up. Hashes made with another algorithm or other parameters are
+// upgraded on the next login.
+type Password struct {
+	// Algorithm is argon2id or bcrypt.
+	Algorithm string `yaml:"algorithm" env-default:"argon2id"`
+	// Argon2Memory is in KiB.
+	Argon2Memory      uint32 `yaml:"argon2_memory" env-default:"65536"`
+	Argon2Iterations  uint32 `yaml:"argon2_iterations" env-default:"3"`
+	Argon2Parallelism uint8  `yaml:"argon2_parallelism" env-default:"2"`
+	BcryptCost        int    `yaml:"bcrypt_cost" env-default:"10"`
+	MinLength         int    `yaml:"min_length" env-default:"8"`
+	MaxLength         int    `yaml:"max_length" env-default:"128"`
+	// BlocklistPath is a file of breached passwords or their SHA-1 hashes, one per line.
+	BlocklistPath string `yaml:"blocklist_path"`
+}
+
+type Archive struct {
+	// MaxImportSize limits uploaded archives, in bytes.
+	MaxImportSize int64 `yaml:"max_import_size" env-default:"104857600"`
+}
+
+// Trash keeps deleted items for Retention, the purge checking
+// every PurgeInterval deletes older ones for good.
+type Trash struct {
+	Retention     time.Duration `yaml:"retention" env-default:"720h"`
+	PurgeInterval time.Duration `yaml:"purge_interval" env-default:"1h"`
+}
+
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port" env-default:"587"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+	From     string `yaml:"from"`
+	// Timeout bounds sending a single email, sign-up waits for it.
+	SendTimeout time.Duration `yaml:"send_timeout" env-default:"10s"`
+}
+
 func MustLoad() *Config {
 	if err := godotenv.Load(); err != nil {
 		log.Fatalf("failed to load .env file: %s", err.Error())
@@ -51,6 +155,10 @@ func MustLoad() *Config {
 		log.Fatalf("failed to read config: %s", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	return &cfg
 }
 
@@ -70,5 +178,26 @@ func MustLoadByPath(cfgPath string) *Config {
 		log.Fatalf("failed to read config: %s", err)
 	}
 
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("invalid config: %s", err)
+	}
+
 	return &cfg
 }
+
+// validate rejects values cleanenv accepts but the app can not run with.
+func (c *Config) validate() error {
+	if c.Scheduler.Interval <= 0 {
+		return errors.New("scheduler.interval must be positive")
+	}
+
+	if c.Trash.Retention <= 0 {
+		return errors.New("trash.retention must be positive")
+	}
+
+	if c.Trash.PurgeInterval <= 0 {
+		return errors.New("trash.purge_interval must be positive")
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// UserSummary is a user as listed to admins.
+type UserSummary struct {
+	Id               int64      `json:"id"`
+	Email            string     `json:"email"`
+	DisplayName      string     `json:"display_name"`
+	Role             string     `json:"role"`
+	EmailVerified    bool       `json:"email_verified"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+}
+
+// UserDetails is a user with counts of what the user owns.
+type UserDetails struct {
+	UserSummary
+	Items ItemCounts `json:"items"`
+	Lists int        `json:"lists"`
+	Tags  int        `json:"tags"`
+}
+
+// ItemCounts counts items of a user, subtasks included.
+type ItemCounts struct {
+	Total   int `json:"total"`
+	Open    int `json:"open"`
+	Done    int `json:"done"`
+	Overdue int `json:"overdue"`
+}
+
+// UserFilter describes which users to list, ordered by id.
+// Query matches a part of the email or display name.
+type UserFilter struct {
+	Query    string
+	Role     string
+	Disabled *bool
+	Limit    int
+	AfterId  int64
+}
+
+type UserPage struct {
+	Users      []UserSummary `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
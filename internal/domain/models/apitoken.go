@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Scopes a personal access token may be granted, sessions started
+// with a password have all of them.
+const (
+	ScopeItemsRead  = "items:read"
+	ScopeItemsWrite = "items:write"
+	ScopeListsRead  = "lists:read"
+	ScopeListsWrite = "lists:write"
+	ScopeTagsRead   = "tags:read"
+	ScopeTagsWrite  = "tags:write"
+)
+
+var Scopes = []string{
+	ScopeItemsRead,
+	ScopeItemsWrite,
+	ScopeListsRead,
+	ScopeListsWrite,
+	ScopeTagsRead,
+	ScopeTagsWrite,
+}
+
+// APIToken is a personal access token, only its Prefix is kept
+// in clear to tell tokens apart.
+type APIToken struct {
+	Id         int64      `json:"id"`
+	UserId     int64      `json:"-" db:"user_id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at" db:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at" db:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
+
+// CreateAPITokenInput holds fields of a new token, nil ExpiresAt never expires.
+type CreateAPITokenInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
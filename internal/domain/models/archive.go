@@ -0,0 +1,34 @@
+package models
+
+import (
+	"iter"
+	"time"
+)
+
+// ArchiveVersion is the version of the export format, archives of
+// other versions are not imported.
+const ArchiveVersion = 1
+
+// Manifest describes an export archive.
+type Manifest struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// ExportSink receives the data of a user: the profile, lists and tags
+// first, then items one by one ordered by id, so subtasks follow their parents.
+type ExportSink interface {
+	Profile(profile Profile) error
+	Lists(lists []List) error
+	Tags(tags []Tag) error
+	Item(item Item) error
+}
+
+// Import is the data of an archive restored into an account. Items are read
+// as they are consumed, ids in it are those of the exported account.
+type Import struct {
+	Profile UpdateProfileInput
+	Lists   []List
+	Tags    []Tag
+	Items   iter.Seq2[Item, error]
+}
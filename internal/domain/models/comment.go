@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Comment is a message left on an item, replies have ParentId set and are
+// nested under their parent in Replies when a thread is returned.
+type Comment struct {
+	Id          int64      `json:"id"`
+	ItemId      int64      `json:"item_id" db:"item_id"`
+	ParentId    *int64     `json:"parent_id" db:"parent_id"`
+	UserId      int64      `json:"user_id" db:"user_id"`
+	AuthorEmail string     `json:"author_email" db:"author_email"`
+	Body        string     `json:"body"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at" db:"updated_at"`
+	Replies     []Comment  `json:"replies,omitempty" db:"-"`
+}
+
+// Kinds of notifications.
+const (
+	NotificationMention = "mention"
+)
+
+// Notification tells the user about something another user, the actor, did.
+type Notification struct {
+	Id         int64      `json:"id"`
+	Kind       string     `json:"kind"`
+	ActorId    int64      `json:"actor_id" db:"actor_id"`
+	ActorEmail string     `json:"actor_email" db:"actor_email"`
+	ItemId     int64      `json:"item_id" db:"item_id"`
+	ItemTitle  string     `json:"item_title" db:"item_title"`
+	CommentId  *int64     `json:"comment_id" db:"comment_id"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+	ReadAt     *time.Time `json:"read_at" db:"read_at"`
+}
+
+// Mention is a user notified about being mentioned in a comment.
+type Mention struct {
+	UserId int64  `db:"user_id"`
+	Email  string `db:"email"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// Kinds of item events.
+const (
+	ItemEventCreated    = "created"
+	ItemEventUpdated    = "updated"
+	ItemEventCompleted  = "completed"
+	ItemEventReopened   = "reopened"
+	ItemEventTagAdded   = "tag_added"
+	ItemEventTagRemoved = "tag_removed"
+	ItemEventShared     = "shared"
+	ItemEventDeleted    = "deleted"
+	ItemEventRestored   = "restored"
+)
+
+// ItemEvent is an entry of the item history. Updates carry the changed Field
+// with its values before and after, a share carries email of the invited user
+// in Field and the role in NewValue. ActorId is nil once the actor is deleted.
+type ItemEvent struct {
+	Id         int64     `json:"id"`
+	ItemId     int64     `json:"item_id" db:"item_id"`
+	ActorId    *int64    `json:"actor_id" db:"actor_id"`
+	ActorEmail *string   `json:"actor_email" db:"actor_email"`
+	Kind       string    `json:"kind"`
+	Field      string    `json:"field,omitempty"`
+	OldValue   *string   `json:"old_value" db:"old_value"`
+	NewValue   *string   `json:"new_value" db:"new_value"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
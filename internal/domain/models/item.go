@@ -1,7 +1,128 @@
 package models
 
+import "time"
+
+const (
+	PriorityNone = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
 type Item struct {
-	Id          int    `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	Id          int        `json:"id"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	Done        bool       `json:"done"`
+	CompletedAt *time.Time `json:"completed_at" db:"completed_at"`
+	DueAt       *time.Time `json:"due_at" db:"due_at"`
+	RemindAt    *time.Time `json:"remind_at" db:"remind_at"`
+	Priority    int        `json:"priority"`
+	ListId      *int64     `json:"list_id" db:"list_id"`
+	Tags        []string   `json:"tags"`
+	ParentId    *int64     `json:"parent_id" db:"parent_id"`
+	Position    int        `json:"position"`
+	RRule       string     `json:"rrule,omitempty" db:"rrule"`
+	SeriesId    *int64     `json:"series_id,omitempty" db:"series_id"`
+	Subtasks    []Item     `json:"subtasks,omitempty" db:"-"`
+	Progress    *Progress  `json:"progress,omitempty" db:"-"`
+	// SharedAs is the role granted to the user on an item of someone else.
+	SharedAs string `json:"shared_as,omitempty" db:"shared_as"`
+	// WorkspaceId is the workspace owning the item, AssigneeId one of its members.
+	WorkspaceId int64  `json:"workspace_id" db:"workspace_id"`
+	AssigneeId  *int64 `json:"assignee_id" db:"assignee_id"`
+	// CommentCount counts comments on the item including replies.
+	CommentCount int `json:"comment_count" db:"comment_count"`
+	// DeletedAt is set while the item is in the trash.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	// Version grows with every change of the item.
+	Version int64 `json:"version"`
+	// UserId is the owner of the item, its recurrence follows their timezone.
+	UserId int64 `json:"-" db:"user_id"`
+}
+
+// Progress counts completed direct subtasks of an item.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+// CreateItemInput holds fields of a new item, nil ListId leaves it out of any list.
+// Subtasks are created with ParentId set and go last among their siblings.
+// Subtasks and items of a list go to the workspace of their parent or list,
+// other items to WorkspaceId or, when it is nil, to the personal workspace.
+type CreateItemInput struct {
+	Title       string
+	Description string
+	DueAt       *time.Time
+	RemindAt    *time.Time
+	Priority    int
+	ListId      *int64
+	ParentId    *int64
+	RRule       string
+	WorkspaceId *int64
+	AssigneeId  *int64
+}
+
+// UpdateItemInput holds item fields to change, nil fields are left untouched.
+// ClearDueAt and ClearRemindAt remove the dates regardless of DueAt and RemindAt,
+// ClearList takes the item out of its list regardless of ListId and
+// ClearAssignee unassigns the item regardless of AssigneeId.
+type UpdateItemInput struct {
+	Title         *string
+	Description   *string
+	DueAt         *time.Time
+	ClearDueAt    bool
+	RemindAt      *time.Time
+	ClearRemindAt bool
+	Priority      *int
+	ListId        *int64
+	ClearList     bool
+	Position      *int
+	RRule         *string
+	AssigneeId    *int64
+	ClearAssignee bool
+	// Version is the version of the item the change is based on, zero skips the check.
+	Version int64
+}
+
+const (
+	ItemSortCreated  = "created"
+	ItemSortDueAt    = "due_at"
+	ItemSortPriority = "priority"
+	ItemSortTitle    = "title"
+)
+
+// ItemFilter describes which items to list and in what order.
+// After continues the listing right behind the given cursor.
+type ItemFilter struct {
+	Done     *bool
+	DueFrom  *time.Time
+	DueTo    *time.Time
+	Priority *int
+	Tag      string
+	Query    string
+	Sort     string
+	Desc     bool
+	Limit    int
+	After    *Cursor
+
+	// AssigneeId and WorkspaceId narrow the listing down to items
+	// assigned to the user or belonging to the workspace.
+	AssigneeId  *int64
+	WorkspaceId *int64
+}
+
+// Cursor points at a row in a keyset ordered listing by its sort value and id,
+// Sort and Desc tell the order the cursor is only valid for.
+type Cursor struct {
+	Value string `json:"v,omitempty"`
+	Id    int64  `json:"id"`
+	Sort  string `json:"s,omitempty"`
+	Desc  bool   `json:"d,omitempty"`
+}
+
+type ItemPage struct {
+	Items      []Item `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,29 @@
+package models
+
+type List struct {
+	Id       int64  `json:"id"`
+	Name     string `json:"name"`
+	Color    string `json:"color"`
+	Position int    `json:"position"`
+	Archived bool   `json:"archived"`
+	// SharedAs is the role granted to the user on a list of someone else.
+	SharedAs    string `json:"shared_as,omitempty" db:"shared_as"`
+	WorkspaceId int64  `json:"workspace_id" db:"workspace_id"`
+}
+
+// CreateListInput holds fields of a new list, nil Position puts the list last
+// and nil WorkspaceId puts it into the personal workspace.
+type CreateListInput struct {
+	Name        string
+	Color       string
+	Position    *int
+	WorkspaceId *int64
+}
+
+// UpdateListInput holds list fields to change, nil fields are left untouched.
+type UpdateListInput struct {
+	Name     *string
+	Color    *string
+	Position *int
+	Archived *bool
+}
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// Reasons a sign-in attempt failed for.
+const (
+	LoginInvalidCredentials = "invalid_credentials"
+	LoginLocked             = "locked"
+	LoginNotVerified        = "not_verified"
+	LoginDisabled           = "disabled"
+	LoginResetRequired      = "password_reset_required"
+)
+
+// LoginEvent records a sign-in attempt, Reason is empty for successful ones.
+type LoginEvent struct {
+	Email     string
+	IP        string
+	Success   bool
+	Reason    string
+	CreatedAt time.Time
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Reminder is a due reminder of an item to be delivered to its owner.
+type Reminder struct {
+	ItemId   int64      `json:"item_id" db:"item_id"`
+	UserId   int64      `json:"user_id" db:"user_id"`
+	Email    string     `json:"email"`
+	Title    string     `json:"title"`
+	DueAt    *time.Time `json:"due_at" db:"due_at"`
+	RemindAt time.Time  `json:"remind_at" db:"remind_at"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Roles a user can be granted on a shared item or list,
+// editors may change the shared items, viewers only see them.
+const (
+	ShareViewer = "viewer"
+	ShareEditor = "editor"
+)
+
+// States of a share, only accepted shares grant access.
+const (
+	SharePending  = "pending"
+	ShareAccepted = "accepted"
+	ShareDeclined = "declined"
+)
+
+// Share grants a user access to an item with its subtasks or to a list
+// with its items, exactly one of ItemId and ListId is set. Title is the
+// title of the item or the name of the list.
+type Share struct {
+	Id          int64      `json:"id"`
+	ItemId      *int64     `json:"item_id,omitempty" db:"item_id"`
+	ListId      *int64     `json:"list_id,omitempty" db:"list_id"`
+	Title       string     `json:"title"`
+	OwnerId     int64      `json:"owner_id" db:"owner_id"`
+	OwnerEmail  string     `json:"owner_email" db:"owner_email"`
+	UserId      int64      `json:"user_id" db:"user_id"`
+	UserEmail   string     `json:"user_email" db:"user_email"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	RespondedAt *time.Time `json:"responded_at" db:"responded_at"`
+}
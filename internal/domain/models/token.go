@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TokenPair is issued on login and on every refresh.
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+}
+
+// RefreshToken is a stored refresh token, tokens rotated from
+// the same login share FamilyId.
+type RefreshToken struct {
+	UserId    int64
+	FamilyId  string
+	TokenHash string
+	ExpiresAt time.Time
+}
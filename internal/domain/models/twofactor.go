@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// TwoFactor is the TOTP state of a user. Secret is set on enrollment and
+// is only used for sign-in once Enabled, LastStep is the time step of the
+// latest accepted code, codes of it and earlier steps are rejected.
+type TwoFactor struct {
+	Secret   string
+	Enabled  bool
+	LastStep int64
+}
+
+// Enrollment is a new TOTP secret waiting to be confirmed with a code.
+type Enrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+	// QRCode is the URI encoded as a PNG image.
+	QRCode []byte `json:"qr_code_png"`
+}
+
+// LoginResult is a token pair or, for users with two-factor authentication,
+// a challenge to be exchanged together with a code.
+type LoginResult struct {
+	Tokens             TokenPair
+	ChallengeToken     string
+	ChallengeExpiresAt time.Time
+}
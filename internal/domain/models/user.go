@@ -1,7 +1,38 @@
 package models
 
 type User struct {
-	Id       int64
-	Email    string
-	PassHash []byte
+	Id            int64
+	Email         string
+	PassHash      []byte
+	EmailVerified bool
+	// TwoFactorEnabled requires a TOTP or recovery code on sign-in.
+	TwoFactorEnabled bool
+	Role             string
+	// Disabled users can not sign in.
+	Disabled bool
+	// PasswordResetRequired users can not sign in until they reset the password.
+	PasswordResetRequired bool
+}
+
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Profile is the part of a user the user can see and edit.
+type Profile struct {
+	Id               int64  `json:"id"`
+	Email            string `json:"email"`
+	DisplayName      string `json:"display_name"`
+	Timezone         string `json:"timezone"`
+	Locale           string `json:"locale"`
+	EmailVerified    bool   `json:"email_verified"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+}
+
+// UpdateProfileInput holds profile fields to change, nil fields are left untouched.
+type UpdateProfileInput struct {
+	DisplayName *string
+	Timezone    *string
+	Locale      *string
 }
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// Roles of workspace members. Owners and admins manage the workspace and
+// its members, members work with its lists and items, guests only see them.
+const (
+	WorkspaceOwner  = "owner"
+	WorkspaceAdmin  = "admin"
+	WorkspaceMember = "member"
+	WorkspaceGuest  = "guest"
+)
+
+// States of a workspace invitation.
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+)
+
+// Workspace owns lists and items its members work on together, Role is the
+// role of the user looking at it. Every user has a personal workspace, it can
+// not be deleted and nobody else can join it.
+type Workspace struct {
+	Id        int64     `json:"id"`
+	Name      string    `json:"name"`
+	OwnerId   int64     `json:"owner_id" db:"owner_id"`
+	Personal  bool      `json:"personal"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+type Member struct {
+	UserId      int64     `json:"user_id" db:"user_id"`
+	Email       string    `json:"email"`
+	DisplayName string    `json:"display_name" db:"display_name"`
+	Role        string    `json:"role"`
+	JoinedAt    time.Time `json:"joined_at" db:"joined_at"`
+}
+
+type Invitation struct {
+	Id            int64      `json:"id"`
+	WorkspaceId   int64      `json:"workspace_id" db:"workspace_id"`
+	WorkspaceName string     `json:"workspace_name" db:"workspace_name"`
+	UserId        int64      `json:"user_id" db:"user_id"`
+	UserEmail     string     `json:"user_email" db:"user_email"`
+	InvitedBy     int64      `json:"invited_by" db:"invited_by"`
+	Role          string     `json:"role"`
+	Status        string     `json:"status"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	RespondedAt   *time.Time `json:"responded_at" db:"responded_at"`
+}
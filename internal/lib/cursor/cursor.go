@@ -0,0 +1,33 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+)
+
+// Encode returns an opaque url-safe representation of the cursor.
+func Encode(c models.Cursor) string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(s string) (models.Cursor, error) {
+	const op = "cursor.Decode"
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return models.Cursor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var c models.Cursor
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return models.Cursor{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return c, nil
+}
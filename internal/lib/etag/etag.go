@@ -0,0 +1,53 @@
+package etag
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Version returns the strong entity tag of a version of a resource.
+func Version(v int64) string {
+	return `"` + strconv.FormatInt(v, 10) + `"`
+}
+
+// ParseVersion returns the version an If-Match header asks for, zero for "*"
+// matching any version. Weak and malformed tags never match a version.
+func ParseVersion(header string) (int64, bool) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, true
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false
+	}
+
+	v, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+
+	return v, true
+}
+
+// Body returns the strong entity tag of a representation.
+func Body(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// NoneMatch reports whether none of the tags of an If-None-Match header
+// matches tag, comparing them weakly as the header requires.
+func NoneMatch(header string, tag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(tag, "W/") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package etag_test
+
+import (
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/lib/etag"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		header  string
+		version int64
+		ok      bool
+	}{
+		{header: `"3"`, version: 3, ok: true},
+		{header: ` "12" `, version: 12, ok: true},
+		{header: `*`, version: 0, ok: true},
+		{header: `W/"3"`},
+		{header: `3`},
+		{header: `"abc"`},
+		{header: `"0"`},
+		{header: `"3", "4"`},
+		{header: ``},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			version, ok := etag.ParseVersion(tt.header)
+
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.version, version)
+		})
+	}
+}
+
+func TestVersionRoundTrip(t *testing.T) {
+	version, ok := etag.ParseVersion(etag.Version(42))
+
+	require.True(t, ok)
+	require.Equal(t, int64(42), version)
+}
+
+func TestNoneMatch(t *testing.T) {
+	tag := etag.Body([]byte(`{"items":[]}`))
+
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "Empty", header: "", want: true},
+		{name: "Same", header: tag, want: false},
+		{name: "Weak", header: "W/" + tag, want: false},
+		{name: "Listed", header: `"other", ` + tag, want: false},
+		{name: "Any", header: "*", want: false},
+		{name: "Other", header: `"other"`, want: true},
+		{name: "Changed body", header: etag.Body([]byte(`{"items":[{}]}`)), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, etag.NoneMatch(tt.header, tag))
+		})
+	}
+}
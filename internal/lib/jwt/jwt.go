@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -8,20 +9,67 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func NewToken(user models.User, duration time.Duration, secret string) (string, error) {
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims are the parts of a verified token the app relies on.
+type Claims struct {
+	UserId        int64
+	EmailVerified bool
+}
+
+func NewToken(user models.User, duration time.Duration, signer Signer) (string, error) {
 	const op = "jwt.NewToken"
 
-	token := jwt.New(jwt.SigningMethodHS256)
+	kid, method, key := signer.SigningKey()
+
+	token := jwt.New(method)
+
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
 
 	claims := token.Claims.(jwt.MapClaims)
 	claims["uid"] = user.Id
 	claims["email"] = user.Email
+	claims["email_verified"] = user.EmailVerified
 	claims["exp"] = time.Now().Add(duration).Unix()
 
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(key)
 	if err != nil {
 		return "", fmt.Errorf("%s: %w", op, err)
 	}
 
 	return tokenString, nil
 }
+
+// Parse verifies the token and returns the claims of the user it was issued to.
+// Tokens issued before email verification existed count as verified.
+func Parse(tokenString string, verifier Verifier) (Claims, error) {
+	const op = "jwt.Parse"
+
+	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+
+		return verifier.VerifyingKey(kid, t.Method.Alg())
+	}, jwt.WithExpirationRequired())
+	if err != nil {
+		return Claims{}, fmt.Errorf("%s: %w: %w", op, ErrInvalidToken, err)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return Claims{}, fmt.Errorf("%s: %w: unexpected claims", op, ErrInvalidToken)
+	}
+
+	uid, ok := claims["uid"].(float64)
+	if !ok {
+		return Claims{}, fmt.Errorf("%s: %w: uid claim is missing", op, ErrInvalidToken)
+	}
+
+	verified, ok := claims["email_verified"].(bool)
+
+	return Claims{
+		UserId:        int64(uid),
+		EmailVerified: verified || !ok,
+	}, nil
+}
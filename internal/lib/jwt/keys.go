@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Signer provides the key new tokens are signed with, kid is empty
+// when tokens are signed with a single shared secret.
+type Signer interface {
+	SigningKey() (kid string, method jwt.SigningMethod, key any)
+}
+
+// Verifier provides the key to verify a token signed with alg by the key kid.
+type Verifier interface {
+	VerifyingKey(kid string, alg string) (any, error)
+}
+
+// Keys signs and verifies tokens and publishes public keys of the set.
+type Keys interface {
+	Signer
+	Verifier
+	PublicKeys() []JWK
+}
+
+// HMAC signs tokens with HS256 and a shared secret, it has no public keys.
+type HMAC struct {
+	secret []byte
+}
+
+func NewHMAC(secret string) *HMAC {
+	return &HMAC{secret: []byte(secret)}
+}
+
+func (h *HMAC) SigningKey() (string, jwt.SigningMethod, any) {
+	return "", jwt.SigningMethodHS256, h.secret
+}
+
+func (h *HMAC) VerifyingKey(_ string, alg string) (any, error) {
+	if alg != jwt.SigningMethodHS256.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", alg)
+	}
+
+	return h.secret, nil
+}
+
+func (h *HMAC) PublicKeys() []JWK {
+	return []JWK{}
+}
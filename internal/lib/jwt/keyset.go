@@ -0,0 +1,328 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+const (
+	rsaKeyBits = 2048
+	// kidLayout prefixes kids of generated keys with their creation time,
+	// a random suffix keeps kids of keys created at once by several replicas apart.
+	kidLayout = "20060102T150405Z"
+	// reloadAfter bounds how often an unknown kid makes the set reread the directory.
+	reloadAfter = 5 * time.Second
+)
+
+type key struct {
+	id        string
+	method    jwt.SigningMethod
+	private   crypto.Signer
+	createdAt time.Time
+}
+
+// KeySet holds the private keys stored as PEM files in a directory.
+// The newest key signs new tokens, older keys only verify tokens until
+// the grace period after their successor was created is over.
+// Several replicas may share the directory.
+type KeySet struct {
+	mu       sync.RWMutex
+	dir      string
+	alg      string
+	rotation time.Duration
+	grace    time.Duration
+	keys     []key // sorted from the oldest to the newest
+	loadedAt time.Time
+}
+
+// NewKeySet loads keys from dir generating the first one if there is none.
+// A new alg key is generated every rotation, keys of other algorithms
+// found in dir are still used for verification.
+func NewKeySet(dir string, alg string, rotation time.Duration, grace time.Duration) (*KeySet, error) {
+	const op = "jwt.NewKeySet"
+
+	if alg != AlgRS256 && alg != AlgEdDSA {
+		return nil, fmt.Errorf("%s: unsupported algorithm %q", op, alg)
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	ks := &KeySet{
+		dir:      dir,
+		alg:      alg,
+		rotation: rotation,
+		grace:    grace,
+	}
+
+	if err := ks.Rotate(time.Now()); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) SigningKey() (string, jwt.SigningMethod, any) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	active := ks.keys[len(ks.keys)-1]
+
+	return active.id, active.method, active.private
+}
+
+func (ks *KeySet) VerifyingKey(kid string, alg string) (any, error) {
+	k, ok := ks.find(kid)
+	if !ok {
+		// the key may have been just created by another replica
+		if err := ks.reload(false); err != nil {
+			return nil, err
+		}
+
+		if k, ok = ks.find(kid); !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+	}
+
+	if k.method.Alg() != alg {
+		return nil, fmt.Errorf("unexpected signing method %q", alg)
+	}
+
+	return k.private.Public(), nil
+}
+
+func (ks *KeySet) PublicKeys() []JWK {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	keys := make([]JWK, 0, len(ks.keys))
+
+	for _, k := range ks.keys {
+		if jwk, ok := newJWK(k.id, k.method.Alg(), k.private.Public()); ok {
+			keys = append(keys, jwk)
+		}
+	}
+
+	return keys
+}
+
+// Rotate rereads the directory, creates a new signing key when the newest one
+// is older than the rotation interval and removes keys retired longer than
+// the grace period ago.
+func (ks *KeySet) Rotate(now time.Time) error {
+	const op = "jwt.KeySet.Rotate"
+
+	if err := ks.reload(true); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if len(ks.keys) == 0 || !now.Before(ks.keys[len(ks.keys)-1].createdAt.Add(ks.rotation)) {
+		k, err := ks.generate(now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		ks.keys = append(ks.keys, k)
+	}
+
+	kept := ks.keys[:0]
+
+	for i, k := range ks.keys {
+		if i < len(ks.keys)-1 && now.After(ks.keys[i+1].createdAt.Add(ks.grace)) {
+			if err := os.Remove(filepath.Join(ks.dir, k.id+".pem")); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("%s: %w", op, err)
+			}
+
+			continue
+		}
+
+		kept = append(kept, k)
+	}
+
+	ks.keys = kept
+
+	return nil
+}
+
+func (ks *KeySet) find(kid string) (key, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	for _, k := range ks.keys {
+		if k.id == kid {
+			return k, true
+		}
+	}
+
+	return key{}, false
+}
+
+// reload replaces the keys with the ones stored in the directory,
+// unless forced it is skipped when the directory was read recently.
+func (ks *KeySet) reload(force bool) error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if !force && time.Since(ks.loadedAt) < reloadAfter {
+		return nil
+	}
+
+	paths, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	keys := make([]key, 0, len(paths))
+
+	for _, path := range paths {
+		k, err := readKey(path)
+		if err != nil {
+			return err
+		}
+
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].createdAt.Equal(keys[j].createdAt) {
+			return keys[i].id < keys[j].id
+		}
+
+		return keys[i].createdAt.Before(keys[j].createdAt)
+	})
+
+	// keep the current keys if the directory was emptied under us
+	if len(keys) > 0 || len(ks.keys) == 0 {
+		ks.keys = keys
+	}
+	ks.loadedAt = time.Now()
+
+	return nil
+}
+
+// generate creates a new key and stores it in the directory.
+func (ks *KeySet) generate(now time.Time) (key, error) {
+	var (
+		private crypto.Signer
+		method  jwt.SigningMethod
+		err     error
+	)
+
+	switch ks.alg {
+	case AlgRS256:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		method = jwt.SigningMethodRS256
+	case AlgEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+		method = jwt.SigningMethodEdDSA
+	}
+	if err != nil {
+		return key{}, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return key{}, err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return key{}, err
+	}
+
+	k := key{
+		id:        now.UTC().Format(kidLayout) + "-" + hex.EncodeToString(suffix),
+		method:    method,
+		private:   private,
+		createdAt: now,
+	}
+
+	// write under a temporary name first so other replicas never read a partial file
+	path := filepath.Join(ks.dir, k.id+".pem")
+	tmp := path + ".tmp"
+
+	if err := os.WriteFile(tmp, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return key{}, err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return key{}, err
+	}
+
+	return k, nil
+}
+
+// readKey reads a PKCS #8 or PKCS #1 PEM private key, the file name is its kid.
+func readKey(path string) (key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return key{}, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return key{}, fmt.Errorf("%s: no PEM data", path)
+	}
+
+	var parsed any
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return key{}, fmt.Errorf("%s: %w", path, err)
+	}
+
+	k := key{id: strings.TrimSuffix(filepath.Base(path), ".pem")}
+
+	switch private := parsed.(type) {
+	case *rsa.PrivateKey:
+		k.method, k.private = jwt.SigningMethodRS256, private
+	case ed25519.PrivateKey:
+		k.method, k.private = jwt.SigningMethodEdDSA, private
+	default:
+		return key{}, fmt.Errorf("%s: unsupported key type %T", path, parsed)
+	}
+
+	// keys put into the directory by hand are dated by their modification time
+	created, _, _ := strings.Cut(k.id, "-")
+
+	k.createdAt, err = time.Parse(kidLayout, created)
+	if err != nil {
+		info, err := os.Stat(path)
+		if err != nil {
+			return key{}, err
+		}
+
+		k.createdAt = info.ModTime()
+	}
+
+	return k, nil
+}
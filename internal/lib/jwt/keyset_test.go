@@ -0,0 +1,113 @@
+package jwt_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySetSignAndVerify(t *testing.T) {
+	tests := []struct {
+		name string
+		alg  string
+		kty  string
+	}{
+		{name: "RS256", alg: jwt.AlgRS256, kty: "RSA"},
+		{name: "EdDSA", alg: jwt.AlgEdDSA, kty: "OKP"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			ks, err := jwt.NewKeySet(t.TempDir(), tt.alg, time.Hour, time.Hour)
+			require.NoError(t, err)
+
+			token, err := jwt.NewToken(models.User{Id: 42, Email: "test@mail.ru"}, time.Minute, ks)
+			require.NoError(t, err)
+
+			claims, err := jwt.Parse(token, ks)
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), claims.UserId)
+			assert.False(t, claims.EmailVerified)
+
+			keys := ks.PublicKeys()
+			require.Len(t, keys, 1)
+			assert.Equal(t, tt.kty, keys[0].Kty)
+			assert.Equal(t, tt.alg, keys[0].Alg)
+
+			kid, _, _ := ks.SigningKey()
+			assert.Equal(t, kid, keys[0].Kid)
+		})
+	}
+}
+
+func TestKeySetRotation(t *testing.T) {
+	dir := t.TempDir()
+
+	const (
+		rotation = time.Hour
+		grace    = 10 * time.Minute
+	)
+
+	ks, err := jwt.NewKeySet(dir, jwt.AlgEdDSA, rotation, grace)
+	require.NoError(t, err)
+
+	user := models.User{Id: 1}
+
+	oldToken, err := jwt.NewToken(user, 2*time.Hour, ks)
+	require.NoError(t, err)
+
+	oldKid, _, _ := ks.SigningKey()
+
+	// not due yet
+	require.NoError(t, ks.Rotate(time.Now().Add(rotation/2)))
+	kid, _, _ := ks.SigningKey()
+	assert.Equal(t, oldKid, kid)
+
+	rotatedAt := time.Now().Add(rotation + time.Second)
+	require.NoError(t, ks.Rotate(rotatedAt))
+
+	newKid, _, _ := ks.SigningKey()
+	assert.NotEqual(t, oldKid, newKid)
+	assert.Len(t, ks.PublicKeys(), 2)
+
+	// the old key still verifies during the grace period
+	_, err = jwt.Parse(oldToken, ks)
+	require.NoError(t, err)
+
+	// another replica sharing the directory picks up the new key
+	replica, err := jwt.NewKeySet(dir, jwt.AlgEdDSA, rotation, grace)
+	require.NoError(t, err)
+	replicaKid, _, _ := replica.SigningKey()
+	assert.Equal(t, newKid, replicaKid)
+
+	require.NoError(t, ks.Rotate(rotatedAt.Add(grace+time.Second)))
+
+	keys := ks.PublicKeys()
+	require.Len(t, keys, 1)
+	assert.Equal(t, newKid, keys[0].Kid)
+
+	_, err = os.Stat(filepath.Join(dir, oldKid+".pem"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = jwt.Parse(oldToken, ks)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
+
+func TestParseRejectsOtherAlgorithm(t *testing.T) {
+	ks, err := jwt.NewKeySet(t.TempDir(), jwt.AlgRS256, time.Hour, time.Hour)
+	require.NoError(t, err)
+
+	token, err := jwt.NewToken(models.User{Id: 1}, time.Minute, jwt.NewHMAC("secret"))
+	require.NoError(t, err)
+
+	_, err = jwt.Parse(token, ks)
+	assert.ErrorIs(t, err, jwt.ErrInvalidToken)
+}
@@ -0,0 +1,31 @@
+// Package mention finds users mentioned in a text as @email.
+package mention
+
+import (
+	"regexp"
+	"strings"
+)
+
+// mentionRe matches @ followed by an email at the start of the text or after
+// a character that can not be part of an email, so plain emails do not count.
+var mentionRe = regexp.MustCompile(`(?:^|[^\w.+@-])@([\w.+-]+@[\w-]+(?:\.[\w-]+)+)`)
+
+// Parse returns lowercased emails mentioned in the text in order of
+// their first appearance, every email is returned once.
+func Parse(text string) []string {
+	var emails []string
+
+	seen := make(map[string]bool)
+
+	for _, m := range mentionRe.FindAllStringSubmatch(text, -1) {
+		email := strings.ToLower(m[1])
+		if seen[email] {
+			continue
+		}
+
+		seen[email] = true
+		emails = append(emails, email)
+	}
+
+	return emails
+}
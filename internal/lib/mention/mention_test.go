@@ -0,0 +1,49 @@
+package mention_test
+
+import (
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/lib/mention"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "Single",
+			text: "@bob@mail.ru please take a look",
+			want: []string{"bob@mail.ru"},
+		},
+		{
+			name: "Several with punctuation",
+			text: "Ask @Alice@Mail.ru, and @bob.smith+todo@work.example.com.",
+			want: []string{"alice@mail.ru", "bob.smith+todo@work.example.com"},
+		},
+		{
+			name: "Repeated",
+			text: "@bob@mail.ru (@BOB@mail.ru)",
+			want: []string{"bob@mail.ru"},
+		},
+		{
+			name: "Plain email",
+			text: "write to bob@mail.ru or me@bob@mail.ru",
+		},
+		{
+			name: "No domain",
+			text: "@bob@localhost and @bob",
+		},
+		{
+			name: "Empty",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, mention.Parse(tt.text))
+		})
+	}
+}
@@ -0,0 +1,186 @@
+// Package password hashes passwords with argon2id or bcrypt and checks
+// them against a password policy.
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgArgon2id = "argon2id"
+	AlgBcrypt   = "bcrypt"
+)
+
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// Argon2idParams are the argon2id cost parameters, Memory is in KiB.
+type Argon2idParams struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Hasher hashes new passwords with Algorithm and verifies hashes of
+// every supported algorithm, so stored hashes can be upgraded on login.
+//
+// Argon2id hashes are encoded in the PHC string format:
+//
+//	$argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>
+//
+// bcrypt hashes keep their own $2a$ format.
+type Hasher struct {
+	Algorithm  string
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+func New(alg string, argon2idParams Argon2idParams, bcryptCost int) (Hasher, error) {
+	const op = "password.New"
+
+	if alg != AlgArgon2id && alg != AlgBcrypt {
+		return Hasher{}, fmt.Errorf("%s: unsupported algorithm %q", op, alg)
+	}
+
+	if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+		return Hasher{}, fmt.Errorf("%s: bcrypt cost must be in [%d, %d]", op, bcrypt.MinCost, bcrypt.MaxCost)
+	}
+
+	p := argon2idParams
+	if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength == 0 || p.KeyLength == 0 {
+		return Hasher{}, fmt.Errorf("%s: argon2id parameters must be positive", op)
+	}
+
+	return Hasher{
+		Algorithm:  alg,
+		Argon2id:   argon2idParams,
+		BcryptCost: bcryptCost,
+	}, nil
+}
+
+func (h Hasher) Hash(password string) (string, error) {
+	if h.Algorithm == AlgBcrypt {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+
+		return string(hash), nil
+	}
+
+	salt := make([]byte, h.Argon2id.SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	return encodeArgon2id(h.Argon2id, salt, argon2idKey(password, salt, h.Argon2id)), nil
+}
+
+// Verify reports whether password matches the encoded hash.
+func (h Hasher) Verify(password string, encoded string) (bool, error) {
+	if isBcrypt(encoded) {
+		err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
+	p, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare(key, argon2idKey(password, salt, p)) == 1, nil
+}
+
+// NeedsRehash reports whether the encoded hash was made with another
+// algorithm or other parameters than the hasher uses now.
+func (h Hasher) NeedsRehash(encoded string) bool {
+	if isBcrypt(encoded) {
+		if h.Algorithm != AlgBcrypt {
+			return true
+		}
+
+		cost, err := bcrypt.Cost([]byte(encoded))
+
+		return err != nil || cost != h.BcryptCost
+	}
+
+	if h.Algorithm != AlgArgon2id {
+		return true
+	}
+
+	p, salt, key, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	p.SaltLength, p.KeyLength = uint32(len(salt)), uint32(len(key))
+
+	return p != h.Argon2id
+}
+
+func isBcrypt(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") ||
+		strings.HasPrefix(encoded, "$2b$") ||
+		strings.HasPrefix(encoded, "$2y$")
+}
+
+func argon2idKey(password string, salt []byte, p Argon2idParams) []byte {
+	return argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, p.KeyLength)
+}
+
+func encodeArgon2id(p Argon2idParams, salt []byte, key []byte) string {
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		p.Memory, p.Iterations, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+func decodeArgon2id(encoded string) (Argon2idParams, []byte, []byte, error) {
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != AlgArgon2id {
+		return Argon2idParams{}, nil, nil, ErrUnknownHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: unsupported argon2 version", ErrUnknownHash)
+	}
+
+	var p Argon2idParams
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+	}
+
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return Argon2idParams{}, nil, nil, fmt.Errorf("%w: %w", ErrUnknownHash, err)
+	}
+
+	p.SaltLength, p.KeyLength = uint32(len(salt)), uint32(len(key))
+
+	return p, salt, key, nil
+}
@@ -0,0 +1,181 @@
+package password_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Muaz717/todo-app/internal/lib/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cheap parameters keep the tests fast
+var testParams = password.Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func newHasher(t *testing.T, alg string, params password.Argon2idParams, cost int) password.Hasher {
+	t.Helper()
+
+	h, err := password.New(alg, params, cost)
+	require.NoError(t, err)
+
+	return h
+}
+
+func TestHashVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		alg    string
+		prefix string
+	}{
+		{
+			name:   "Argon2id",
+			alg:    password.AlgArgon2id,
+			prefix: "$argon2id$v=19$m=1024,t=1,p=1$",
+		},
+		{
+			name:   "Bcrypt",
+			alg:    password.AlgBcrypt,
+			prefix: "$2a$04$",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			h := newHasher(t, tt.alg, testParams, 4)
+
+			hash, err := h.Hash("correct horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(hash, tt.prefix), hash)
+
+			ok, err := h.Verify("correct horse", hash)
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = h.Verify("battery staple", hash)
+			require.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.False(t, h.NeedsRehash(hash))
+		})
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcryptHash, err := newHasher(t, password.AlgBcrypt, testParams, 4).Hash("secret")
+	require.NoError(t, err)
+
+	argonHash, err := newHasher(t, password.AlgArgon2id, testParams, 4).Hash("secret")
+	require.NoError(t, err)
+
+	stronger := testParams
+	stronger.Iterations = 2
+
+	// hashes of other algorithms are still verified
+	ok, err := newHasher(t, password.AlgArgon2id, testParams, 4).Verify("secret", bcryptHash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.True(t, newHasher(t, password.AlgArgon2id, testParams, 4).NeedsRehash(bcryptHash))
+	assert.True(t, newHasher(t, password.AlgBcrypt, testParams, 4).NeedsRehash(argonHash))
+	assert.True(t, newHasher(t, password.AlgBcrypt, testParams, 5).NeedsRehash(bcryptHash))
+	assert.True(t, newHasher(t, password.AlgArgon2id, stronger, 4).NeedsRehash(argonHash))
+	assert.True(t, newHasher(t, password.AlgArgon2id, testParams, 4).NeedsRehash("plain"))
+}
+
+func TestVerifyInvalidHash(t *testing.T) {
+	h := newHasher(t, password.AlgArgon2id, testParams, 4)
+
+	for _, hash := range []string{"", "plain", "$argon2id$v=18$m=1,t=1,p=1$c2FsdA$a2V5", "$argon2id$v=19$m=1$c2FsdA$a2V5"} {
+		_, err := h.Verify("secret", hash)
+		assert.True(t, errors.Is(err, password.ErrUnknownHash), hash)
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := password.New("md5", testParams, 10)
+	assert.Error(t, err)
+
+	_, err = password.New(password.AlgBcrypt, testParams, 1)
+	assert.Error(t, err)
+
+	_, err = password.New(password.AlgArgon2id, password.Argon2idParams{}, 10)
+	assert.Error(t, err)
+}
+
+func TestPolicy(t *testing.T) {
+	blocklist := filepath.Join(t.TempDir(), "breached.txt")
+
+	// the second line is the SHA-1 of "hunter2hunter2"
+	data := "Password123\n" +
+		"FC8C5EB194806E31A213F073131E73B0012A0FB5:12\n" +
+		"\n"
+	require.NoError(t, os.WriteFile(blocklist, []byte(data), 0o600))
+
+	policy, err := password.NewPolicy(8, 16, blocklist)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		reason   string
+	}{
+		{
+			name:     "Valid",
+			password: "long enough",
+		},
+		{
+			name:     "Too short",
+			password: "short",
+			reason:   "must be at least 8 characters long",
+		},
+		{
+			name:     "Too long",
+			password: "much too long for the policy",
+			reason:   "must be at most 16 characters long",
+		},
+		{
+			name:     "Multibyte characters are counted once",
+			password: "пароль12",
+		},
+		{
+			name:     "Breached",
+			password: "password123",
+			reason:   "found in a list of breached passwords",
+		},
+		{
+			name:     "Breached by hash",
+			password: "hunter2hunter2",
+			reason:   "found in a list of breached passwords",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password)
+
+			if tt.reason == "" {
+				require.NoError(t, err)
+
+				return
+			}
+
+			var policyErr *password.PolicyError
+			require.ErrorAs(t, err, &policyErr)
+			assert.Equal(t, tt.reason, policyErr.Reason)
+		})
+	}
+
+	_, err = password.NewPolicy(8, 16, filepath.Join(t.TempDir(), "missing.txt"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// PolicyError tells why a password was rejected.
+type PolicyError struct {
+	Reason string
+}
+
+func (e *PolicyError) Error() string {
+	return "weak password: " + e.Reason
+}
+
+// Policy is the rule new passwords have to follow.
+type Policy struct {
+	MinLength int
+	MaxLength int
+	// blocklist holds lower-cased breached passwords and SHA-1
+	// hashes of breached passwords in upper-case hex.
+	blocklist map[string]struct{}
+}
+
+// NewPolicy creates a policy, blocklistPath may name a file of breached
+// passwords, one per line. Lines of 40 hex digits, optionally followed by
+// ":count" as in the Have I Been Pwned dumps, are taken as SHA-1 hashes.
+func NewPolicy(minLength int, maxLength int, blocklistPath string) (*Policy, error) {
+	const op = "password.NewPolicy"
+
+	p := &Policy{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		blocklist: make(map[string]struct{}),
+	}
+
+	if blocklistPath == "" {
+		return p, nil
+	}
+
+	f, err := os.Open(blocklistPath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if hash, _, _ := strings.Cut(line, ":"); isSHA1(hash) {
+			p.blocklist[strings.ToUpper(hash)] = struct{}{}
+
+			continue
+		}
+
+		p.blocklist[strings.ToLower(line)] = struct{}{}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return p, nil
+}
+
+// Check returns a PolicyError if the password does not follow the policy.
+func (p *Policy) Check(password string) error {
+	length := utf8.RuneCountInString(password)
+
+	if length < p.MinLength {
+		return &PolicyError{Reason: fmt.Sprintf("must be at least %d characters long", p.MinLength)}
+	}
+
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &PolicyError{Reason: fmt.Sprintf("must be at most %d characters long", p.MaxLength)}
+	}
+
+	if p.breached(password) {
+		return &PolicyError{Reason: "found in a list of breached passwords"}
+	}
+
+	return nil
+}
+
+func (p *Policy) breached(password string) bool {
+	if _, ok := p.blocklist[strings.ToLower(password)]; ok {
+		return true
+	}
+
+	sum := sha1.Sum([]byte(password))
+	_, ok := p.blocklist[strings.ToUpper(hex.EncodeToString(sum[:]))]
+
+	return ok
+}
+
+func isSHA1(s string) bool {
+	if len(s) != 2*sha1.Size {
+		return false
+	}
+
+	_, err := hex.DecodeString(s)
+
+	return err == nil
+}
@@ -0,0 +1,444 @@
+// Package rrule parses and expands a subset of iCalendar (RFC 5545)
+// recurrence rules: FREQ, INTERVAL, BYDAY, BYMONTHDAY, BYMONTH, COUNT and UNTIL.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency int
+
+const (
+	Daily Frequency = iota + 1
+	Weekly
+	Monthly
+	Yearly
+)
+
+var frequencies = map[string]Frequency{
+	"DAILY":   Daily,
+	"WEEKLY":  Weekly,
+	"MONTHLY": Monthly,
+	"YEARLY":  Yearly,
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// Weekday is a BYDAY entry, N selects the n-th weekday of the month
+// (negative counts from the end), zero means every such weekday.
+type Weekday struct {
+	Day time.Weekday
+	N   int
+}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []Weekday
+	ByMonthDay []int
+	ByMonth    []time.Month
+	// Count is the number of occurrences left including the current one, zero is unlimited.
+	Count int
+	Until *time.Time
+}
+
+// maxPeriods bounds the search for the next occurrence so rules that
+// can never match (e.g. BYMONTHDAY=31 with BYMONTH=2) do not loop forever.
+const maxPeriods = 1000
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+// Parse parses a rule in the "FREQ=WEEKLY;BYDAY=MO,WE" form,
+// an optional "RRULE:" prefix is accepted.
+func Parse(s string) (Rule, error) {
+	const op = "rrule.Parse"
+
+	r := Rule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return Rule{}, fmt.Errorf("%s: %w: empty rule", op, ErrInvalidRule)
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return Rule{}, fmt.Errorf("%s: %w: malformed part %q", op, ErrInvalidRule, part)
+		}
+
+		var err error
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			freq, ok := frequencies[strings.ToUpper(value)]
+			if !ok {
+				err = fmt.Errorf("unsupported FREQ %q", value)
+			}
+			r.Freq = freq
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("INTERVAL must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = errors.New("COUNT must be positive")
+			}
+		case "UNTIL":
+			var until time.Time
+			until, err = parseUntil(value)
+			r.Until = &until
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseInts(value, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(value, 1, 12)
+			for _, m := range months {
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "WKST":
+			if value != "MO" {
+				err = errors.New("only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("unsupported part %q", key)
+		}
+
+		if err != nil {
+			return Rule{}, fmt.Errorf("%s: %w: %s", op, ErrInvalidRule, err.Error())
+		}
+	}
+
+	if r.Freq == 0 {
+		return Rule{}, fmt.Errorf("%s: %w: FREQ is required", op, ErrInvalidRule)
+	}
+
+	if r.Count > 0 && r.Until != nil {
+		return Rule{}, fmt.Errorf("%s: %w: COUNT and UNTIL are exclusive", op, ErrInvalidRule)
+	}
+
+	return r, nil
+}
+
+// String formats the rule back into its textual form.
+func (r Rule) String() string {
+	var parts []string
+
+	for name, freq := range frequencies {
+		if freq == r.Freq {
+			parts = append(parts, "FREQ="+name)
+		}
+	}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			day := ""
+			if wd.N != 0 {
+				day = strconv.Itoa(wd.N)
+			}
+			for name, d := range weekdays {
+				if d == wd.Day {
+					day += name
+				}
+			}
+			days = append(days, day)
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if len(r.ByMonthDay) > 0 {
+		parts = append(parts, "BYMONTHDAY="+joinInts(r.ByMonthDay))
+	}
+
+	if len(r.ByMonth) > 0 {
+		months := make([]int, 0, len(r.ByMonth))
+		for _, m := range r.ByMonth {
+			months = append(months, int(m))
+		}
+		parts = append(parts, "BYMONTH="+joinInts(months))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after current, which is
+// expected to be an occurrence itself. The rule is expanded in loc, so
+// the wall clock time of current is kept across DST changes.
+// False is returned when the series is over.
+func (r Rule) Next(current time.Time, loc *time.Location) (time.Time, bool) {
+	if r.Count == 1 {
+		return time.Time{}, false
+	}
+
+	current = current.In(loc)
+
+	interval := r.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	for i := 0; i < maxPeriods; i++ {
+		for _, candidate := range r.candidates(current, i*interval) {
+			if !candidate.After(current) {
+				continue
+			}
+
+			if r.Until != nil && candidate.After(*r.Until) {
+				return time.Time{}, false
+			}
+
+			return candidate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// candidates returns sorted occurrences of the period shifted by offset
+// frequency units from the period containing current.
+func (r Rule) candidates(current time.Time, offset int) []time.Time {
+	y, m, d := current.Date()
+	hh, mm, ss := current.Clock()
+	loc := current.Location()
+
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hh, mm, ss, 0, loc)
+	}
+
+	var days []time.Time
+
+	switch r.Freq {
+	case Daily:
+		day := at(y, m, d+offset)
+		if r.matchesMonth(day.Month()) && r.matchesWeekday(day) && r.matchesMonthDay(day) {
+			days = append(days, day)
+		}
+	case Weekly:
+		// weeks start on monday
+		monday := at(y, m, d-(int(current.Weekday())+6)%7+7*offset)
+		for i := 0; i < 7; i++ {
+			day := at(monday.Year(), monday.Month(), monday.Day()+i)
+			if !r.matchesMonth(day.Month()) {
+				continue
+			}
+
+			// without BYDAY the series repeats on the weekday it started on
+			if len(r.ByDay) == 0 && day.Weekday() != current.Weekday() || !r.matchesWeekday(day) {
+				continue
+			}
+
+			days = append(days, day)
+		}
+	case Monthly:
+		first := at(y, m+time.Month(offset), 1)
+		if r.matchesMonth(first.Month()) {
+			days = r.monthDays(first, d)
+		}
+	case Yearly:
+		months := r.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{m}
+		}
+		for _, month := range months {
+			days = append(days, r.monthDays(at(y+offset, month, 1), d)...)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+
+	return days
+}
+
+// monthDays expands BYMONTHDAY and BYDAY within the month starting at first,
+// defaulting to the day of month of the series.
+func (r Rule) monthDays(first time.Time, defaultDay int) []time.Time {
+	y, m, _ := first.Date()
+	hh, mm, ss := first.Clock()
+	loc := first.Location()
+	daysIn := time.Date(y, m+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	at := func(day int) time.Time {
+		return time.Date(y, m, day, hh, mm, ss, 0, loc)
+	}
+
+	var days []time.Time
+
+	if len(r.ByMonthDay) == 0 && len(r.ByDay) == 0 {
+		if defaultDay <= daysIn {
+			days = append(days, at(defaultDay))
+		}
+
+		return days
+	}
+
+	for day := 1; day <= daysIn; day++ {
+		t := at(day)
+
+		if len(r.ByMonthDay) > 0 && !r.matchesMonthDay(t) {
+			continue
+		}
+
+		if len(r.ByDay) > 0 && !r.matchesNthWeekday(t, daysIn) {
+			continue
+		}
+
+		days = append(days, t)
+	}
+
+	return days
+}
+
+func (r Rule) matchesMonth(month time.Month) bool {
+	if len(r.ByMonth) == 0 {
+		return true
+	}
+
+	for _, m := range r.ByMonth {
+		if m == month {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesWeekday(t time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+
+	for _, wd := range r.ByDay {
+		if wd.Day == t.Weekday() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesMonthDay(t time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+
+	daysIn := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+
+	for _, md := range r.ByMonthDay {
+		if md == t.Day() || md < 0 && daysIn+md+1 == t.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r Rule) matchesNthWeekday(t time.Time, daysIn int) bool {
+	for _, wd := range r.ByDay {
+		if wd.Day != t.Weekday() {
+			continue
+		}
+
+		switch {
+		case wd.N == 0:
+			return true
+		case wd.N > 0 && (t.Day()-1)/7+1 == wd.N:
+			return true
+		case wd.N < 0 && (daysIn-t.Day())/7+1 == -wd.N:
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseByDay(value string) ([]Weekday, error) {
+	var days []Weekday
+
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToUpper(strings.TrimSpace(item))
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+
+		day, ok := weekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", item)
+		}
+
+		wd := Weekday{Day: day}
+
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid BYDAY %q", item)
+			}
+			wd.N = n
+		}
+
+		days = append(days, wd)
+	}
+
+	return days, nil
+}
+
+func parseInts(value string, min int, max int) ([]int, error) {
+	var nums []int
+
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil || n == 0 || n < min || n > max {
+			return nil, fmt.Errorf("invalid value %q", item)
+		}
+		nums = append(nums, n)
+	}
+
+	return nums, nil
+}
+
+func joinInts(nums []int) string {
+	items := make([]string, 0, len(nums))
+	for _, n := range nums {
+		items = append(items, strconv.Itoa(n))
+	}
+
+	return strings.Join(items, ",")
+}
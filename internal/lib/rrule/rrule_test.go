@@ -0,0 +1,224 @@
+package rrule_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/lib/rrule"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Daily",
+			rule: "FREQ=DAILY",
+			want: "FREQ=DAILY",
+		},
+		{
+			name: "Prefix and interval",
+			rule: "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+			want: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE",
+		},
+		{
+			name: "Nth weekday",
+			rule: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+			want: "FREQ=MONTHLY;BYDAY=-1FR;COUNT=3",
+		},
+		{
+			name: "Until",
+			rule: "FREQ=YEARLY;BYMONTH=3;BYMONTHDAY=1;UNTIL=20301231T000000Z",
+			want: "FREQ=YEARLY;BYMONTHDAY=1;BYMONTH=3;UNTIL=20301231T000000Z",
+		},
+		{
+			name:    "Empty",
+			rule:    "",
+			wantErr: true,
+		},
+		{
+			name:    "No freq",
+			rule:    "INTERVAL=2",
+			wantErr: true,
+		},
+		{
+			name:    "Unknown freq",
+			rule:    "FREQ=HOURLY",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid day",
+			rule:    "FREQ=WEEKLY;BYDAY=XX",
+			wantErr: true,
+		},
+		{
+			name:    "Count with until",
+			rule:    "FREQ=DAILY;COUNT=2;UNTIL=20301231",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := rrule.Parse(tt.rule)
+			if tt.wantErr {
+				require.ErrorIs(t, err, rrule.ErrInvalidRule)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, r.String())
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	require.NoError(t, err)
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		rule    string
+		current time.Time
+		loc     *time.Location
+		want    time.Time
+		done    bool
+	}{
+		{
+			name:    "Daily",
+			rule:    "FREQ=DAILY",
+			current: time.Date(2024, 10, 31, 9, 0, 0, 0, moscow),
+			loc:     moscow,
+			want:    time.Date(2024, 11, 1, 9, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Every other day",
+			rule:    "FREQ=DAILY;INTERVAL=2",
+			current: time.Date(2024, 2, 28, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Weekdays skip weekend",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR",
+			current: time.Date(2024, 10, 18, 8, 30, 0, 0, moscow),
+			loc:     moscow,
+			want:    time.Date(2024, 10, 21, 8, 30, 0, 0, moscow),
+		},
+		{
+			name:    "Weekly keeps start weekday",
+			rule:    "FREQ=WEEKLY;INTERVAL=2",
+			current: time.Date(2024, 10, 16, 10, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 10, 30, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Weekly within the same week",
+			rule:    "FREQ=WEEKLY;BYDAY=MO,TH",
+			current: time.Date(2024, 10, 14, 10, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 10, 17, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Monthly last friday",
+			rule:    "FREQ=MONTHLY;BYDAY=-1FR",
+			current: time.Date(2024, 10, 25, 18, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 11, 29, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Monthly first monday",
+			rule:    "FREQ=MONTHLY;BYDAY=1MO",
+			current: time.Date(2024, 10, 7, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 11, 4, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Monthly skips short months",
+			rule:    "FREQ=MONTHLY",
+			current: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 3, 31, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Monthly last day",
+			rule:    "FREQ=MONTHLY;BYMONTHDAY=-1",
+			current: time.Date(2024, 1, 31, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Yearly",
+			rule:    "FREQ=YEARLY",
+			current: time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			want:    time.Date(2028, 2, 29, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Keeps wall clock across DST",
+			rule:    "FREQ=DAILY",
+			current: time.Date(2024, 10, 26, 9, 0, 0, 0, berlin),
+			loc:     berlin,
+			want:    time.Date(2024, 10, 27, 9, 0, 0, 0, berlin),
+		},
+		{
+			name:    "Expands in given location",
+			rule:    "FREQ=WEEKLY;BYDAY=MO",
+			current: time.Date(2024, 10, 20, 22, 0, 0, 0, time.UTC),
+			loc:     moscow,
+			want:    time.Date(2024, 10, 28, 1, 0, 0, 0, moscow),
+		},
+		{
+			name:    "Last of count",
+			rule:    "FREQ=DAILY;COUNT=1",
+			current: time.Date(2024, 10, 18, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			done:    true,
+		},
+		{
+			name:    "Past until",
+			rule:    "FREQ=DAILY;UNTIL=20241018T120000Z",
+			current: time.Date(2024, 10, 18, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			done:    true,
+		},
+		{
+			name:    "Never matches",
+			rule:    "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			current: time.Date(2024, 2, 1, 9, 0, 0, 0, time.UTC),
+			loc:     time.UTC,
+			done:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			r, err := rrule.Parse(tt.rule)
+			require.NoError(t, err)
+
+			next, ok := r.Next(tt.current, tt.loc)
+			require.Equal(t, !tt.done, ok)
+
+			if !tt.done {
+				assert.True(t, tt.want.Equal(next), "want %s, got %s", tt.want, next)
+			}
+		})
+	}
+}
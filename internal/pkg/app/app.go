@@ -2,18 +2,42 @@ package app
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"os"
 
+	"github.com/Muaz717/todo-app/internal/app/mailer"
+	"github.com/Muaz717/todo-app/internal/app/notifier"
+	adminsrv "github.com/Muaz717/todo-app/internal/app/services/admin"
+	apitokensrv "github.com/Muaz717/todo-app/internal/app/services/apitoken"
+	archivesrv "github.com/Muaz717/todo-app/internal/app/services/archive"
 	authService "github.com/Muaz717/todo-app/internal/app/services/auth"
+	commentsrv "github.com/Muaz717/todo-app/internal/app/services/comment"
 	itemsrv "github.com/Muaz717/todo-app/internal/app/services/item"
+	listsrv "github.com/Muaz717/todo-app/internal/app/services/list"
+	notificationsrv "github.com/Muaz717/todo-app/internal/app/services/notification"
+	remindersrv "github.com/Muaz717/todo-app/internal/app/services/reminder"
+	sharesrv "github.com/Muaz717/todo-app/internal/app/services/share"
+	tagsrv "github.com/Muaz717/todo-app/internal/app/services/tag"
+	workspacesrv "github.com/Muaz717/todo-app/internal/app/services/workspace"
 	"github.com/Muaz717/todo-app/internal/app/storage/postgres"
 	"github.com/Muaz717/todo-app/internal/config"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+	"github.com/Muaz717/todo-app/internal/lib/password"
 	httpapp "github.com/Muaz717/todo-app/internal/pkg/app/http"
+	keysapp "github.com/Muaz717/todo-app/internal/pkg/app/keys"
+	schedulerapp "github.com/Muaz717/todo-app/internal/pkg/app/scheduler"
+	trashapp "github.com/Muaz717/todo-app/internal/pkg/app/trash"
 )
 
+const envLocal = "local"
+
 type App struct {
-	HTTPSrv *httpapp.App
+	HTTPSrv     *httpapp.App
+	Scheduler   *schedulerapp.App
+	KeyRotation *keysapp.App
+	TrashPurge  *trashapp.App
 }
 
 func New(
@@ -27,12 +51,135 @@ func New(
 		panic(err)
 	}
 
-	authSrv := authService.New(log, storage, storage, cfg.TokenTTL)
-	itemSrv := itemsrv.New(log, storage, storage)
+	for _, email := range cfg.AdminEmails {
+		if err := storage.GrantAdmin(ctx, email); err != nil {
+			log.Warn("failed to grant admin role", slog.String("email", email), sl.Err(err))
+		}
+	}
+
+	keys, err := newKeys(cfg)
+	if err != nil {
+		log.Error("failed to init signing keys", sl.Err(err))
+		panic(err)
+	}
+
+	hasher, err := password.New(
+		cfg.Password.Algorithm,
+		password.Argon2idParams{
+			Memory:      cfg.Password.Argon2Memory,
+			Iterations:  cfg.Password.Argon2Iterations,
+			Parallelism: cfg.Password.Argon2Parallelism,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+		cfg.Password.BcryptCost,
+	)
+	if err != nil {
+		log.Error("failed to init password hasher", sl.Err(err))
+		panic(err)
+	}
+
+	policy, err := password.NewPolicy(cfg.Password.MinLength, cfg.Password.MaxLength, cfg.Password.BlocklistPath)
+	if err != nil {
+		log.Error("failed to load password policy", sl.Err(err))
+		panic(err)
+	}
+
+	mail, err := newMailer(log, cfg)
+	if err != nil {
+		log.Error("failed to init mailer", sl.Err(err))
+		panic(err)
+	}
+
+	authSrv := authService.New(
+		log,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		storage,
+		hasher,
+		policy,
+		keys,
+		mail,
+		authService.Config{
+			TokenTTL:         cfg.TokenTTL,
+			RefreshTTL:       cfg.RefreshTTL,
+			PasswordResetTTL: cfg.PasswordResetTTL,
+			VerificationTTL:  cfg.VerificationTTL,
+			AppURL:           cfg.AppURL,
+			AllowUnverified:  cfg.UnverifiedAccess != config.AccessNone,
+			Lockout: authService.LockoutPolicy{
+				AccountThreshold: cfg.Lockout.AccountThreshold,
+				IPThreshold:      cfg.Lockout.IPThreshold,
+				BaseDelay:        cfg.Lockout.BaseDelay,
+				MaxDelay:         cfg.Lockout.MaxDelay,
+				Window:           cfg.Lockout.Window,
+			},
+			TOTPIssuer:   cfg.TwoFactor.Issuer,
+			ChallengeTTL: cfg.TwoFactor.ChallengeTTL,
+		},
+	)
+	itemSrv := itemsrv.New(log, storage, storage, storage, storage, storage, storage)
+	listSrv := listsrv.New(log, storage, storage, storage, storage, storage)
+	tagSrv := tagsrv.New(log, storage, storage, storage, storage)
+	apiTokenSrv := apitokensrv.New(log, storage, storage, storage)
+	archiveSrv := archivesrv.New(log, storage, storage)
+	adminSrv := adminsrv.New(log, storage, storage, storage, authSrv)
+	shareSrv := sharesrv.New(log, storage, storage, storage, storage)
+	workspaceSrv := workspacesrv.New(log, storage, storage, storage, storage, storage)
+	commentSrv := commentsrv.New(log, storage, storage, storage, mail)
+	notificationSrv := notificationsrv.New(log, storage, storage)
+
+	reminderSrv := remindersrv.New(log, storage, newNotifier(log, cfg, mail), cfg.Scheduler.BatchSize)
 
-	httpApp := httpapp.New(ctx, log, *cfg, authSrv, itemSrv)
+	httpApp := httpapp.New(ctx, log, *cfg, keys, authSrv, itemSrv, listSrv, tagSrv, apiTokenSrv, authSrv, authSrv, archiveSrv, adminSrv, shareSrv, workspaceSrv, commentSrv, notificationSrv, storage, apiTokenSrv, storage)
+	schedulerApp := schedulerapp.New(ctx, log, reminderSrv, cfg.Scheduler.Interval, cfg.Scheduler.BatchSize)
 
 	return &App{
-		HTTPSrv: httpApp,
+		HTTPSrv:     httpApp,
+		Scheduler:   schedulerApp,
+		KeyRotation: keysapp.New(log, keys),
+		TrashPurge:  trashapp.New(ctx, log, itemSrv, cfg.Trash.Retention, cfg.Trash.PurgeInterval),
+	}
+}
+
+func newKeys(cfg *config.Config) (jwt.Keys, error) {
+	if cfg.JWT.Algorithm == "HS256" {
+		return jwt.NewHMAC(os.Getenv("MY_SECRET")), nil
+	}
+
+	// tokens signed by a replaced key have to stay valid until they expire
+	grace := max(cfg.JWT.GracePeriod, cfg.TokenTTL)
+
+	return jwt.NewKeySet(cfg.JWT.KeysDir, cfg.JWT.Algorithm, cfg.JWT.RotationInterval, grace)
+}
+
+// newMailer sends emails through SMTP unless running locally,
+// outside of the local env the SMTP relay is required.
+func newMailer(log *slog.Logger, cfg *config.Config) (mailer.Mailer, error) {
+	if cfg.Env == envLocal {
+		return mailer.NewLog(log), nil
+	}
+
+	if cfg.SMTP.Host == "" {
+		return nil, errors.New("smtp.host is required outside of the local env")
+	}
+
+	return mailer.NewSMTP(cfg.SMTP), nil
+}
+
+func newNotifier(log *slog.Logger, cfg *config.Config, mail mailer.Mailer) remindersrv.Notifier {
+	switch cfg.Scheduler.Notifier {
+	case "webhook":
+		return notifier.NewWebhook(cfg.Scheduler.WebhookURL)
+	case "smtp":
+		return notifier.NewEmail(mail)
+	default:
+		return notifier.NewLog(log)
 	}
 }
@@ -6,12 +6,27 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/account"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/admin"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/apitoken"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/archive"
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/auth"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/comment"
 	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/item"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/jwks"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/list"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/notification"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/share"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/tag"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/twofactor"
+	"github.com/Muaz717/todo-app/internal/app/http-server/handlers/workspace"
+	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/authorization"
 	"github.com/Muaz717/todo-app/internal/app/http-server/middleware/identification"
 	mwLogger "github.com/Muaz717/todo-app/internal/app/http-server/middleware/logger"
 
 	"github.com/Muaz717/todo-app/internal/config"
+	"github.com/Muaz717/todo-app/internal/domain/models"
+	"github.com/Muaz717/todo-app/internal/lib/jwt"
 	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -29,31 +44,212 @@ func New(
 	ctx context.Context,
 	log *slog.Logger,
 	cfg config.Config,
+	keys jwt.Keys,
 	authSrv auth.Auth,
 	itemSrv item.Item,
+	listSrv list.List,
+	tagSrv tag.Tag,
+	apiTokenSrv apitoken.APIToken,
+	twoFactorSrv twofactor.TwoFactor,
+	accountSrv account.Account,
+	archiveSrv archive.Archive,
+	adminSrv admin.Admin,
+	shareSrv share.Share,
+	workspaceSrv workspace.Workspace,
+	commentSrv comment.Comment,
+	notificationSrv notification.Notification,
+	roles authorization.RoleProvider,
+	tokenAuth identification.TokenAuthenticator,
+	users identification.UserChecker,
 ) *App {
 
 	authHandler := auth.New(ctx, log, authSrv)
 	itemHandler := item.New(ctx, log, itemSrv)
+	listHandler := list.New(ctx, log, listSrv)
+	tagHandler := tag.New(ctx, log, tagSrv)
+	jwksHandler := jwks.New(ctx, log, keys)
+	apiTokenHandler := apitoken.New(ctx, log, apiTokenSrv)
+	twoFactorHandler := twofactor.New(ctx, log, twoFactorSrv)
+	accountHandler := account.New(ctx, log, accountSrv)
+	archiveHandler := archive.New(ctx, log, archiveSrv, cfg.Archive.MaxImportSize)
+	adminHandler := admin.New(ctx, log, adminSrv)
+	shareHandler := share.New(ctx, log, shareSrv)
+	workspaceHandler := workspace.New(ctx, log, workspaceSrv)
+	commentHandler := comment.New(ctx, log, commentSrv)
+	notificationHandler := notification.New(ctx, log, notificationSrv)
 
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
+	if cfg.TrustProxy {
+		router.Use(middleware.RealIP)
+	}
 	router.Use(mwLogger.New(log))
 	router.Use(middleware.Recoverer)
 	router.Use(middleware.URLFormat)
 
+	router.Get("/.well-known/jwks.json", jwksHandler.JWKS)
+
 	router.Route("/auth", func(auth chi.Router) {
 		auth.Post("/sign-up", authHandler.RegisterNewUser)
 		auth.Post("/sign-in", authHandler.Login)
+		auth.Post("/refresh", authHandler.Refresh)
+		auth.Post("/logout", authHandler.Logout)
+		auth.Post("/password/forgot", authHandler.ForgotPassword)
+		auth.Post("/password/reset", authHandler.ResetPassword)
+		auth.Get("/verify", authHandler.VerifyEmail)
+		auth.Post("/verify/resend", authHandler.ResendVerification)
+		auth.With(identification.New(log, keys, tokenAuth, users), identification.RequireSession).
+			Post("/logout-all", authHandler.LogoutAll)
+
+		auth.Route("/2fa", func(tf chi.Router) {
+			tf.Post("/verify", authHandler.VerifyTwoFactor)
+
+			tf.Group(func(tf chi.Router) {
+				tf.Use(identification.New(log, keys, tokenAuth, users), identification.RequireSession)
+
+				tf.Post("/enroll", twoFactorHandler.Enroll)
+				tf.Post("/confirm", twoFactorHandler.Confirm)
+				tf.Post("/disable", twoFactorHandler.Disable)
+			})
+		})
 	})
 
 	router.Route("/api", func(api chi.Router) {
-		api.Use(identification.New(log))
+		api.Use(identification.New(log, keys, tokenAuth, users))
+		if cfg.UnverifiedAccess == config.AccessRead {
+			api.Use(identification.RequireVerifiedToWrite)
+		}
 
 		api.Route("/items", func(items chi.Router) {
+			items.Use(identification.RequireScope(models.ScopeItemsRead, models.ScopeItemsWrite))
+
 			items.Post("/", itemHandler.Create)
 			items.Get("/", itemHandler.AllItems)
+
+			items.Route("/{id}", func(item chi.Router) {
+				item.Get("/", itemHandler.Item)
+				item.Get("/history", itemHandler.History)
+				item.Put("/", itemHandler.Update)
+				item.Patch("/", itemHandler.Patch)
+				item.Delete("/", itemHandler.Delete)
+				item.Post("/complete", itemHandler.Complete)
+				item.Post("/reopen", itemHandler.Reopen)
+				item.Post("/restore", itemHandler.Restore)
+				item.Post("/tags/{tagId}", itemHandler.AttachTag)
+				item.Delete("/tags/{tagId}", itemHandler.DetachTag)
+				item.Post("/shares", shareHandler.ShareItem)
+				item.Get("/shares", shareHandler.ItemShares)
+				item.Post("/comments", commentHandler.Create)
+				item.Get("/comments", commentHandler.Comments)
+				item.Patch("/comments/{commentId}", commentHandler.Update)
+				item.Delete("/comments/{commentId}", commentHandler.Delete)
+			})
+		})
+
+		api.With(identification.RequireScope(models.ScopeItemsRead, models.ScopeItemsWrite)).
+			Get("/trash", itemHandler.Trash)
+
+		api.Route("/lists", func(lists chi.Router) {
+			lists.Use(identification.RequireScope(models.ScopeListsRead, models.ScopeListsWrite))
+
+			lists.Post("/", listHandler.Create)
+			lists.Get("/", listHandler.AllLists)
+
+			lists.Route("/{id}", func(list chi.Router) {
+				list.Get("/", listHandler.List)
+				list.Patch("/", listHandler.Update)
+				list.Delete("/", listHandler.Delete)
+				list.Get("/items", listHandler.Items)
+				list.Post("/shares", shareHandler.ShareList)
+				list.Get("/shares", shareHandler.ListShares)
+			})
+		})
+
+		api.Route("/tags", func(tags chi.Router) {
+			tags.Use(identification.RequireScope(models.ScopeTagsRead, models.ScopeTagsWrite))
+
+			tags.Post("/", tagHandler.Create)
+			tags.Get("/", tagHandler.AllTags)
+			tags.Patch("/{id}", tagHandler.Rename)
+			tags.Delete("/{id}", tagHandler.Delete)
+		})
+
+		api.Route("/tokens", func(tokens chi.Router) {
+			tokens.Use(identification.RequireSession)
+
+			tokens.Post("/", apiTokenHandler.Create)
+			tokens.Get("/", apiTokenHandler.AllTokens)
+			tokens.Delete("/{id}", apiTokenHandler.Revoke)
+		})
+
+		api.Route("/shares", func(shares chi.Router) {
+			shares.Use(identification.RequireSession)
+
+			shares.Get("/", shareHandler.Shares)
+			shares.Post("/{id}/accept", shareHandler.Accept)
+			shares.Post("/{id}/decline", shareHandler.Decline)
+			shares.Delete("/{id}", shareHandler.Delete)
+		})
+
+		api.Route("/workspaces", func(workspaces chi.Router) {
+			workspaces.Use(identification.RequireSession)
+
+			workspaces.Post("/", workspaceHandler.Create)
+			workspaces.Get("/", workspaceHandler.Workspaces)
+			workspaces.Get("/invitations", workspaceHandler.Invitations)
+			workspaces.Post("/invitations/{id}/accept", workspaceHandler.Accept)
+			workspaces.Post("/invitations/{id}/decline", workspaceHandler.Decline)
+
+			workspaces.Route("/{id}", func(ws chi.Router) {
+				ws.Get("/", workspaceHandler.Workspace)
+				ws.Patch("/", workspaceHandler.Rename)
+				ws.Delete("/", workspaceHandler.Delete)
+				ws.Get("/members", workspaceHandler.Members)
+				ws.Put("/members/{userId}/role", workspaceHandler.SetRole)
+				ws.Delete("/members/{userId}", workspaceHandler.RemoveMember)
+				ws.Post("/invitations", workspaceHandler.Invite)
+				ws.Get("/invitations", workspaceHandler.WorkspaceInvitations)
+				ws.Delete("/invitations/{invitationId}", workspaceHandler.RevokeInvitation)
+			})
+		})
+
+		api.Route("/notifications", func(notifications chi.Router) {
+			notifications.Use(identification.RequireSession)
+
+			notifications.Get("/", notificationHandler.Notifications)
+			notifications.Post("/read", notificationHandler.ReadAll)
+			notifications.Post("/{id}/read", notificationHandler.Read)
+		})
+
+		api.Route("/me", func(me chi.Router) {
+			me.Use(identification.RequireSession)
+
+			me.Get("/", accountHandler.Profile)
+			me.Patch("/", accountHandler.Update)
+			me.Delete("/", accountHandler.Delete)
+			me.Post("/password", accountHandler.ChangePassword)
+			me.Post("/email", accountHandler.ChangeEmail)
+			me.Get("/export", archiveHandler.Export)
+			me.Post("/import", archiveHandler.Import)
+		})
+	})
+
+	router.Route("/admin", func(adm chi.Router) {
+		adm.Use(
+			identification.New(log, keys, tokenAuth, users),
+			identification.RequireSession,
+			authorization.RequireRole(log, roles, models.RoleAdmin),
+		)
+
+		adm.Get("/users", adminHandler.Users)
+
+		adm.Route("/users/{id}", func(user chi.Router) {
+			user.Get("/", adminHandler.User)
+			user.Post("/disable", adminHandler.Disable)
+			user.Post("/enable", adminHandler.Enable)
+			user.Put("/role", adminHandler.SetRole)
+			user.Post("/password-reset", adminHandler.ForcePasswordReset)
 		})
 	})
 
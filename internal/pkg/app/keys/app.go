@@ -0,0 +1,85 @@
+package keysapp
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Muaz717/todo-app/internal/lib/logger/sl"
+)
+
+// checkInterval is how often the key directory is checked for due rotations
+// and keys created by other replicas.
+const checkInterval = time.Minute
+
+type Rotator interface {
+	Rotate(now time.Time) error
+}
+
+// App rotates signing keys until stopped, it does nothing
+// for keys that are not rotated.
+type App struct {
+	log      *slog.Logger
+	rotator  Rotator
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
+}
+
+// New returns the rotation app, keys are rotated only if they implement Rotator.
+// With a rotator Run must be called once for Stop to return.
+func New(log *slog.Logger, keys any) *App {
+	rotator, _ := keys.(Rotator)
+
+	a := &App{
+		log:     log,
+		rotator: rotator,
+		stop:    make(chan struct{}),
+	}
+
+	// added here and not in Run, so Stop waits even if Run has not started yet
+	if rotator != nil {
+		a.wg.Add(1)
+	}
+
+	return a
+}
+
+// Run blocks rotating keys until Stop is called.
+func (a *App) Run() {
+	const op = "keysapp.Run"
+
+	if a.rotator == nil {
+		return
+	}
+
+	defer a.wg.Done()
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("key rotation is running")
+
+	ticker := time.NewTicker(checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case now := <-ticker.C:
+			if err := a.rotator.Rotate(now); err != nil {
+				log.Error("failed to rotate keys", sl.Err(err))
+			}
+		}
+	}
+}
+
+func (a *App) Stop() {
+	const op = "keysapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping key rotation")
+
+	a.stopOnce.Do(func() { close(a.stop) })
+
+	a.wg.Wait()
+}
@@ -0,0 +1,97 @@
+package schedulerapp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Dispatcher interface {
+	Dispatch(ctx context.Context) (int, error)
+}
+
+// App periodically dispatches due reminders until stopped.
+type App struct {
+	ctx        context.Context
+	log        *slog.Logger
+	dispatcher Dispatcher
+	interval   time.Duration
+	batchSize  int
+	stop       chan struct{}
+	stopOnce   sync.Once
+	wg         sync.WaitGroup
+}
+
+// New returns the scheduler, Run must be called once for Stop to return.
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	dispatcher Dispatcher,
+	interval time.Duration,
+	batchSize int,
+) *App {
+	a := &App{
+		ctx:        ctx,
+		log:        log,
+		dispatcher: dispatcher,
+		interval:   interval,
+		batchSize:  batchSize,
+		stop:       make(chan struct{}),
+	}
+
+	// added here and not in Run, so Stop waits even if Run has not started yet
+	a.wg.Add(1)
+
+	return a
+}
+
+// Run blocks dispatching reminders every interval until Stop is called.
+func (a *App) Run() {
+	const op = "schedulerapp.Run"
+
+	defer a.wg.Done()
+
+	a.log.With(slog.String("op", op)).
+		Info("reminder scheduler is running", slog.Duration("interval", a.interval))
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			a.dispatch()
+		}
+	}
+}
+
+// dispatch drains due reminders batch by batch, a full batch
+// means more reminders may be waiting.
+func (a *App) dispatch() {
+	for {
+		sent, err := a.dispatcher.Dispatch(a.ctx)
+		if err != nil || sent < a.batchSize {
+			return
+		}
+
+		select {
+		case <-a.stop:
+			return
+		default:
+		}
+	}
+}
+
+// Stop waits for the batch in flight to finish and stops the scheduler.
+func (a *App) Stop() {
+	const op = "schedulerapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping reminder scheduler")
+
+	a.stopOnce.Do(func() { close(a.stop) })
+
+	a.wg.Wait()
+}
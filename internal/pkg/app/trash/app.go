@@ -0,0 +1,81 @@
+package trashapp
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type Purger interface {
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// App periodically deletes for good items kept in the trash longer than the retention.
+type App struct {
+	ctx       context.Context
+	log       *slog.Logger
+	purger    Purger
+	retention time.Duration
+	interval  time.Duration
+	stop      chan struct{}
+	stopOnce  sync.Once
+	wg        sync.WaitGroup
+}
+
+// New returns the purge app, Run must be called once for Stop to return.
+func New(
+	ctx context.Context,
+	log *slog.Logger,
+	purger Purger,
+	retention time.Duration,
+	interval time.Duration,
+) *App {
+	a := &App{
+		ctx:       ctx,
+		log:       log,
+		purger:    purger,
+		retention: retention,
+		interval:  interval,
+		stop:      make(chan struct{}),
+	}
+
+	// added here and not in Run, so Stop waits even if Run has not started yet
+	a.wg.Add(1)
+
+	return a
+}
+
+// Run blocks purging the trash every interval until Stop is called.
+func (a *App) Run() {
+	const op = "trashapp.Run"
+
+	defer a.wg.Done()
+
+	a.log.With(slog.String("op", op)).
+		Info("trash purge is running", slog.Duration("retention", a.retention), slog.Duration("interval", a.interval))
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case now := <-ticker.C:
+			// errors are logged by the purger, the next tick retries
+			_, _ = a.purger.Purge(a.ctx, now.Add(-a.retention))
+		}
+	}
+}
+
+// Stop waits for the purge in flight to finish and stops the app.
+func (a *App) Stop() {
+	const op = "trashapp.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping trash purge")
+
+	a.stopOnce.Do(func() { close(a.stop) })
+
+	a.wg.Wait()
+}
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS login_attempts
+(
+    key             TEXT PRIMARY KEY,
+    failures        INT NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    locked_until    TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS login_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    email      TEXT NOT NULL,
+    ip         TEXT NOT NULL DEFAULT '',
+    success    BOOLEAN NOT NULL,
+    reason     TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_login_events_email ON login_events (email, created_at);
+CREATE INDEX IF NOT EXISTS idx_login_events_ip ON login_events (ip, created_at);
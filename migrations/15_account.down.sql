@@ -0,0 +1,6 @@
+ALTER TABLE email_verifications
+    DROP COLUMN IF EXISTS new_email;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS display_name;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS display_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS locale       TEXT NOT NULL DEFAULT 'en';
+
+-- a verification with new_email confirms a change of the email address
+ALTER TABLE email_verifications
+    ADD COLUMN IF NOT EXISTS new_email TEXT;
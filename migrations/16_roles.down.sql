@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_users_email_trgm;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS disabled_at,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role        TEXT NOT NULL DEFAULT 'user'
+        CONSTRAINT users_role_check CHECK (role IN ('user', 'admin')),
+    ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_users_email_trgm ON users USING gin (email gin_trgm_ops);
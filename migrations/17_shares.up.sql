@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS shares
+(
+    id           BIGSERIAL PRIMARY KEY,
+    item_id      BIGINT,
+    list_id      BIGINT,
+    owner_id     BIGINT NOT NULL,
+    user_id      BIGINT NOT NULL,
+    role         TEXT NOT NULL CONSTRAINT shares_role_check CHECK (role IN ('viewer', 'editor')),
+    status       TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT shares_status_check CHECK (status IN ('pending', 'accepted', 'declined')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    responded_at TIMESTAMPTZ,
+    CONSTRAINT shares_target_check CHECK ((item_id IS NULL) <> (list_id IS NULL)),
+    CONSTRAINT items_shares_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT lists_shares_fk FOREIGN KEY (list_id) REFERENCES lists (id) ON DELETE CASCADE,
+    CONSTRAINT owners_shares_fk FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT users_shares_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_item_user ON shares (item_id, user_id) WHERE item_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_shares_list_user ON shares (list_id, user_id) WHERE list_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_shares_user_status ON shares (user_id, status);
@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_items_assignee;
+DROP INDEX IF EXISTS idx_items_workspace;
+ALTER TABLE items
+    DROP COLUMN IF EXISTS assignee_id,
+    DROP COLUMN IF EXISTS workspace_id;
+
+DROP INDEX IF EXISTS idx_lists_workspace_position;
+ALTER TABLE lists DROP COLUMN IF EXISTS workspace_id;
+
+DROP TABLE IF EXISTS workspace_invitations;
+DROP TABLE IF EXISTS workspace_members;
+DROP TABLE IF EXISTS workspaces;
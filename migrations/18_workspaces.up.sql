@@ -0,0 +1,69 @@
+CREATE TABLE IF NOT EXISTS workspaces
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       VARCHAR(255) NOT NULL,
+    owner_id   BIGINT NOT NULL,
+    personal   BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT users_workspaces_fk FOREIGN KEY (owner_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspaces_personal ON workspaces (owner_id) WHERE personal;
+
+CREATE TABLE IF NOT EXISTS workspace_members
+(
+    workspace_id BIGINT NOT NULL,
+    user_id      BIGINT NOT NULL,
+    role         TEXT NOT NULL
+        CONSTRAINT workspace_members_role_check CHECK (role IN ('owner', 'admin', 'member', 'guest')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (workspace_id, user_id),
+    CONSTRAINT workspaces_members_fk FOREIGN KEY (workspace_id) REFERENCES workspaces (id) ON DELETE CASCADE,
+    CONSTRAINT users_members_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_workspace_members_user ON workspace_members (user_id);
+
+CREATE TABLE IF NOT EXISTS workspace_invitations
+(
+    id           BIGSERIAL PRIMARY KEY,
+    workspace_id BIGINT NOT NULL,
+    user_id      BIGINT NOT NULL,
+    invited_by   BIGINT NOT NULL,
+    role         TEXT NOT NULL
+        CONSTRAINT workspace_invitations_role_check CHECK (role IN ('admin', 'member', 'guest')),
+    status       TEXT NOT NULL DEFAULT 'pending'
+        CONSTRAINT workspace_invitations_status_check CHECK (status IN ('pending', 'accepted', 'declined')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    responded_at TIMESTAMPTZ,
+    CONSTRAINT workspaces_invitations_fk FOREIGN KEY (workspace_id) REFERENCES workspaces (id) ON DELETE CASCADE,
+    CONSTRAINT users_invitations_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT inviters_invitations_fk FOREIGN KEY (invited_by) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_workspace_invitations_user ON workspace_invitations (workspace_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_workspace_invitations_status ON workspace_invitations (user_id, status);
+
+-- everyone gets a personal workspace holding what they had so far
+INSERT INTO workspaces (name, owner_id, personal)
+SELECT 'Personal', id, true FROM users
+ON CONFLICT DO NOTHING;
+
+INSERT INTO workspace_members (workspace_id, user_id, role)
+SELECT id, owner_id, 'owner' FROM workspaces
+ON CONFLICT DO NOTHING;
+
+ALTER TABLE lists ADD COLUMN IF NOT EXISTS workspace_id BIGINT;
+UPDATE lists SET workspace_id = w.id FROM workspaces w WHERE w.owner_id = lists.user_id AND w.personal;
+ALTER TABLE lists
+    ALTER COLUMN workspace_id SET NOT NULL,
+    ADD CONSTRAINT workspaces_lists_fk FOREIGN KEY (workspace_id) REFERENCES workspaces (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_lists_workspace_position ON lists (workspace_id, position);
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS workspace_id BIGINT,
+    ADD COLUMN IF NOT EXISTS assignee_id  BIGINT,
+    ADD CONSTRAINT users_assigned_items_fk FOREIGN KEY (assignee_id) REFERENCES users (id) ON DELETE SET NULL;
+UPDATE items SET workspace_id = w.id FROM workspaces w WHERE w.owner_id = items.user_id AND w.personal;
+ALTER TABLE items
+    ALTER COLUMN workspace_id SET NOT NULL,
+    ADD CONSTRAINT workspaces_items_fk FOREIGN KEY (workspace_id) REFERENCES workspaces (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_items_workspace ON items (workspace_id);
+CREATE INDEX IF NOT EXISTS idx_items_assignee ON items (assignee_id) WHERE assignee_id IS NOT NULL;
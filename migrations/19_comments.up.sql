@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS comments
+(
+    id         BIGSERIAL PRIMARY KEY,
+    item_id    BIGINT NOT NULL,
+    parent_id  BIGINT,
+    user_id    BIGINT NOT NULL,
+    body       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT items_comments_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT parents_comments_fk FOREIGN KEY (parent_id) REFERENCES comments (id) ON DELETE CASCADE,
+    CONSTRAINT users_comments_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_comments_item ON comments (item_id, id);
+
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    BIGINT NOT NULL,
+    actor_id   BIGINT NOT NULL,
+    kind       TEXT NOT NULL
+        CONSTRAINT notifications_kind_check CHECK (kind IN ('mention')),
+    item_id    BIGINT NOT NULL,
+    comment_id BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at    TIMESTAMPTZ,
+    CONSTRAINT users_notifications_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT actors_notifications_fk FOREIGN KEY (actor_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT items_notifications_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT comments_notifications_fk FOREIGN KEY (comment_id) REFERENCES comments (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, id);
+-- a comment mentions everyone once, editing it only notifies the newly mentioned
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_comment_user ON notifications (comment_id, user_id)
+    WHERE comment_id IS NOT NULL;
@@ -0,0 +1,17 @@
+-- item_events is an append-only history of item changes, rows are never updated
+CREATE TABLE IF NOT EXISTS item_events
+(
+    id         BIGSERIAL PRIMARY KEY,
+    item_id    BIGINT NOT NULL,
+    user_id    BIGINT,
+    kind       TEXT NOT NULL
+        CONSTRAINT item_events_kind_check CHECK (kind IN
+            ('created', 'updated', 'completed', 'reopened', 'tag_added', 'tag_removed', 'shared')),
+    field      TEXT NOT NULL DEFAULT '',
+    old_value  TEXT,
+    new_value  TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT items_item_events_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT users_item_events_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE SET NULL
+);
+CREATE INDEX IF NOT EXISTS idx_item_events_item ON item_events (item_id, id);
@@ -0,0 +1,11 @@
+DELETE FROM items WHERE deleted_at IS NOT NULL;
+DELETE FROM item_events WHERE kind IN ('deleted', 'restored');
+
+ALTER TABLE item_events
+    DROP CONSTRAINT IF EXISTS item_events_kind_check,
+    ADD CONSTRAINT item_events_kind_check CHECK (kind IN
+        ('created', 'updated', 'completed', 'reopened', 'tag_added', 'tag_removed', 'shared'));
+
+DROP INDEX IF EXISTS idx_items_deleted;
+ALTER TABLE items
+    DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,8 @@
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_items_deleted ON items (deleted_at) WHERE deleted_at IS NOT NULL;
+
+ALTER TABLE item_events
+    DROP CONSTRAINT IF EXISTS item_events_kind_check,
+    ADD CONSTRAINT item_events_kind_check CHECK (kind IN
+        ('created', 'updated', 'completed', 'reopened', 'tag_added', 'tag_removed', 'shared', 'deleted', 'restored'));
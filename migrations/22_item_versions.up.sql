@@ -0,0 +1,3 @@
+-- version grows with every change of an item, clients send it back to detect concurrent edits
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
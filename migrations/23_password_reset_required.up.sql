@@ -0,0 +1,3 @@
+-- set when an admin forces a password reset, sign-in is refused until the password is reset
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS password_reset_required BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,6 @@
+CREATE INDEX IF NOT EXISTS idx_items_workspace ON items (workspace_id);
+
+DROP INDEX IF EXISTS idx_items_workspace_title;
+DROP INDEX IF EXISTS idx_items_workspace_due_sort;
+DROP INDEX IF EXISTS idx_items_workspace_priority;
+DROP INDEX IF EXISTS idx_items_workspace_id;
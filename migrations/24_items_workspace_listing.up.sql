@@ -0,0 +1,8 @@
+-- workspace listings are ordered the same ways user listings are,
+-- (workspace_id, id) also covers lookups by workspace_id alone
+CREATE INDEX IF NOT EXISTS idx_items_workspace_id ON items (workspace_id, id);
+CREATE INDEX IF NOT EXISTS idx_items_workspace_priority ON items (workspace_id, priority, id);
+CREATE INDEX IF NOT EXISTS idx_items_workspace_due_sort ON items (workspace_id, (COALESCE(due_at, 'infinity'::timestamptz)), id);
+CREATE INDEX IF NOT EXISTS idx_items_workspace_title ON items (workspace_id, title, id);
+
+DROP INDEX IF EXISTS idx_items_workspace;
@@ -0,0 +1,4 @@
+DELETE FROM item_events e WHERE NOT EXISTS(SELECT 1 FROM items i WHERE i.id = e.item_id);
+
+ALTER TABLE item_events
+    ADD CONSTRAINT items_item_events_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+-- history outlives the item, item_id of a purged item keeps pointing to the id it had
+ALTER TABLE item_events
+    DROP CONSTRAINT IF EXISTS items_item_events_fk;
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_items_user_due;
+ALTER TABLE items
+    DROP COLUMN IF EXISTS priority,
+    DROP COLUMN IF EXISTS due_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS done;
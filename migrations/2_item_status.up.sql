@@ -0,0 +1,7 @@
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS done         BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS due_at       TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS priority     SMALLINT NOT NULL DEFAULT 0
+        CONSTRAINT items_priority_check CHECK (priority BETWEEN 0 AND 3);
+CREATE INDEX IF NOT EXISTS idx_items_user_due ON items (user_id, due_at);
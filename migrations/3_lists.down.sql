@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_items_list;
+ALTER TABLE items
+    DROP CONSTRAINT IF EXISTS lists_items_fk,
+    DROP COLUMN IF EXISTS list_id;
+DROP TABLE IF EXISTS lists;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS lists
+(
+    id       SERIAL NOT NULL UNIQUE PRIMARY KEY,
+    name     VARCHAR(255) NOT NULL,
+    color    VARCHAR(16) NOT NULL DEFAULT '',
+    position INTEGER NOT NULL DEFAULT 0,
+    archived BOOLEAN NOT NULL DEFAULT FALSE,
+    user_id  BIGINT NOT NULL,
+    CONSTRAINT users_lists_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_lists_user_position ON lists (user_id, position);
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS list_id BIGINT,
+    ADD CONSTRAINT lists_items_fk FOREIGN KEY (list_id) REFERENCES lists (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_items_list ON items (list_id);
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_items_description_trgm;
+DROP INDEX IF EXISTS idx_items_title_trgm;
+DROP INDEX IF EXISTS idx_items_user_title;
+DROP INDEX IF EXISTS idx_items_user_due_sort;
+DROP INDEX IF EXISTS idx_items_user_priority;
+DROP INDEX IF EXISTS idx_items_user_id;
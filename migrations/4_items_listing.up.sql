@@ -0,0 +1,8 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_items_user_id ON items (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_items_user_priority ON items (user_id, priority, id);
+CREATE INDEX IF NOT EXISTS idx_items_user_due_sort ON items (user_id, (COALESCE(due_at, 'infinity'::timestamptz)), id);
+CREATE INDEX IF NOT EXISTS idx_items_user_title ON items (user_id, title, id);
+CREATE INDEX IF NOT EXISTS idx_items_title_trgm ON items USING gin (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_items_description_trgm ON items USING gin (description gin_trgm_ops);
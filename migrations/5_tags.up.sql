@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS tags
+(
+    id      SERIAL NOT NULL UNIQUE PRIMARY KEY,
+    name    VARCHAR(64) NOT NULL,
+    user_id BIGINT NOT NULL,
+    CONSTRAINT users_tags_fk FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_user_name ON tags (user_id, lower(name));
+
+CREATE TABLE IF NOT EXISTS item_tags
+(
+    item_id BIGINT NOT NULL,
+    tag_id  BIGINT NOT NULL,
+    PRIMARY KEY (item_id, tag_id),
+    CONSTRAINT items_item_tags_fk FOREIGN KEY (item_id) REFERENCES items (id) ON DELETE CASCADE,
+    CONSTRAINT tags_item_tags_fk FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
+CREATE INDEX IF NOT EXISTS idx_item_tags_tag ON item_tags (tag_id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_items_parent_position;
+ALTER TABLE items
+    DROP CONSTRAINT IF EXISTS items_parent_fk,
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS parent_id;
@@ -0,0 +1,5 @@
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS parent_id BIGINT,
+    ADD COLUMN IF NOT EXISTS position  INTEGER NOT NULL DEFAULT 0,
+    ADD CONSTRAINT items_parent_fk FOREIGN KEY (parent_id) REFERENCES items (id) ON DELETE CASCADE;
+CREATE INDEX IF NOT EXISTS idx_items_parent_position ON items (parent_id, position);
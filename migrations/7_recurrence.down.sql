@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_items_series;
+ALTER TABLE items
+    DROP COLUMN IF EXISTS series_id,
+    DROP COLUMN IF EXISTS rrule;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,7 @@
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'UTC';
+
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS rrule     TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS series_id BIGINT;
+CREATE INDEX IF NOT EXISTS idx_items_series ON items (series_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_items_pending_reminders;
+ALTER TABLE items
+    DROP COLUMN IF EXISTS reminded_at,
+    DROP COLUMN IF EXISTS remind_at;
@@ -0,0 +1,5 @@
+ALTER TABLE items
+    ADD COLUMN IF NOT EXISTS remind_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_items_pending_reminders ON items (remind_at)
+    WHERE reminded_at IS NULL AND done = FALSE;
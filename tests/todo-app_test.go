@@ -71,7 +71,7 @@ func TestTodo_HappyPath(t *testing.T) {
 
 	const deltaSeconds = 1
 
-	time, err := time.ParseDuration("12h")
+	time, err := time.ParseDuration("15m")
 	assert.NoError(t, err)
 	// check if exp of token is in correct range, ttl get from st.Cfg.TokenTTL
 	assert.InDelta(t, loginTime.Add(time).Unix(), claims["exp"].(float64), deltaSeconds)